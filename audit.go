@@ -0,0 +1,85 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/artifact"
+	"github.com/yu-icchi/mu/pkg/audit"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+type auditOptions struct {
+	// sinks is the comma-separated list of summary, artifact, file, http and branch.
+	sinks     string
+	file      string
+	httpURL   string
+	httpToken string
+	branch    string
+}
+
+func auditOptionsFromInputs() *auditOptions {
+	return &auditOptions{
+		sinks:     action.Input("audit_sinks"),
+		file:      action.Input("audit_file"),
+		httpURL:   action.Input("audit_http_url"),
+		httpToken: cmp.Or(action.Input("audit_http_token"), os.Getenv("MU_AUDIT_HTTP_TOKEN")),
+		branch:    action.Input("audit_branch"),
+	}
+}
+
+// registerAuditFlags registers the flags of the audit sinks for the subcommands, which run outside GitHub Actions.
+// The token of the HTTP endpoint is read from MU_AUDIT_HTTP_TOKEN.
+func registerAuditFlags(flags *flag.FlagSet, opts *auditOptions) {
+	flags.StringVar(&opts.sinks, "audit", "", "comma-separated list of the audit sinks (file, http and branch)")
+	flags.StringVar(&opts.file, "audit-file", "", "JSON Lines file to append the audit records when audit has file")
+	flags.StringVar(&opts.httpURL, "audit-http-url", "", "endpoint to post the audit records when audit has http")
+	flags.StringVar(&opts.branch, "audit-branch", "audit", "branch to commit the audit records when audit has branch")
+	opts.httpToken = os.Getenv("MU_AUDIT_HTTP_TOKEN")
+}
+
+func newAuditSinks(opts *auditOptions, v vcs.VCS) ([]audit.Sink, error) {
+	var sinks []audit.Sink
+	for _, name := range strings.Split(opts.sinks, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "summary":
+			sinks = append(sinks, audit.NewSummary(action.New(os.Stdout)))
+		case "artifact":
+			if os.Getenv("ACTIONS_RUNTIME_TOKEN") == "" {
+				return nil, errors.New("audit artifact is only available in GitHub Actions")
+			}
+			name := fmt.Sprintf("mu-audit-%s-%s-%s",
+				os.Getenv("GITHUB_RUN_ID"), os.Getenv("GITHUB_RUN_ATTEMPT"), os.Getenv("GITHUB_JOB"))
+			client := artifact.New(&artifact.Params{
+				RuntimeToken: os.Getenv("ACTIONS_RUNTIME_TOKEN"),
+				ResultsURL:   os.Getenv("ACTIONS_RESULTS_URL"),
+			})
+			sinks = append(sinks, audit.NewArtifact(client, name, os.TempDir()))
+		case "file":
+			if opts.file == "" {
+				return nil, errors.New("invalid audit_file")
+			}
+			sinks = append(sinks, audit.NewFile(opts.file))
+		case "http":
+			if opts.httpURL == "" {
+				return nil, errors.New("invalid audit_http_url")
+			}
+			sinks = append(sinks, audit.NewHTTP(opts.httpURL, opts.httpToken, nil))
+		case "branch":
+			branch := opts.branch
+			if branch == "" {
+				branch = "audit"
+			}
+			sinks = append(sinks, audit.NewBranch(v, branch))
+		default:
+			return nil, fmt.Errorf("invalid audit sink: %s", name)
+		}
+	}
+	return sinks, nil
+}
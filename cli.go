@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/app"
+	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// cliOptions are the flags shared by `mu run` and `mu exec`, which run mu on a terminal outside GitHub Actions.
+// The GitHub token is read from MU_GITHUB_TOKEN or GITHUB_TOKEN.
+type cliOptions struct {
+	configPath              string
+	repo                    string
+	allowCommands           string
+	defaultTerraformVersion string
+	dryRun                  bool
+	botName                 string
+	storeOpts               *planStoreOptions
+	auditOpts               *auditOptions
+}
+
+func (o *cliOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.configPath, "config", ".github/mu.yaml", "file path of YAML manifest for mu")
+	flags.StringVar(&o.repo, "repo", "", "repository in the owner/repo format (default GITHUB_REPOSITORY)")
+	flags.StringVar(&o.allowCommands, "allow-commands", "plan,apply,unlock", "comma-separated list of allowed commands")
+	flags.StringVar(&o.defaultTerraformVersion, "default-terraform-version", "latest", "terraform version to default")
+	flags.BoolVar(&o.dryRun, "dry-run", false, "print the comments, labels and statuses instead of calling the GitHub API")
+	flags.StringVar(&o.botName, "bot-name", "", botNameUsage)
+	o.storeOpts = &planStoreOptions{}
+	registerPlanStoreFlags(flags, o.storeOpts, filepath.Join(os.TempDir(), "mu", "plans"), "directory to keep the plan files when plan-store is local")
+	o.auditOpts = &auditOptions{}
+	registerAuditFlags(flags, o.auditOpts)
+}
+
+func (o *cliOptions) execute(ctx context.Context, event vcs.Event, emojiReaction string) error {
+	owner, repo, ok := strings.Cut(o.repo, "/")
+	if !ok || owner == "" || repo == "" {
+		return fmt.Errorf("invalid repo: %q", o.repo)
+	}
+	token := os.Getenv("MU_GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		return errors.New("MU_GITHUB_TOKEN or GITHUB_TOKEN is required")
+	}
+	if o.storeOpts.backend == "github" {
+		return errors.New("plan-store github is only available in GitHub Actions")
+	}
+
+	gh, err := github.New(ctx, token, owner, repo, github.WithURLs(github.URLsFromEnv()))
+	if err != nil {
+		return fmt.Errorf("failed to setup github client: %w", err)
+	}
+	botName, err := resolveBotName(ctx, o.botName, token)
+	if err != nil {
+		return err
+	}
+	planStore, err := newPlanStore(gh, o.storeOpts)
+	if err != nil {
+		return err
+	}
+	var v vcs.VCS = gh
+	if o.dryRun {
+		v = vcs.NewDryRun(gh, os.Stdout)
+	}
+	auditSinks, err := newAuditSinks(o.auditOpts, v)
+	if err != nil {
+		return err
+	}
+	mu := app.New(&app.Params{
+		VCS:                     v,
+		PlanStore:               planStore,
+		Encrypter:               newEncrypter(""),
+		PlanSigningKey:          planSigningKey("", ""),
+		ConfigPath:              o.configPath,
+		DefaultTerraformVersion: o.defaultTerraformVersion,
+		AllowCommands:           strings.Split(strings.ToLower(o.allowCommands), ","),
+		DisableSummaryLog:       true,
+		EmojiReaction:           emojiReaction,
+		BotName:                 botName,
+		AuditSinks:              auditSinks,
+		Release: &app.Release{
+			Version: version,
+			Commit:  commit,
+			Date:    date,
+		},
+	})
+	return mu.ExecuteEvent(ctx, event)
+}
+
+// runRun runs `mu run --event-file payload.json`, which replays a saved event payload,
+// e.g. the GITHUB_EVENT_PATH file of a workflow run or the body of a webhook delivery.
+func runRun(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	opts := &cliOptions{}
+	opts.register(flags)
+	eventFile := flags.String("event-file", "", "file path of the event payload")
+	eventName := flags.String("event-name", os.Getenv("GITHUB_EVENT_NAME"), "name of the event (default detected from the payload)")
+	emojiReaction := flags.String("emoji-reaction", "", "emoji reaction")
+	if _, err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *eventFile == "" {
+		return errors.New("--event-file is required")
+	}
+	payload, err := os.ReadFile(*eventFile)
+	if err != nil {
+		return err
+	}
+	name := *eventName
+	if name == "" {
+		name = detectEventName(payload)
+	}
+	event, err := github.DecodeEvent(name, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", *eventFile, err)
+	}
+	if opts.repo == "" {
+		opts.repo = payloadRepository(payload)
+	}
+	if opts.repo == "" {
+		opts.repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	return opts.execute(ctx, github.ConvertEvent(event), *emojiReaction)
+}
+
+// runExec runs `mu exec "mu plan -p foo" --pr 123`, which runs the mu command as if it were commented on the pull request.
+func runExec(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	opts := &cliOptions{}
+	opts.register(flags)
+	prNum := flags.Int("pr", 0, "number of the pull request")
+	user := flags.String("user", "", "login of the user who runs the command, which is checked against the permissions of the projects")
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New(`usage: mu exec "mu plan -p <project>" --pr <number>`)
+	}
+	if *prNum <= 0 {
+		return errors.New("--pr is required")
+	}
+	if opts.repo == "" {
+		opts.repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	event := &vcs.CommentEvent{
+		Action:            vcs.Created,
+		PullRequestNumber: *prNum,
+		Body:              positional[0],
+		User:              *user,
+	}
+	return opts.execute(ctx, event, "")
+}
+
+// parseFlags parses flags placed before and after the positional arguments,
+// since flag.FlagSet stops at the first positional argument.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// detectEventName guesses the event name from the fields which only the payload of the event has.
+func detectEventName(payload []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return ""
+	}
+	has := func(key string) bool {
+		_, ok := fields[key]
+		return ok
+	}
+	switch {
+	case has("comment") && has("issue"):
+		return github.EventIssueComment
+	case has("review") && has("pull_request"):
+		return github.EventPullRequestReview
+	case has("pull_request"):
+		return github.EventPullRequest
+	case has("pusher"):
+		return github.EventPush
+	case has("inputs") && has("workflow"):
+		return github.EventDispatch
+	case has("schedule"):
+		return github.EventSchedule
+	default:
+		return ""
+	}
+}
+
+func payloadRepository(payload []byte) string {
+	var fields struct {
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return ""
+	}
+	return fields.Repository.FullName
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yu-icchi/mu/pkg/config"
+)
+
+var configSubcommands = map[string]func(ctx context.Context, args []string) error{
+	"show":     runConfigShow,
+	"validate": runConfigValidate,
+	"schema":   runConfigSchema,
+}
+
+// runConfig runs `mu config <subcommand>`, which works on the config file without GitHub.
+func runConfig(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: mu config show -p <project> | mu config validate | mu config schema")
+	}
+	run, ok := configSubcommands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown subcommand: mu config %s", args[0])
+	}
+	return run(ctx, args[1:])
+}
+
+// runConfigShow runs `mu config show -p <project>`, which prints the effective config of the project,
+// after the defaults, the templates and the workspaces are merged, to debug the inheritance.
+// Every project is printed without -p. The values of the secrets are printed as ***. The config is validated after it is printed.
+func runConfigShow(_ context.Context, args []string) error {
+	flags := flag.NewFlagSet("config show", flag.ContinueOnError)
+	configPath := flags.String("config", ".github/mu.yaml", "file path of YAML manifest for mu")
+	defaultTerraformVersion := flags.String("default-terraform-version", "latest", "terraform version to default")
+	var project, workspace string
+	flags.StringVar(&project, "p", "", "name of the project")
+	flags.StringVar(&project, "project", "", "name of the project")
+	flags.StringVar(&workspace, "w", "", "workspace of the project")
+	flags.StringVar(&workspace, "workspace", "", "workspace of the project")
+	if _, err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath, config.WithDefaultTerraformVersion(*defaultTerraformVersion))
+	if err != nil {
+		return err
+	}
+	projects := config.Projects(cfg.Projects)
+	if project != "" {
+		projects = cfg.GetProjects(project, workspace)
+		if len(projects) == 0 {
+			return fmt.Errorf("project %q is not found", project)
+		}
+	}
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(projects); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	out := buf.String()
+	for _, secret := range cfg.Secrets() {
+		out = strings.ReplaceAll(out, secret, "***")
+	}
+	if _, err := fmt.Fprint(os.Stdout, out); err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// runConfigValidate runs `mu config validate`, which reports every problem of the config file with its position,
+// including the dirs of the projects which do not exist, and fails if there is any problem.
+func runConfigValidate(_ context.Context, args []string) error {
+	flags := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	configPath := flags.String("config", ".github/mu.yaml", "file path of YAML manifest for mu")
+	if _, err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if err := errors.Join(cfg.Validate(), cfg.ValidateDirs()); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "%s is valid\n", *configPath)
+	return nil
+}
+
+// runConfigSchema runs `mu config schema`, which prints the JSON Schema of the config file for the editors.
+func runConfigSchema(_ context.Context, args []string) error {
+	flags := flag.NewFlagSet("config schema", flag.ContinueOnError)
+	output := flags.String("o", "", "file path to write the schema to instead of stdout")
+	if _, err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	schema, err := config.JSONSchema()
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err := os.Stdout.Write(schema)
+		return err
+	}
+	return os.WriteFile(*output, schema, 0o644)
+}
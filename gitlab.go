@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/app"
+	"github.com/yu-icchi/mu/pkg/gitlab"
+)
+
+// runGitlab runs `mu gitlab` in a job of GitLab CI, which handles the merge request pipelines.
+// The token is read from MU_GITLAB_TOKEN or GITLAB_TOKEN.
+func runGitlab(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("gitlab", flag.ContinueOnError)
+	configPath := flags.String("config", ".gitlab/mu.yaml", "file path of YAML manifest for mu")
+	allowCommands := flags.String("allow-commands", "plan,apply,unlock", "comma-separated list of allowed commands")
+	defaultTerraformVersion := flags.String("default-terraform-version", "latest", "terraform version to default")
+	emojiReaction := flags.String("emoji-reaction", "+1", "emoji reaction")
+	botName := flags.String("bot-name", "", "username of the token, which posts the notes")
+	driftProjects := flags.String("drift-projects", "", "comma-separated list of projects to check for drift (default all projects)")
+	storeOpts := &planStoreOptions{}
+	registerPlanStoreFlags(flags, storeOpts, ".mu/plans", "directory to keep the plan files when plan-store is local, e.g. a shared volume of the runners")
+	auditOpts := &auditOptions{}
+	registerAuditFlags(flags, auditOpts)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	params := gitlab.ParamsFromEnv()
+	if params.Token == "" {
+		return errors.New("MU_GITLAB_TOKEN or GITLAB_TOKEN is required")
+	}
+	if params.APIURL == "" || params.ProjectID == "" {
+		return errors.New("CI_API_V4_URL and CI_PROJECT_ID are required, run mu gitlab in GitLab CI")
+	}
+	if storeOpts.backend == "github" {
+		return errors.New("plan-store github is not available in GitLab CI")
+	}
+	planStore, err := newPlanStore(nil, storeOpts)
+	if err != nil {
+		return err
+	}
+	var projects []string
+	if *driftProjects != "" {
+		for _, project := range strings.Split(*driftProjects, ",") {
+			projects = append(projects, strings.TrimSpace(project))
+		}
+	}
+	v := gitlab.New(params)
+	auditSinks, err := newAuditSinks(auditOpts, v)
+	if err != nil {
+		return err
+	}
+	mu := app.New(&app.Params{
+		VCS:                     v,
+		PlanStore:               planStore,
+		Encrypter:               newEncrypter(""),
+		ConfigPath:              *configPath,
+		DefaultTerraformVersion: *defaultTerraformVersion,
+		AllowCommands:           strings.Split(strings.ToLower(*allowCommands), ","),
+		DisableSummaryLog:       true,
+		EmojiReaction:           *emojiReaction,
+		DriftProjects:           projects,
+		BotName:                 *botName,
+		AuditSinks:              auditSinks,
+		Release: &app.Release{
+			Version: version,
+			Commit:  commit,
+			Date:    date,
+		},
+	})
+	return mu.Execute(ctx)
+}
@@ -1,16 +1,24 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/yu-icchi/mu/pkg/action"
 	"github.com/yu-icchi/mu/pkg/app"
+	"github.com/yu-icchi/mu/pkg/archive"
+	"github.com/yu-icchi/mu/pkg/artifact"
+	"github.com/yu-icchi/mu/pkg/encryption"
 	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/planstore"
 )
 
 var (
@@ -19,24 +27,36 @@ var (
 	date    = "unknown"
 )
 
+var subcommands = map[string]func(ctx context.Context, args []string) error{
+	"server": runServer,
+	"run":    runRun,
+	"exec":   runExec,
+	"gitlab": runGitlab,
+	"config": runConfig,
+}
+
 func main() {
 	_, _ = fmt.Fprintln(os.Stdout, fmt.Sprintf("mu (version=%s, commit=%s, date=%s)", version, commit, date)) // nolint: gosimple
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(ctx, os.Args[2:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+				_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	owner := action.Owner()
 	repo := action.Repo()
-	token := action.Input("github_token")
-	if token == "" {
-		action.Failed("invalid github_token")
-	}
 	configPath := action.Input("config_path")
 	if configPath == "" {
 		action.Failed("invalid config_path")
 	}
-	uploadArtifactVersion := action.Input("upload_artifact_version")
-	uploadArtifactDir := action.Input("upload_artifact_dir")
 	defaultTerraformVersion := action.Input("default_terraform_version")
 	disableSummaryLog, err := strconv.ParseBool(action.Input("disable_summary_log"))
 	if err != nil {
@@ -44,20 +64,38 @@ func main() {
 	}
 	allowCommands := strings.Split(strings.ToLower(action.Input("allow_commands")), ",")
 	emojiReaction := action.Input("emoji_reaction")
-	gh, err := github.New(ctx, token, owner, repo)
+	var driftProjects []string
+	if input := action.Input("drift_projects"); input != "" {
+		for _, project := range strings.Split(input, ",") {
+			driftProjects = append(driftProjects, strings.TrimSpace(project))
+		}
+	}
+	gh, botName, err := newGithub(ctx, owner, repo)
+	if err != nil {
+		action.Failed(err.Error())
+	}
+	planStore, err := newPlanStore(gh, planStoreOptionsFromInputs())
 	if err != nil {
-		action.Failed("failed to setup github client")
+		action.Failed(err.Error())
+	}
+	auditSinks, err := newAuditSinks(auditOptionsFromInputs(), gh)
+	if err != nil {
+		action.Failed(err.Error())
 	}
 
 	params := &app.Params{
-		Github:                  gh,
+		VCS:                     gh,
+		PlanStore:               planStore,
+		Encrypter:               newEncrypter(action.Input("plan_encryption_key")),
+		PlanSigningKey:          planSigningKey(action.Input("plan_signing_key"), action.Input("plan_encryption_key")),
 		ConfigPath:              configPath,
 		DefaultTerraformVersion: defaultTerraformVersion,
-		UploadArtifactVersion:   uploadArtifactVersion,
-		UploadArtifactDir:       uploadArtifactDir,
 		AllowCommands:           allowCommands,
 		DisableSummaryLog:       disableSummaryLog,
 		EmojiReaction:           emojiReaction,
+		DriftProjects:           driftProjects,
+		BotName:                 cmp.Or(botName, github.ActionBotName),
+		AuditSinks:              auditSinks,
 		Release: &app.Release{
 			Version: version,
 			Commit:  commit,
@@ -69,3 +107,191 @@ func main() {
 		action.Failed(err.Error())
 	}
 }
+
+// newGithub authenticates as the GitHub App if app_id is set, otherwise with github_token.
+func newGithub(ctx context.Context, owner, repo string) (github.Github, string, error) {
+	botName := action.Input("bot_name")
+	urls := githubURLs()
+	appID := action.Input("app_id")
+	if appID == "" {
+		token := action.Input("github_token")
+		if token == "" {
+			return nil, "", errors.New("invalid github_token")
+		}
+		gh, err := github.New(ctx, token, owner, repo, github.WithURLs(urls))
+		if err != nil {
+			return nil, "", errors.New("failed to setup github client")
+		}
+		return gh, botName, nil
+	}
+
+	id, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil {
+		return nil, "", errors.New("invalid app_id")
+	}
+	var installationID int64
+	if input := action.Input("app_installation_id"); input != "" {
+		installationID, err = strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return nil, "", errors.New("invalid app_installation_id")
+		}
+	}
+	src, err := github.NewAppTokenSource(&github.AppParams{
+		AppID:          id,
+		PrivateKey:     []byte(action.Input("app_private_key")),
+		InstallationID: installationID,
+		Owner:          owner,
+		Repo:           repo,
+		APIURL:         urls.API,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid app_private_key: %w", err)
+	}
+	if botName == "" {
+		botName, err = src.BotName(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	gh, err := github.NewWithTokenSource(ctx, src.TokenSource(ctx), owner, repo, github.WithURLs(urls))
+	if err != nil {
+		return nil, "", errors.New("failed to setup github client")
+	}
+	return gh, botName, nil
+}
+
+const botNameUsage = "login of the token, which posts the comments, e.g. my-app[bot] for a GitHub App (default resolved from the token)"
+
+// resolveBotName returns the login of the token outside GitHub Actions, which is needed to hide the old comments of mu.
+// The token of a GitHub App cannot resolve it, so the bot name must be set for it.
+func resolveBotName(ctx context.Context, botName, token string) (string, error) {
+	if botName != "" {
+		return botName, nil
+	}
+	login, err := github.Login(ctx, token, github.URLsFromEnv())
+	if err != nil {
+		return "", fmt.Errorf("failed to get the login of the token, set --bot-name: %w", err)
+	}
+	return login, nil
+}
+
+// githubURLs returns the endpoints of GitHub Enterprise Server from the inputs, or the environment variables of the runner.
+func githubURLs() *github.URLs {
+	urls := github.URLsFromEnv()
+	urls.API = cmp.Or(action.Input("github_api_url"), urls.API)
+	urls.GraphQL = cmp.Or(action.Input("github_graphql_url"), urls.GraphQL)
+	urls.Upload = cmp.Or(action.Input("github_upload_url"), urls.Upload)
+	return urls
+}
+
+type planStoreOptions struct {
+	backend    string
+	dir        string
+	s3Bucket   string
+	s3Prefix   string
+	s3Region   string
+	s3Endpoint string
+}
+
+func planStoreOptionsFromInputs() *planStoreOptions {
+	return &planStoreOptions{
+		backend:    action.Input("plan_store"),
+		dir:        action.Input("plan_store_dir"),
+		s3Bucket:   action.Input("plan_store_s3_bucket"),
+		s3Prefix:   action.Input("plan_store_s3_prefix"),
+		s3Region:   action.Input("plan_store_s3_region"),
+		s3Endpoint: action.Input("plan_store_s3_endpoint"),
+	}
+}
+
+// registerPlanStoreFlags registers the flags of the plan store for the subcommands, which run outside GitHub Actions.
+func registerPlanStoreFlags(flags *flag.FlagSet, opts *planStoreOptions, defaultDir, dirUsage string) {
+	flags.StringVar(&opts.backend, "plan-store", "local", "backend to keep the plan files (local or s3)")
+	flags.StringVar(&opts.dir, "plan-store-dir", defaultDir, dirUsage)
+	flags.StringVar(&opts.s3Bucket, "plan-store-s3-bucket", "", "S3 bucket to keep the plan files. "+
+		"The credentials are read from the AWS_ACCESS_KEY_ID, AWS_WEB_IDENTITY_TOKEN_FILE or AWS_CONTAINER_CREDENTIALS_* environment variables")
+	flags.StringVar(&opts.s3Prefix, "plan-store-s3-prefix", "", "key prefix of the plan files in the S3 bucket")
+	flags.StringVar(&opts.s3Region, "plan-store-s3-region", "", "region of the S3 bucket")
+	flags.StringVar(&opts.s3Endpoint, "plan-store-s3-endpoint", "", "endpoint of an S3-compatible storage")
+}
+
+func newPlanStore(gh github.Github, opts *planStoreOptions) (planstore.PlanStore, error) {
+	archiver := archive.NewZipArchiver()
+	switch opts.backend {
+	case "", "github":
+		return planstore.NewGithub(&planstore.GithubParams{
+			Github: gh,
+			Artifact: artifact.New(&artifact.Params{
+				RuntimeToken: os.Getenv("ACTIONS_RUNTIME_TOKEN"),
+				ResultsURL:   os.Getenv("ACTIONS_RESULTS_URL"),
+			}),
+			Archiver: archiver,
+		}), nil
+	case "local":
+		if opts.dir == "" {
+			return nil, errors.New("invalid plan_store_dir")
+		}
+		return planstore.NewLocal(opts.dir, archiver), nil
+	case "s3":
+		if opts.s3Bucket == "" {
+			return nil, errors.New("invalid plan_store_s3_bucket")
+		}
+		region := opts.s3Region
+		if region == "" {
+			region = os.Getenv("AWS_REGION")
+		}
+		if region == "" {
+			region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+		if region == "" {
+			region = "us-east-1"
+		}
+		return planstore.NewS3(&planstore.S3Params{
+			Bucket:                          opts.s3Bucket,
+			Prefix:                          opts.s3Prefix,
+			Region:                          region,
+			Endpoint:                        opts.s3Endpoint,
+			AccessKeyID:                     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey:                 os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:                    os.Getenv("AWS_SESSION_TOKEN"),
+			WebIdentityTokenFile:            os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"),
+			RoleARN:                         os.Getenv("AWS_ROLE_ARN"),
+			RoleSessionName:                 os.Getenv("AWS_ROLE_SESSION_NAME"),
+			STSEndpoint:                     os.Getenv("AWS_ENDPOINT_URL_STS"),
+			ContainerCredentialsURL:         containerCredentialsURL(),
+			ContainerAuthorizationToken:     os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"),
+			ContainerAuthorizationTokenFile: os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"),
+			Archiver:                        archiver,
+		}), nil
+	default:
+		return nil, fmt.Errorf("invalid plan_store: %s", opts.backend)
+	}
+}
+
+// containerCredentialsURL returns the endpoint of the container credentials set by the container runtime.
+// AWS_CONTAINER_CREDENTIALS_RELATIVE_URI is relative to the ECS credentials endpoint.
+func containerCredentialsURL() string {
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); uri != "" {
+		return uri
+	}
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		return "http://169.254.170.2" + uri
+	}
+	return ""
+}
+
+// planSigningKey returns the key which signs the metadata of the plan files.
+// It falls back to MU_PLAN_SIGNING_KEY, then to the encryption key.
+func planSigningKey(key, encryptionKey string) string {
+	return cmp.Or(key, os.Getenv("MU_PLAN_SIGNING_KEY"), encryptionKey, os.Getenv("MU_PLAN_ENCRYPTION_KEY"))
+}
+
+func newEncrypter(key string) encryption.Encrypter {
+	if key == "" {
+		key = os.Getenv("MU_PLAN_ENCRYPTION_KEY")
+	}
+	if key == "" {
+		return nil
+	}
+	return encryption.NewAESGCM(key)
+}
@@ -32,13 +32,30 @@ func Owner() string {
 	return os.Getenv("GITHUB_REPOSITORY_OWNER")
 }
 
+// Actor returns the login of the user who triggered the workflow run.
+func Actor() string {
+	return os.Getenv("GITHUB_ACTOR")
+}
+
+func SHA() string {
+	return os.Getenv("GITHUB_SHA")
+}
+
+// ServerURL returns the URL of GitHub, which is the URL of GitHub Enterprise Server on GHES.
+func ServerURL() string {
+	if url := os.Getenv("GITHUB_SERVER_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return "https://github.com"
+}
+
 func RunURL() string {
 	repo := os.Getenv("GITHUB_REPOSITORY")
 	id := os.Getenv("GITHUB_RUN_ID")
 	if repo == "" || id == "" {
 		return ""
 	}
-	return fmt.Sprintf("https://github.com/%s/actions/runs/%s", repo, id)
+	return fmt.Sprintf("%s/%s/actions/runs/%s", ServerURL(), repo, id)
 }
 
 func LabelURL(label string) string {
@@ -46,7 +63,7 @@ func LabelURL(label string) string {
 	if repo == "" || label == "" {
 		return ""
 	}
-	return fmt.Sprintf("https://github.com/%s/labels/%s", repo, label)
+	return fmt.Sprintf("%s/%s/labels/%s", ServerURL(), repo, label)
 }
 
 type Action struct {
@@ -118,3 +135,13 @@ func (a *Action) StartGroup(title string) {
 func (a *Action) EndGroup() {
 	_, _ = fmt.Fprintln(a.stdout, "::endgroup::")
 }
+
+// AddMask masks the value in the logs of the workflow run. Each line of the value is masked, since the runner
+// masks the values line by line.
+func (a *Action) AddMask(value string) {
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			_, _ = fmt.Fprintf(a.stdout, "::add-mask::%s\n", line)
+		}
+	}
+}
@@ -1 +1,47 @@
 package action
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunURL(t *testing.T) {
+	tests := map[string]struct {
+		serverURL string
+		expected  string
+	}{
+		"github.com": {
+			serverURL: "",
+			expected:  "https://github.com/owner/repo/actions/runs/123",
+		},
+		"github enterprise server": {
+			serverURL: "https://ghe.example.com/",
+			expected:  "https://ghe.example.com/owner/repo/actions/runs/123",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GITHUB_SERVER_URL", tt.serverURL)
+			t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+			t.Setenv("GITHUB_RUN_ID", "123")
+			assert.Equal(t, tt.expected, RunURL())
+		})
+	}
+}
+
+func TestLabelURL(t *testing.T) {
+	t.Setenv("GITHUB_SERVER_URL", "https://ghe.example.com")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+	assert.Equal(t, "https://ghe.example.com/owner/repo/labels/mu_lock_test", LabelURL("mu_lock_test"))
+	assert.Empty(t, LabelURL(""))
+}
+
+func TestAction_AddMask(t *testing.T) {
+	buf := new(bytes.Buffer)
+	a := New(buf)
+	a.AddMask("s3cr3t")
+	a.AddMask("-----BEGIN KEY-----\nabc\n\n-----END KEY-----\n")
+	assert.Equal(t, "::add-mask::s3cr3t\n::add-mask::-----BEGIN KEY-----\n::add-mask::abc\n::add-mask::-----END KEY-----\n", buf.String())
+}
@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// executePushEvent applies the projects with `apply.mode: after_merge` that were changed by the merged pull request.
+// The plan is created against the merged commit and the results are posted back to the merged pull request.
+// Approval before apply can be enforced by GitHub environments protection rules on the workflow job.
+func (a *App) executePushEvent(ctx context.Context, event *vcs.PushEvent) error {
+	if !event.DefaultBranch {
+		return nil
+	}
+
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	sha := event.SHA
+	pr, err := a.vcs.FindMergedPullRequest(ctx, sha)
+	if err != nil {
+		if errors.Is(err, vcs.ErrNotFound) {
+			a.logger.Info("There is no merged pull request.", log.String("sha", sha))
+			return nil
+		}
+		return err
+	}
+
+	modifiedFiles, err := a.vcs.ListFiles(ctx, pr.Number)
+	if err != nil {
+		return err
+	}
+	projects := a.findAfterMergeProjects(cfg, modifiedFiles)
+	if len(projects) == 0 {
+		a.logger.Info("There is no project to apply after merge.")
+		return nil
+	}
+	auditCommand(ctx, command.ApplyType, nil)
+	auditSHA(ctx, sha)
+	reviews, err := a.vcs.ListReviews(ctx, pr.Number)
+	if err != nil {
+		return err
+	}
+
+	outputProjects := make(OutputProjects, 0, len(projects))
+	for _, project := range projects {
+		out, err := a.tfApplyAfterMerge(ctx, pr.Number, sha, project, reviews)
+		if err != nil {
+			return err
+		}
+		outputProjects = append(outputProjects, &OutputProject{
+			Name:      project.Name,
+			Dir:       project.Dir,
+			Workspace: project.Workspace,
+			Mode:      "apply",
+			Result:    out.result,
+			ActionURL: action.RunURL(),
+		})
+	}
+
+	if err := a.outputProjects(ctx, outputProjects); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *App) findAfterMergeProjects(cfg *config.Config, modifiedFiles []string) config.Projects {
+	projects := make(config.Projects, 0, len(cfg.Projects))
+	for _, project := range cfg.Projects {
+		if !project.Apply.IsAfterMerge() {
+			continue
+		}
+		if project.Plan.HasMatchedPaths(project.Dir, modifiedFiles) {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
+
+// tfApplyAfterMerge plans and applies the project in a single run.
+// The plan file is not stored in the Actions Artifacts since it is applied right away.
+func (a *App) tfApplyAfterMerge(
+	ctx context.Context, prNum int, sha string, projectCfg *config.Project, reviews vcs.Reviews,
+) (out *outputApply, err error) {
+	defer a.lockProject(projectCfg)()
+	auditProject(ctx, projectCfg)
+
+	defer func() {
+		rec := recover()
+		if err == nil && rec == nil {
+			return
+		}
+		if rec != nil {
+			err = fmt.Errorf("%w: %s", errPanicOccurred, rec)
+			a.logger.Debug(fmt.Sprintf("apply after merge: %+v", rec))
+		}
+		if err := a.updateFailureStatus(ctx, sha, projectCfg.ID(), command.ApplyType); err != nil {
+			a.logger.Error("failed to update status", log.Error(err))
+		}
+	}()
+
+	if requireApprovals := projectCfg.Apply.GetRequireApprovals(); requireApprovals > 0 {
+		if approvals := reviews.Approves(); requireApprovals > approvals {
+			msg := fmt.Sprintf(":x: At least %d approvals are required before applying the `%s` project after merge.",
+				requireApprovals, projectCfg.Name)
+			if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("not enough approve: require_approvals: %d, count: %d: %w",
+				requireApprovals, approvals, errApprovalsRequired)
+		}
+	}
+
+	if err := a.updatePendingStatus(ctx, sha, projectCfg.ID(), command.ApplyType); err != nil {
+		return nil, err
+	}
+
+	tf := a.genTerraform(projectCfg)
+	if err := tf.Setup(ctx); err != nil {
+		return nil, err
+	}
+	if err := tf.CompareVersion(ctx, projectCfg.Terraform.GetVersion()); err != nil {
+		return nil, err
+	}
+	if err := tf.SwitchWorkspace(ctx, projectCfg.Workspace); err != nil {
+		return nil, err
+	}
+
+	filename := a.genPlanFilename(projectCfg.Name, projectCfg.Workspace, prNum)
+	hooks := &hookParams{
+		prNum:    prNum,
+		sha:      sha,
+		project:  projectCfg,
+		planFile: filepath.Join(a.projectDir(projectCfg), filename),
+	}
+	var (
+		steps   []*stepOutput
+		planRet *terraform.Output
+	)
+	for _, step := range projectCfg.PlanSteps() {
+		switch step.Type() {
+		case config.StepInit:
+			if err := a.tfInit(ctx, tf, hooks, step.GetExtraArgs(), nil); err != nil {
+				return nil, err
+			}
+		case config.StepRun:
+			stepOut, err := a.runStep(ctx, hooks, step.Run)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, stepOut)
+			if stepOut.hasError {
+				return nil, a.outputStepFailedResult(ctx, prNum, muApplyMeta, projectCfg, steps)
+			}
+		case config.StepPlan:
+			if err := a.runHooks(ctx, config.HookPrePlan, hooks); err != nil {
+				return nil, err
+			}
+			a.action.StartGroup(fmt.Sprintf("mu plan --project=%s --workspace=%s", projectCfg.Name, projectCfg.Workspace))
+			planRet, err = tf.Plan(ctx, &terraform.PlanParams{
+				Vars:      projectCfg.Terraform.GetVars(),
+				VarFiles:  projectCfg.Terraform.GetVarFiles(),
+				Out:       filename,
+				ExtraArgs: step.GetExtraArgs(),
+			}, terraform.WithStream(os.Stdout))
+			_, _ = fmt.Fprintln(os.Stdout)
+			a.action.EndGroup()
+			if err != nil {
+				return nil, err
+			}
+			if !a.disableSummaryLog {
+				a.outputPlanSummary(projectCfg, planRet.RawLog)
+			}
+			if planRet.HasError {
+				if err := a.outputPlanFailedResult(ctx, prNum, projectCfg, planRet); err != nil {
+					return nil, err
+				}
+				return nil, errPlanFailed
+			}
+			if err := a.runPostHooks(ctx, config.HookPostPlan, hooks); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !planRet.HasChanges {
+		msg := fmt.Sprintf("%s\n:white_check_mark: No changes. The `%s` project is up-to-date.\n\n%s",
+			muApplyMeta, projectCfg.Name, action.RunURL())
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+			return nil, err
+		}
+		if err := a.updateSuccessStatus(ctx, sha, projectCfg.ID(), command.ApplyType, planRet); err != nil {
+			return nil, err
+		}
+		return &outputApply{
+			result: planRet.Result,
+		}, nil
+	}
+
+	applyRet, err := a.runApplySteps(ctx, tf, hooks, filename, true, steps, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.updateSuccessStatus(ctx, sha, projectCfg.ID(), command.ApplyType, applyRet); err != nil {
+		return nil, err
+	}
+	return &outputApply{
+		result: applyRet.Result,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_findAfterMergeProjects(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Projects: []*config.Project{
+			{
+				Name: "before",
+				Dir:  "before",
+				Plan: &config.Plan{Paths: []string{"*.tf"}},
+			},
+			{
+				Name:  "after",
+				Dir:   "after",
+				Plan:  &config.Plan{Paths: []string{"*.tf"}},
+				Apply: &config.Apply{Mode: config.ApplyModeAfterMerge},
+			},
+			{
+				Name:  "unchanged",
+				Dir:   "unchanged",
+				Plan:  &config.Plan{Paths: []string{"*.tf"}},
+				Apply: &config.Apply{Mode: config.ApplyModeAfterMerge},
+			},
+		},
+	}
+	app := &App{}
+	projects := app.findAfterMergeProjects(cfg, []string{"before/main.tf", "after/main.tf"})
+	require.Len(t, projects, 1)
+	assert.Equal(t, "after", projects[0].Name)
+}
+
+func TestApp_tfApplyAfterMerge(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	const (
+		actionURL = "https://github.com/test/mu/actions/runs/test-run-id"
+		src       = "mu/apply: test"
+	)
+	project := &config.Project{
+		Name:      "test",
+		Dir:       "./testdata",
+		Workspace: "default",
+		Terraform: &config.Terraform{
+			Version: "1.9.1",
+		},
+		Plan: &config.Plan{
+			Paths: []string{"*.tf*"},
+		},
+		Apply: &config.Apply{
+			Mode: config.ApplyModeAfterMerge,
+		},
+	}
+	prepareInit := func(ctx context.Context, m *mock) {
+		m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+			Sha:       "test-merge-sha",
+			Status:    vcs.PendingStatus,
+			TargetURL: actionURL,
+			Desc:      "in progress...",
+			Context:   src,
+		}).Return(nil)
+		m.terraform.EXPECT().Setup(ctx).Return(nil)
+		m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+		m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
+		m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).Return(&terraform.Output{RawLog: "init log"}, nil)
+	}
+	tests := []struct {
+		name      string
+		cfg       *config.Project
+		reviews   vcs.Reviews
+		prepare   prepare
+		expect    *outputApply
+		expectErr error
+	}{
+		{
+			name:    "success",
+			cfg:     project,
+			reviews: vcs.Reviews{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Plan(ctx, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params *terraform.PlanParams, _ ...terraform.Option) (*terraform.Output, error) {
+						assert.Equal(t, "test_default_1.tfplan", params.Out)
+						return &terraform.Output{
+							Result:     "Plan: 1 to add, 0 to change, 0 to destroy.",
+							HasChanges: true,
+							RawLog:     "plan log",
+						}, nil
+					})
+				m.terraform.EXPECT().Apply(ctx, &terraform.ApplyParams{
+					PlanFilePath: "test_default_1.tfplan",
+				}, gomock.Any()).Return(&terraform.Output{
+					Result: "apply result",
+					RawLog: "apply log",
+				}, nil)
+				m.terraform.EXPECT().Output(ctx).Return(&terraform.OutputsOutput{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "apply result")
+						return nil
+					})
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha:       "test-merge-sha",
+					Status:    vcs.SuccessStatus,
+					TargetURL: actionURL,
+					Desc:      "Apply succeeded.",
+					Context:   src,
+				}).Return(nil)
+			},
+			expect: &outputApply{
+				result: "apply result",
+			},
+		},
+		{
+			name:    "no changes",
+			cfg:     project,
+			reviews: vcs.Reviews{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Plan(ctx, gomock.Any(), gomock.Any()).Return(&terraform.Output{
+					Result:       "No changes.",
+					HasNoChanges: true,
+					RawLog:       "plan log",
+				}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "No changes. The `test` project is up-to-date.")
+						return nil
+					})
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha:       "test-merge-sha",
+					Status:    vcs.SuccessStatus,
+					TargetURL: actionURL,
+					Desc:      "Apply succeeded.",
+					Context:   src,
+				}).Return(nil)
+			},
+			expect: &outputApply{
+				result: "No changes.",
+			},
+		},
+		{
+			name:    "plan failed",
+			cfg:     project,
+			reviews: vcs.Reviews{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Plan(ctx, gomock.Any(), gomock.Any()).Return(&terraform.Output{
+					Result:   "plan error",
+					HasError: true,
+					RawLog:   "plan log",
+				}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha:       "test-merge-sha",
+					Status:    vcs.FailureStatus,
+					TargetURL: actionURL,
+					Desc:      "failed.",
+					Context:   src,
+				}).Return(nil)
+			},
+			expectErr: errPlanFailed,
+		},
+		{
+			name: "approvals required",
+			cfg: &config.Project{
+				Name:      "test",
+				Dir:       "./testdata",
+				Workspace: "default",
+				Plan: &config.Plan{
+					Paths: []string{"*.tf*"},
+				},
+				Apply: &config.Apply{
+					Mode:             config.ApplyModeAfterMerge,
+					RequireApprovals: 1,
+				},
+			},
+			reviews: vcs.Reviews{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha:       "test-merge-sha",
+					Status:    vcs.FailureStatus,
+					TargetURL: actionURL,
+					Desc:      "failed.",
+					Context:   src,
+				}).Return(nil)
+			},
+			expectErr: errApprovalsRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, mock, t)
+			out, err := app.tfApplyAfterMerge(ctx, 1, "test-merge-sha", tt.cfg, tt.reviews)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, out)
+		})
+	}
+}
@@ -1,18 +1,25 @@
 package app
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/yu-icchi/mu/pkg/action"
-	"github.com/yu-icchi/mu/pkg/archive"
+	"github.com/yu-icchi/mu/pkg/audit"
 	"github.com/yu-icchi/mu/pkg/command"
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/encryption"
 	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/planstore"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 type Release struct {
@@ -23,105 +30,161 @@ type Release struct {
 
 type App struct {
 	terraform               terraform.Terraform
-	github                  github.Github
+	vcs                     vcs.VCS
 	action                  *action.Action
-	archiver                archive.Archive
+	planStore               planstore.PlanStore
+	encrypter               encryption.Encrypter
+	planSigningKey          string
 	configPath              string
 	defaultTerraformVersion string
-	uploadArtifactVersion   string
-	uploadArtifactDir       string
 	allowCommands           []string
 	logger                  log.Logger
 	disableSummaryLog       bool
 	emojiReaction           string
+	driftProjects           []string
 	release                 *Release
+	workDir                 string
+	configDir               string
+	projectLocker           ProjectLocker
+	botName                 string
+	auditSinks              []audit.Sink
+}
+
+// ProjectLocker serializes the operations on the same project within the process.
+// It is used by `mu server`, which handles the events concurrently.
+type ProjectLocker interface {
+	// Lock blocks until the lock of key is acquired, and returns the function to release it.
+	Lock(key string) (unlock func())
 }
 
 type Params struct {
-	Github                  github.Github
+	VCS       vcs.VCS
+	PlanStore planstore.PlanStore
+	// Encrypter encrypts the plan files before they are stored. The plan files are stored in plaintext if it is nil.
+	Encrypter encryption.Encrypter
+	// PlanSigningKey signs the metadata of the plan files, so that tampered plan files are refused at apply.
+	// The plan files are not signed if it is empty.
+	PlanSigningKey          string
 	ConfigPath              string
 	DefaultTerraformVersion string
-	UploadArtifactVersion   string
-	UploadArtifactDir       string
 	AllowCommands           []string
 	DisableSummaryLog       bool
 	EmojiReaction           string
+	DriftProjects           []string
 	Release                 *Release
+	// WorkDir is the directory where the repository is checked out. The current directory is used if it is empty.
+	WorkDir string
+	// ConfigDir is the directory where the trusted revision of the repository, e.g. the default branch, is checked out.
+	// The fragments and the Terragrunt units of the config are read from it instead of WorkDir, and ConfigPath must be under it.
+	ConfigDir     string
+	ProjectLocker ProjectLocker
+	// BotName is the login of the bot which posts the comments of mu, e.g. the slug of the GitHub App.
+	BotName string
+	// AuditSinks receive the audit records of every command. No audit record is written if it is empty.
+	AuditSinks []audit.Sink
 }
 
 func New(params *Params) *App {
 	return &App{
 		terraform:               nil,
-		github:                  params.Github,
+		vcs:                     params.VCS,
 		action:                  action.New(os.Stdout),
-		archiver:                archive.NewZipArchiver(),
+		planStore:               params.PlanStore,
+		encrypter:               params.Encrypter,
+		planSigningKey:          params.PlanSigningKey,
 		configPath:              params.ConfigPath,
 		defaultTerraformVersion: params.DefaultTerraformVersion,
-		uploadArtifactVersion:   params.UploadArtifactVersion,
-		uploadArtifactDir:       params.UploadArtifactDir,
 		allowCommands:           params.AllowCommands,
 		logger:                  log.New(os.Stdout),
 		disableSummaryLog:       params.DisableSummaryLog,
 		emojiReaction:           params.EmojiReaction,
+		driftProjects:           params.DriftProjects,
 		release:                 params.Release,
+		workDir:                 params.WorkDir,
+		configDir:               params.ConfigDir,
+		projectLocker:           params.ProjectLocker,
+		botName:                 params.BotName,
+		auditSinks:              params.AuditSinks,
 	}
 }
 
 func (a *App) Execute(ctx context.Context) error {
-	event, err := a.github.Event()
+	event, err := a.vcs.Event()
 	if err != nil {
 		return err
 	}
+	return a.ExecuteEvent(ctx, event)
+}
+
+// ExecuteEvent runs mu for the event, which is decoded from a workflow event or a webhook payload.
+// The audit records of the command are written to the audit sinks when it returns.
+func (a *App) ExecuteEvent(ctx context.Context, event vcs.Event) (err error) {
+	if len(a.auditSinks) > 0 && event != nil {
+		rec := newAuditRecorder(event)
+		ctx = context.WithValue(ctx, auditRecorderKey{}, rec)
+		defer func() {
+			a.writeAudit(ctx, rec, err)
+		}()
+	}
+
 	switch e := event.(type) {
-	case *github.PullRequestEvent:
+	case *vcs.PullRequestEvent:
 		return a.executePullRequestEvent(ctx, e)
-	case *github.IssueCommentEvent:
-		return a.executeIssueCommentEvent(ctx, e)
+	case *vcs.CommentEvent:
+		return a.executeCommentEvent(ctx, e)
+	case *vcs.ReviewEvent:
+		return a.executeReviewEvent(ctx, e)
+	case *vcs.DriftEvent:
+		return a.executeDriftDetection(ctx)
+	case *vcs.PushEvent:
+		return a.executePushEvent(ctx, e)
 	default:
 		return nil
 	}
 }
 
-func (a *App) executePullRequestEvent(ctx context.Context, event *github.PullRequestEvent) error {
-	eventAction := event.GetAction()
+func (a *App) executePullRequestEvent(ctx context.Context, event *vcs.PullRequestEvent) error {
+	eventAction := event.Action
 	switch eventAction {
-	case github.Opened, github.Synchronize, github.Reopened, github.Closed:
+	case vcs.Opened, vcs.Synchronize, vcs.Reopened, vcs.Closed:
 	default:
 		return nil
 	}
 
-	cfg, err := config.Load(a.configPath, config.WithDefaultTerraformVersion(a.defaultTerraformVersion))
-	if err != nil {
-		return err
+	prNum := event.Number()
+	cfg, err := a.loadConfig()
+	if eventAction != vcs.Closed {
+		err = a.checkModifiedConfig(ctx, prNum, cfg, err)
 	}
-	if err := cfg.Validate(); err != nil {
+	if err != nil {
 		return err
 	}
 
-	prNum := event.Number()
-	pr, err := a.github.GetPullRequest(ctx, prNum)
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
 	if err != nil {
 		return err
 	}
-	if eventAction == github.Closed {
+	auditSHA(ctx, pr.HeadSHA)
+	if eventAction == vcs.Closed {
+		auditCommand(ctx, command.UnlockType, nil)
 		return a.executeUnlock(ctx, prNum, cfg, &command.Unlock{})
 	}
+	auditCommand(ctx, command.PlanType, nil)
 	return a.executeTerraformAutoPlan(ctx, prNum, pr.HeadSHA, cfg)
 }
 
-func (a *App) executeIssueCommentEvent(ctx context.Context, event *github.IssueCommentEvent) error {
-	if event.GetAction() != github.Created {
+func (a *App) executeCommentEvent(ctx context.Context, event *vcs.CommentEvent) error {
+	if event.Action != vcs.Created {
 		return nil
 	}
 
-	comment := event.GetComment()
-	muCmd, err := command.Parse(comment.GetBody())
+	muCmd, err := command.Parse(event.Body)
 	if err != nil {
 		return nil
 	}
 
-	if a.emojiReaction != "" {
-		if err := a.github.CreateIssueCommentReaction(ctx, comment.GetID(), a.emojiReaction); err != nil {
+	if a.emojiReaction != "" && event.CommentID != 0 {
+		if err := a.vcs.CreateCommentReaction(ctx, event.CommentID, a.emojiReaction); err != nil {
 			return err
 		}
 	}
@@ -130,13 +193,14 @@ func (a *App) executeIssueCommentEvent(ctx context.Context, event *github.IssueC
 
 	if muCmd.Type() != command.HelpType && !slices.Contains(a.allowCommands, string(muCmd.Type())) {
 		msg := a.unknownCommandMessage(string(muCmd.Type()), a.allowCommands)
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
 	}
+	auditCommand(ctx, muCmd.Type(), commentArgs(event.Body))
 
-	pr, err := a.github.GetPullRequest(ctx, prNum)
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
 	if err != nil {
 		return err
 	}
@@ -144,12 +208,13 @@ func (a *App) executeIssueCommentEvent(ctx context.Context, event *github.IssueC
 		return fmt.Errorf("conflict: %s", pr.MergeableState)
 	}
 	sha := pr.HeadSHA
+	auditSHA(ctx, sha)
 
-	cfg, err := config.Load(a.configPath, config.WithDefaultTerraformVersion(a.defaultTerraformVersion))
+	cfg, err := a.loadConfig()
 	if err != nil {
 		return err
 	}
-	if err := cfg.Validate(); err != nil {
+	if err := a.authorize(ctx, prNum, pr.BaseRef, event.User, cfg, muCmd); err != nil {
 		return err
 	}
 
@@ -166,33 +231,30 @@ func (a *App) executeIssueCommentEvent(ctx context.Context, event *github.IssueC
 		return a.executeTerraformImport(ctx, prNum, sha, cfg, cmd)
 	case *command.StateRm:
 		return a.executeTerraformStateRm(ctx, prNum, sha, cfg, cmd)
+	case *command.Output:
+		return a.executeTerraformOutput(ctx, prNum, sha, cfg, cmd)
+	case *command.Fmt:
+		return a.executeTerraformFmt(ctx, prNum, sha, cfg, cmd)
 	default:
 		return nil
 	}
 }
 
 func (a *App) executeUnlock(ctx context.Context, prNum int, cfg *config.Config, cmd *command.Unlock) error {
-	projects := make(config.Projects, 0, len(cfg.Projects))
+	var modifiedFiles []string
 	if cmd.Project == "" {
-		modifiedFiles, err := a.github.ListFiles(ctx, prNum)
+		files, err := a.vcs.ListFiles(ctx, prNum)
 		if err != nil {
 			return err
 		}
-		for _, project := range cfg.Projects {
-			if project.Plan.HasMatchedPaths(project.Dir, modifiedFiles) {
-				projects = append(projects, project)
-			}
-		}
-	} else {
-		if project := cfg.GetProject(cmd.Project); project != nil {
-			projects = append(projects, project)
-		}
+		modifiedFiles = files
 	}
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
 	if len(projects) == 0 {
 		return nil
 	}
 
-	pr, err := a.github.GetPullRequest(ctx, prNum)
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
 	if err != nil {
 		return err
 	}
@@ -207,18 +269,19 @@ func (a *App) executeUnlock(ctx context.Context, prNum int, cfg *config.Config,
 				return err
 			}
 		}
-		if err := a.unlock(ctx, project.Name, pr); err != nil {
+		if err := a.unlock(ctx, project.ID(), pr); err != nil {
 			return err
 		}
+		auditProject(ctx, project)
 		artifactNames = append(artifactNames, a.genArtifactName(project.Name, project.Workspace, prNum))
 	}
 
-	if err := a.github.DeleteArtifactsByNames(ctx, artifactNames); err != nil {
+	if err := a.planStore.Delete(ctx, artifactNames); err != nil {
 		return err
 	}
 
 	if err := a.deleteProgressLabel(ctx, prNum); err != nil {
-		if !github.IsErrNotFound(err) {
+		if !errors.Is(err, vcs.ErrNotFound) {
 			return err
 		}
 	}
@@ -226,5 +289,99 @@ func (a *App) executeUnlock(ctx context.Context, prNum int, cfg *config.Config,
 }
 
 func (a *App) executeHelp(ctx context.Context, prNum int) error {
-	return a.github.CreateIssueComment(ctx, prNum, a.helpMessage())
+	return a.vcs.CreateComment(ctx, prNum, a.helpMessage())
+}
+
+// loadConfig loads the config, discovering the projects under the config directory, and validates it.
+// The values of the secrets in the config are masked in the logs.
+func (a *App) loadConfig() (*config.Config, error) {
+	opts := []config.Option{config.WithDefaultTerraformVersion(a.defaultTerraformVersion)}
+	if dir := a.configBaseDir(); dir != "" {
+		opts = append(opts, config.WithBaseDir(dir))
+	}
+	cfg, err := config.Load(a.configPath, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range cfg.Secrets() {
+		a.action.AddMask(secret)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// checkModifiedConfig comments the problems of the config on the pull request which modifies the config file or a fragment,
+// so that they are fixed before merging. The dirs of the projects are also checked, which loadConfig does not.
+// It returns loadErr as is unless the config is modified.
+func (a *App) checkModifiedConfig(ctx context.Context, prNum int, cfg *config.Config, loadErr error) error {
+	files, err := a.vcs.ListFiles(ctx, prNum)
+	if err != nil {
+		return errors.Join(loadErr, err)
+	}
+	if !a.isConfigModified(files) {
+		return loadErr
+	}
+	err = loadErr
+	if err == nil {
+		err = cfg.ValidateDirs()
+	}
+	if err == nil {
+		return nil
+	}
+	if cerr := a.vcs.CreateComment(ctx, prNum, a.configErrorsMessage(err)); cerr != nil {
+		return errors.Join(err, cerr)
+	}
+	return err
+}
+
+// isConfigModified reports whether the files of the pull request, relative to the repository, have the config file
+// or a fragment of it.
+func (a *App) isConfigModified(files []string) bool {
+	configPath := a.repoConfigPath()
+	for _, file := range files {
+		if file == configPath || path.Base(file) == config.ProjectFile {
+			return true
+		}
+	}
+	return false
+}
+
+// repoConfigPath returns the path of the config file relative to the repository, in a slash-separated path.
+func (a *App) repoConfigPath() string {
+	configPath := filepath.Clean(a.configPath)
+	if dir := a.configBaseDir(); dir != "" {
+		if rel, err := filepath.Rel(dir, configPath); err == nil && !strings.HasPrefix(rel, "..") {
+			configPath = rel
+		}
+	}
+	return filepath.ToSlash(configPath)
+}
+
+// configBaseDir returns the directory of the repository which the config is read from.
+func (a *App) configBaseDir() string {
+	return cmp.Or(a.configDir, a.workDir)
+}
+
+// projectDir returns the directory of the project on the file system.
+func (a *App) projectDir(cfg *config.Project) string {
+	if a.workDir == "" {
+		return cfg.Dir
+	}
+	return filepath.Join(a.workDir, cfg.Dir)
+}
+
+// lockProject acquires the in-process lock of the project, and returns the function to release it.
+func (a *App) lockProject(cfg *config.Project) func() {
+	if a.projectLocker == nil {
+		return func() {}
+	}
+	return a.projectLocker.Lock(cfg.ID())
+}
+
+// isBotComment reports whether the comment is posted by mu.
+// The login of a bot has the "[bot]" suffix in REST API, but not in GraphQL API.
+func (a *App) isBotComment(login string) bool {
+	return strings.TrimSuffix(login, "[bot]") == strings.TrimSuffix(a.botName, "[bot]")
 }
@@ -2,28 +2,38 @@ package app
 
 import (
 	"context"
+	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/yu-icchi/mu/pkg/action"
-	archiveMock "github.com/yu-icchi/mu/pkg/archive/mock"
-	githubMock "github.com/yu-icchi/mu/pkg/github/mock"
+	"github.com/yu-icchi/mu/pkg/config"
+	encryptionMock "github.com/yu-icchi/mu/pkg/encryption/mock"
 	"github.com/yu-icchi/mu/pkg/log"
+	planstoreMock "github.com/yu-icchi/mu/pkg/planstore/mock"
 	tfMock "github.com/yu-icchi/mu/pkg/terraform/mock"
+	vcsMock "github.com/yu-icchi/mu/pkg/vcs/mock"
 )
 
 type mock struct {
-	github    *githubMock.MockGithub
-	archive   *archiveMock.MockArchive
+	vcs       *vcsMock.MockVCS
+	planStore *planstoreMock.MockPlanStore
+	encrypter *encryptionMock.MockEncrypter
 	terraform *tfMock.MockTerraform
 }
 
 func newMock(ctrl *gomock.Controller) *mock {
 	return &mock{
-		github:    githubMock.NewMockGithub(ctrl),
-		archive:   archiveMock.NewMockArchive(ctrl),
+		vcs:       vcsMock.NewMockVCS(ctrl),
+		planStore: planstoreMock.NewMockPlanStore(ctrl),
+		encrypter: encryptionMock.NewMockEncrypter(ctrl),
 		terraform: tfMock.NewMockTerraform(ctrl),
 	}
 }
@@ -32,13 +42,11 @@ func newTestAppAndMock(ctrl *gomock.Controller) (*App, *mock) {
 	mock := newMock(ctrl)
 	app := &App{
 		terraform:               mock.terraform,
-		github:                  mock.github,
+		vcs:                     mock.vcs,
 		action:                  action.New(io.Discard),
-		archiver:                mock.archive,
+		planStore:               mock.planStore,
 		configPath:              "",
 		defaultTerraformVersion: "",
-		uploadArtifactVersion:   "",
-		uploadArtifactDir:       "./test-upload-artifact",
 		allowCommands:           nil,
 		logger:                  log.New(io.Discard),
 		release: &Release{
@@ -48,8 +56,200 @@ func newTestAppAndMock(ctrl *gomock.Controller) (*App, *mock) {
 		},
 		disableSummaryLog: false,
 		emojiReaction:     "",
+		botName:           "github-actions",
 	}
 	return app, mock
 }
 
 type prepare func(ctx context.Context, m *mock, t *testing.T)
+
+func TestApp_isBotComment(t *testing.T) {
+	tests := map[string]struct {
+		botName  string
+		login    string
+		expected bool
+	}{
+		"github actions": {
+			botName:  "github-actions",
+			login:    "github-actions",
+			expected: true,
+		},
+		"github app in graphql": {
+			botName:  "mu-app[bot]",
+			login:    "mu-app",
+			expected: true,
+		},
+		"github app in rest": {
+			botName:  "mu-app[bot]",
+			login:    "mu-app[bot]",
+			expected: true,
+		},
+		"other user": {
+			botName:  "mu-app[bot]",
+			login:    "github-actions",
+			expected: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := &App{botName: tt.botName}
+			assert.Equal(t, tt.expected, app.isBotComment(tt.login))
+		})
+	}
+}
+
+func TestApp_checkModifiedConfig(t *testing.T) {
+	workDir := t.TempDir()
+	configPath := filepath.Join(workDir, ".github", "mu.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, ".github"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "terraform", "app"), 0o755))
+	const valid = `version: 1
+projects:
+  - name: app
+    dir: terraform/app
+    plan:
+      paths: ["*.tf"]
+`
+	const missingDir = `version: 1
+projects:
+  - name: app
+    dir: terraform/missing
+    plan:
+      paths: ["*.tf"]
+`
+	loadErr := errors.New("load error")
+
+	tests := map[string]struct {
+		config  string
+		loadErr error
+		files   []string
+		prepare prepare
+		expect  func(t *testing.T, err error)
+	}{
+		"config is not modified": {
+			config:  missingDir,
+			files:   []string{"terraform/app/main.tf"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {},
+			expect: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"load error without the modified config": {
+			loadErr: loadErr,
+			files:   []string{"terraform/app/main.tf"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {},
+			expect: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, loadErr)
+			},
+		},
+		"load error with the modified config": {
+			loadErr: loadErr,
+			files:   []string{".github/mu.yaml"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "load error")
+						return nil
+					})
+			},
+			expect: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, loadErr)
+			},
+		},
+		"dir of the modified fragment does not exist": {
+			config: missingDir,
+			files:  []string{"terraform/app/mu.project.yaml"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, `project "app": dir "terraform/missing" does not exist`)
+						return nil
+					})
+			},
+			expect: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, config.ErrInvalidConfig)
+			},
+		},
+		"valid modified config": {
+			config:  valid,
+			files:   []string{".github/mu.yaml"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {},
+			expect: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			app, m := newTestAppAndMock(ctrl)
+			app.workDir = workDir
+			app.configPath = configPath
+
+			var cfg *config.Config
+			if tt.config != "" {
+				require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0o644))
+				var err error
+				cfg, err = config.Load(configPath, config.WithBaseDir(workDir))
+				require.NoError(t, err)
+			}
+			m.vcs.EXPECT().ListFiles(ctx, 1).Return(tt.files, nil)
+			tt.prepare(ctx, m, t)
+			tt.expect(t, app.checkModifiedConfig(ctx, 1, cfg, tt.loadErr))
+		})
+	}
+}
+
+func TestApp_loadConfig(t *testing.T) {
+	workDir := t.TempDir()
+	configPath := filepath.Join(workDir, "mu.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 1
+projects:
+  - name: app
+    dir: terraform/app
+    terraform:
+      backend_config:
+        token: ${secret:MU_TEST_BACKEND_TOKEN}
+    plan:
+      paths: ["*.tf"]
+`), 0o644))
+	t.Setenv("MU_TEST_BACKEND_TOKEN", "s3cr3t")
+
+	buf := new(strings.Builder)
+	app := &App{action: action.New(buf), configPath: configPath, workDir: workDir}
+	cfg, err := app.loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", cfg.GetProject("app").Terraform.BackendConfig["token"])
+	assert.Equal(t, "::add-mask::s3cr3t\n", buf.String())
+}
+
+func TestApp_loadConfig_ConfigDir(t *testing.T) {
+	t.Parallel()
+	configDir := t.TempDir()
+	workDir := t.TempDir()
+	configPath := filepath.Join(configDir, ".github", "mu.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0o755))
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 1
+include: ["**/mu.project.yaml"]
+projects:
+  - name: app
+    dir: terraform/app
+    plan:
+      paths: ["*.tf"]
+`), 0o644))
+	// The fragment added by the pull request is not read.
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "terraform", "db"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "terraform", "db", "mu.project.yaml"), []byte(`projects:
+  - name: db
+    plan:
+      paths: ["*.tf"]
+`), 0o644))
+
+	app := &App{action: action.New(io.Discard), configPath: configPath, workDir: workDir, configDir: configDir}
+	cfg, err := app.loadConfig()
+	require.NoError(t, err)
+	assert.NotNil(t, cfg.GetProject("app"))
+	assert.Nil(t, cfg.GetProject("db"))
+	assert.Equal(t, ".github/mu.yaml", app.repoConfigPath())
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/audit"
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+type auditRecorderKey struct{}
+
+// auditRecorder collects what the command of the event did, to write the audit records when ExecuteEvent returns.
+type auditRecorder struct {
+	locker   sync.Mutex
+	start    time.Time
+	actor    string
+	prNum    int
+	sha      string
+	command  string
+	args     []string
+	projects []*auditProjectResult
+}
+
+type auditProjectResult struct {
+	name      string
+	workspace string
+	result    string
+}
+
+func newAuditRecorder(event vcs.Event) *auditRecorder {
+	var actor string
+	switch e := event.(type) {
+	case *vcs.PullRequestEvent:
+		actor = e.User
+	case *vcs.CommentEvent:
+		actor = e.User
+	case *vcs.ReviewEvent:
+		actor = e.User
+	case *vcs.PushEvent:
+		actor = e.User
+	}
+	if actor == "" {
+		actor = action.Actor()
+	}
+	return &auditRecorder{
+		start: time.Now(),
+		actor: actor,
+		prNum: event.Number(),
+	}
+}
+
+func auditRecorderFrom(ctx context.Context) *auditRecorder {
+	rec, _ := ctx.Value(auditRecorderKey{}).(*auditRecorder)
+	return rec
+}
+
+// auditCommand records the command which the event runs. The events which run no command are not recorded.
+func auditCommand(ctx context.Context, cmd command.Type, args []string) {
+	if rec := auditRecorderFrom(ctx); rec != nil {
+		rec.locker.Lock()
+		defer rec.locker.Unlock()
+		rec.command = string(cmd)
+		rec.args = args
+	}
+}
+
+func auditSHA(ctx context.Context, sha string) {
+	if rec := auditRecorderFrom(ctx); rec != nil {
+		rec.locker.Lock()
+		defer rec.locker.Unlock()
+		rec.sha = sha
+	}
+}
+
+// auditProject records that the command runs on the project, even if it fails before the result is output.
+func auditProject(ctx context.Context, project *config.Project) {
+	if rec := auditRecorderFrom(ctx); rec != nil {
+		rec.locker.Lock()
+		defer rec.locker.Unlock()
+		rec.project(project.Name, project.Workspace)
+	}
+}
+
+func auditProjects(ctx context.Context, projects OutputProjects) {
+	if rec := auditRecorderFrom(ctx); rec != nil {
+		rec.locker.Lock()
+		defer rec.locker.Unlock()
+		for _, project := range projects {
+			rec.project(project.Name, project.Workspace).result = project.Result
+		}
+	}
+}
+
+func (r *auditRecorder) project(name, workspace string) *auditProjectResult {
+	for _, project := range r.projects {
+		if project.name == name && project.workspace == workspace {
+			return project
+		}
+	}
+	project := &auditProjectResult{name: name, workspace: workspace}
+	r.projects = append(r.projects, project)
+	return project
+}
+
+// records returns one record per project, or one record without the project if the command ran on no project.
+func (r *auditRecorder) records(err error) []*audit.Record {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	base := audit.Record{
+		Time:        r.start.UTC(),
+		Actor:       r.actor,
+		PullRequest: r.prNum,
+		SHA:         r.sha,
+		Command:     r.command,
+		Args:        r.args,
+		Result:      audit.ResultSuccess,
+		DurationMS:  time.Since(r.start).Milliseconds(),
+		RunURL:      action.RunURL(),
+	}
+	if err != nil {
+		base.Result = audit.ResultFailure
+		if errors.Is(err, errPermissionDenied) {
+			base.Result = audit.ResultDenied
+		}
+		base.Error = err.Error()
+	}
+	if len(r.projects) == 0 {
+		return []*audit.Record{&base}
+	}
+	records := make([]*audit.Record, 0, len(r.projects))
+	for _, project := range r.projects {
+		record := base
+		record.Project = project.name
+		record.Workspace = project.workspace
+		record.Changes = audit.ParseChanges(project.result)
+		records = append(records, &record)
+	}
+	return records
+}
+
+func (a *App) writeAudit(ctx context.Context, rec *auditRecorder, err error) {
+	if rec.command == "" {
+		return
+	}
+	if err := audit.Write(ctx, a.auditSinks, rec.records(err)); err != nil {
+		a.logger.Error("failed to write the audit records", log.Error(err))
+	}
+}
+
+// commentArgs returns the arguments of the mu command in the comment, e.g. ["-p", "foo"] of "mu plan -p foo".
+func commentArgs(body string) []string {
+	fields := strings.Fields(body)
+	if len(fields) <= 2 {
+		return nil
+	}
+	return fields[2:]
+}
+
+// outputProjects sets the projects output of the action, and records them in the audit records.
+func (a *App) outputProjects(ctx context.Context, projects OutputProjects) error {
+	auditProjects(ctx, projects)
+	outputProjectsStr, err := json.Marshal(projects)
+	if err != nil {
+		return err
+	}
+	_ = a.action.Output("projects", string(outputProjectsStr))
+	return nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/audit"
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+type auditSink struct {
+	records []*audit.Record
+}
+
+func (s *auditSink) Write(_ context.Context, records []*audit.Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func TestApp_ExecuteEvent_Audit(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	configPath := filepath.Join(t.TempDir(), "mu.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 1
+projects:
+  - name: test
+    dir: test
+    plan:
+      paths: ["*.tf"]
+`), 0600))
+
+	ctrl := gomock.NewController(t)
+	app, m := newTestAppAndMock(ctrl)
+	sink := &auditSink{}
+	app.auditSinks = []audit.Sink{sink}
+	app.configPath = configPath
+	m.vcs.EXPECT().GetPullRequest(gomock.Any(), 1).Return(&vcs.PullRequest{
+		Number:         1,
+		HeadSHA:        "test-sha",
+		MergeableState: "clean",
+	}, nil)
+	m.vcs.EXPECT().CreateComment(gomock.Any(), 1, gomock.Any()).Return(nil)
+
+	err := app.ExecuteEvent(context.Background(), &vcs.CommentEvent{
+		Action:            vcs.Created,
+		PullRequestNumber: 1,
+		Body:              "mu help",
+		User:              "alice",
+	})
+	require.NoError(t, err)
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "alice", record.Actor)
+	assert.Equal(t, 1, record.PullRequest)
+	assert.Equal(t, "test-sha", record.SHA)
+	assert.Equal(t, "help", record.Command)
+	assert.Equal(t, audit.ResultSuccess, record.Result)
+	assert.Equal(t, "https://github.com/test/mu/actions/runs/test-run-id", record.RunURL)
+
+	// The comment which is not a mu command is not recorded.
+	require.NoError(t, app.ExecuteEvent(context.Background(), &vcs.CommentEvent{
+		Action:            vcs.Created,
+		PullRequestNumber: 1,
+		Body:              "LGTM",
+	}))
+	assert.Len(t, sink.records, 1)
+}
+
+func TestAuditRecorder_records(t *testing.T) {
+	t.Parallel()
+	rec := newAuditRecorder(&vcs.CommentEvent{PullRequestNumber: 1, User: "alice"})
+	ctx := context.WithValue(context.Background(), auditRecorderKey{}, rec)
+	auditCommand(ctx, command.ApplyType, commentArgs("mu apply -p prod"))
+	auditSHA(ctx, "sha")
+	auditProject(ctx, &config.Project{Name: "dev", Workspace: "default"})
+	auditProject(ctx, &config.Project{Name: "prod", Workspace: "default"})
+	auditProjects(ctx, OutputProjects{
+		{Name: "prod", Workspace: "default", Result: "Apply complete! Resources: 1 added, 0 changed, 2 destroyed."},
+	})
+
+	records := rec.records(errApplyFailed)
+	require.Len(t, records, 2)
+	assert.Equal(t, "dev", records[0].Project)
+	assert.Nil(t, records[0].Changes)
+	assert.Equal(t, "prod", records[1].Project)
+	assert.Equal(t, &audit.Changes{Add: 1, Destroy: 2}, records[1].Changes)
+	for _, record := range records {
+		assert.Equal(t, "alice", record.Actor)
+		assert.Equal(t, "sha", record.SHA)
+		assert.Equal(t, "apply", record.Command)
+		assert.Equal(t, []string{"-p", "prod"}, record.Args)
+		assert.Equal(t, audit.ResultFailure, record.Result)
+		assert.Equal(t, "apply failed", record.Error)
+	}
+
+	records = rec.records(errPermissionDenied)
+	assert.Equal(t, audit.ResultDenied, records[0].Result)
+}
+
+func TestAuditRecorder_records_NoProject(t *testing.T) {
+	t.Parallel()
+	rec := newAuditRecorder(&vcs.PullRequestEvent{PullRequestNumber: 1, User: "alice"})
+	rec.command = string(command.PlanType)
+	records := rec.records(nil)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].Project)
+	assert.Equal(t, audit.ResultSuccess, records[0].Result)
+}
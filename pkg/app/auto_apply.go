@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// executeReviewEvent applies the projects with `apply.auto_on_approval: true` when the pull request is approved,
+// as if `mu apply` was commented by the reviewer. Projects that do not have enough approvals yet, have no plan to apply,
+// or whose `apply` permission on the base branch does not allow the reviewer, are skipped without a comment,
+// since every review triggers the event. Nothing is applied unless apply is one of the allowed commands.
+func (a *App) executeReviewEvent(ctx context.Context, event *vcs.ReviewEvent) error {
+	if event.Action != vcs.Submitted || event.State != vcs.ReviewApproved {
+		return nil
+	}
+	if !slices.Contains(a.allowCommands, string(command.ApplyType)) {
+		return nil
+	}
+
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(cfg.Projects, func(project *config.Project) bool {
+		return project.Apply.GetAutoOnApproval()
+	}) {
+		return nil
+	}
+
+	prNum := event.Number()
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
+	if err != nil {
+		return err
+	}
+	if !pr.IsMergeable() {
+		a.logger.Info("The pull request is not mergeable, so it is not applied.", log.String("state", pr.MergeableState))
+		return nil
+	}
+
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
+	if err != nil {
+		return err
+	}
+	reviews, err := a.vcs.ListReviews(ctx, prNum)
+	if err != nil {
+		return err
+	}
+	storedNames, err := a.planStore.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	projects := a.findAutoApplyProjects(cfg, prNum, modifiedFiles, reviews, storedNames)
+	if len(projects) == 0 {
+		a.logger.Info("There is no project to apply on approval.")
+		return nil
+	}
+	base, err := a.loadBaseConfig(ctx, pr.BaseRef)
+	if err != nil {
+		return err
+	}
+
+	autoApply := make(map[string]bool)
+	checker := a.newPermissionChecker(event.User, base)
+	for _, project := range projects {
+		key, err := checker.deniedKey(ctx, project, []string{string(command.ApplyType)})
+		if errors.Is(err, errPermissionsChanged) {
+			a.logger.Info("The permissions are changed by the pull request.", log.String("project", project.Name))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if key != "" {
+			a.logger.Info("The reviewer is not allowed to apply.", log.String("project", project.Name), log.String("user", event.User))
+			continue
+		}
+		autoApply[project.ID()] = true
+	}
+	if len(autoApply) == 0 {
+		a.logger.Info("There is no project to apply on approval.")
+		return nil
+	}
+	auditCommand(ctx, command.ApplyType, nil)
+	auditSHA(ctx, pr.HeadSHA)
+
+	return a.applyProjects(ctx, prNum, pr.HeadSHA, cfg, &command.Apply{}, func(project *config.Project) bool {
+		return autoApply[project.ID()]
+	})
+}
+
+// findAutoApplyProjects returns the projects changed by the pull request which have `apply.auto_on_approval: true`,
+// enough approvals and a stored plan.
+func (a *App) findAutoApplyProjects(
+	cfg *config.Config, prNum int, modifiedFiles []string, reviews vcs.Reviews, storedNames []string,
+) config.Projects {
+	projects := make(config.Projects, 0, len(cfg.Projects))
+	for _, project := range a.findProjectConfigs(cfg, "", "", modifiedFiles) {
+		if !project.Apply.GetAutoOnApproval() || project.Apply.IsAfterMerge() {
+			continue
+		}
+		if requireApprovals := project.Apply.GetRequireApprovals(); requireApprovals > reviews.Approves() {
+			a.logger.Info("There are not enough approvals to apply.", log.String("project", project.Name))
+			continue
+		}
+		if !slices.Contains(storedNames, a.genArtifactName(project.Name, project.Workspace, prNum)) {
+			a.logger.Info("There is no plan to apply.", log.String("project", project.Name))
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_findAutoApplyProjects(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Projects: []*config.Project{
+			{
+				Name:      "auto",
+				Dir:       "auto",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+				Apply:     &config.Apply{AutoOnApproval: true, RequireApprovals: 1},
+			},
+			{
+				Name:      "manual",
+				Dir:       "manual",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+			},
+			{
+				Name:      "two_approvals",
+				Dir:       "two_approvals",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+				Apply:     &config.Apply{AutoOnApproval: true, RequireApprovals: 2},
+			},
+			{
+				Name:      "after_merge",
+				Dir:       "after_merge",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+				Apply:     &config.Apply{AutoOnApproval: true, Mode: config.ApplyModeAfterMerge},
+			},
+			{
+				Name:      "not_planned",
+				Dir:       "not_planned",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+				Apply:     &config.Apply{AutoOnApproval: true},
+			},
+			{
+				Name:      "unchanged",
+				Dir:       "unchanged",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+				Apply:     &config.Apply{AutoOnApproval: true},
+			},
+		},
+	}
+	modifiedFiles := []string{
+		"auto/main.tf", "manual/main.tf", "two_approvals/main.tf", "after_merge/main.tf", "not_planned/main.tf",
+	}
+	reviews := vcs.Reviews{
+		{UserLogin: "alice", State: "APPROVED"},
+		{UserLogin: "bob", State: "COMMENTED"},
+	}
+	storedNames := []string{
+		"mu_auto_default_1", "mu_manual_default_1", "mu_two_approvals_default_1",
+		"mu_after_merge_default_1", "mu_unchanged_default_1",
+	}
+	app := &App{logger: log.New(io.Discard)}
+	projects := app.findAutoApplyProjects(cfg, 1, modifiedFiles, reviews, storedNames)
+	names := make([]string, 0, len(projects))
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	assert.Equal(t, []string{"auto"}, names)
+}
+
+func TestApp_executeReviewEvent_Ignored(t *testing.T) {
+	t.Parallel()
+	tests := map[string]*vcs.ReviewEvent{
+		"commented": {Action: vcs.Submitted, PullRequestNumber: 1, State: "commented"},
+		"dismissed": {Action: "dismissed", PullRequestNumber: 1, State: vcs.ReviewApproved},
+	}
+	for name, event := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			app, _ := newTestAppAndMock(ctrl)
+			app.allowCommands = []string{"plan", "apply"}
+			assert.NoError(t, app.executeReviewEvent(context.Background(), event))
+		})
+	}
+}
+
+func TestApp_executeReviewEvent_ApplyNotAllowed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	app, _ := newTestAppAndMock(ctrl)
+	app.allowCommands = []string{"plan", "unlock"}
+	event := &vcs.ReviewEvent{Action: vcs.Submitted, PullRequestNumber: 1, State: vcs.ReviewApproved}
+	// The config is not loaded, and no VCS API is called.
+	assert.NoError(t, app.executeReviewEvent(context.Background(), event))
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// automerge merges the pull request when every project changed by it has been applied successfully at sha,
+// which the commit status of apply proves, and no project still has an unapplied plan.
+// The projects applied after merge are excluded since their plan files are not applied on the pull request.
+func (a *App) automerge(ctx context.Context, prNum int, sha string, cfg *config.Config, modifiedFiles []string) error {
+	projects := a.findProjectConfigs(cfg, "", "", modifiedFiles)
+	artifactNames := make([]string, 0, len(projects))
+	mergeProjects := make(config.Projects, 0, len(projects))
+	for _, project := range projects {
+		if project.Apply.IsAfterMerge() {
+			continue
+		}
+		artifactNames = append(artifactNames, a.genArtifactName(project.Name, project.Workspace, prNum))
+		mergeProjects = append(mergeProjects, project)
+	}
+	storedNames, err := a.planStore.List(ctx, "")
+	if err != nil {
+		return err
+	}
+	for _, name := range artifactNames {
+		if slices.Contains(storedNames, name) {
+			a.logger.Info("There are unapplied plans, so the pull request is not merged.", log.String("plan", name))
+			return nil
+		}
+	}
+	statuses, err := a.vcs.ListCommitStatuses(ctx, sha)
+	if err != nil {
+		return err
+	}
+	applied := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		applied[status.Context] = status.Status == vcs.SuccessStatus
+	}
+	for _, project := range mergeProjects {
+		if !applied[a.genStatusSource(command.ApplyType, project.ID())] {
+			a.logger.Info("There are unapplied projects, so the pull request is not merged.", log.String("project", project.ID()))
+			return nil
+		}
+	}
+
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
+	if err != nil {
+		return err
+	}
+	// The head branch of a fork is not in this repository, so a branch with the same name would be deleted.
+	err = a.vcs.MergePullRequest(ctx, &vcs.MergePullRequestParams{
+		Number:       prNum,
+		SHA:          sha,
+		Method:       cfg.Automerge.GetMethod(),
+		DeleteBranch: cfg.Automerge.GetDeleteBranch() && !pr.Fork,
+		Branch:       pr.HeadRef,
+	})
+	if err != nil {
+		msg := fmt.Sprintf(":x: **Automerge Failed**\n%s\n\n%s", err.Error(), action.RunURL())
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+			return err
+		}
+		return err
+	}
+
+	// Events triggered by the GITHUB_TOKEN do not start a new workflow run,
+	// so the closed event will not unlock the projects.
+	for _, project := range mergeProjects {
+		if err := a.unlock(ctx, project.ID(), pr); err != nil {
+			return err
+		}
+	}
+	msg := fmt.Sprintf(":twisted_rightwards_arrows: Merged automatically after applying all projects (method: `%s`).",
+		cfg.Automerge.GetMethod())
+	return a.vcs.CreateComment(ctx, prNum, msg)
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_automerge(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	cfg := &config.Config{
+		Projects: []*config.Project{
+			{
+				Name:      "test",
+				Dir:       "test",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+			},
+			{
+				Name:      "db",
+				Dir:       "db",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+			},
+			{
+				Name:      "after",
+				Dir:       "after",
+				Workspace: "default",
+				Plan:      &config.Plan{Paths: []string{"*.tf"}},
+				Apply:     &config.Apply{Mode: config.ApplyModeAfterMerge},
+			},
+		},
+		Automerge: &config.Automerge{
+			Enabled:      true,
+			Method:       config.MergeMethodSquash,
+			DeleteBranch: true,
+		},
+	}
+	modifiedFiles := []string{"test/main.tf", "after/main.tf"}
+	applied := []*vcs.CommitStatus{
+		{Sha: "test-sha", Status: vcs.SuccessStatus, Context: "mu/plan: test"},
+		{Sha: "test-sha", Status: vcs.SuccessStatus, Context: "mu/apply: test"},
+	}
+	tests := []struct {
+		name          string
+		modifiedFiles []string
+		prepare       prepare
+		expectErr     error
+	}{
+		{
+			name: "success",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{"mu_after_default_1"}, nil)
+				m.vcs.EXPECT().ListCommitStatuses(ctx, "test-sha").Return(applied, nil)
+				pr := &vcs.PullRequest{
+					Number:  1,
+					HeadSHA: "test-sha",
+					HeadRef: "test-branch",
+					Labels:  []*vcs.Label{{Name: "mu_lock_test"}},
+				}
+				m.vcs.EXPECT().GetPullRequest(ctx, 1).Return(pr, nil)
+				m.vcs.EXPECT().MergePullRequest(ctx, &vcs.MergePullRequestParams{
+					Number:       1,
+					SHA:          "test-sha",
+					Method:       "squash",
+					DeleteBranch: true,
+					Branch:       "test-branch",
+				}).Return(nil)
+				m.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*vcs.PullRequest{}, nil)
+				m.vcs.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, ":unlock: Unlocked the `test` project").Return(nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "Merged automatically")
+						return nil
+					})
+			},
+		},
+		{
+			name: "fork pull request keeps the branch",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{}, nil)
+				m.vcs.EXPECT().ListCommitStatuses(ctx, "test-sha").Return(applied, nil)
+				pr := &vcs.PullRequest{
+					Number:  1,
+					HeadSHA: "test-sha",
+					HeadRef: "main",
+					Fork:    true,
+				}
+				m.vcs.EXPECT().GetPullRequest(ctx, 1).Return(pr, nil)
+				m.vcs.EXPECT().MergePullRequest(ctx, &vcs.MergePullRequestParams{
+					Number:       1,
+					SHA:          "test-sha",
+					Method:       "squash",
+					DeleteBranch: false,
+					Branch:       "main",
+				}).Return(nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "Merged automatically")
+						return nil
+					})
+			},
+		},
+		{
+			name: "unapplied plans",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{"mu_test_default_1"}, nil)
+			},
+		},
+		{
+			name:          "project never planned",
+			modifiedFiles: []string{"test/main.tf", "db/main.tf"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{}, nil)
+				m.vcs.EXPECT().ListCommitStatuses(ctx, "test-sha").Return(applied, nil)
+			},
+		},
+		{
+			name: "apply failed",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{}, nil)
+				m.vcs.EXPECT().ListCommitStatuses(ctx, "test-sha").Return([]*vcs.CommitStatus{
+					{Sha: "test-sha", Status: vcs.FailureStatus, Context: "mu/apply: test"},
+				}, nil)
+			},
+		},
+		{
+			name: "failed to list commit statuses",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{}, nil)
+				m.vcs.EXPECT().ListCommitStatuses(ctx, "test-sha").Return(nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "failed to merge",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.planStore.EXPECT().List(ctx, "").Return([]string{}, nil)
+				m.vcs.EXPECT().ListCommitStatuses(ctx, "test-sha").Return(applied, nil)
+				m.vcs.EXPECT().GetPullRequest(ctx, 1).Return(&vcs.PullRequest{Number: 1, HeadRef: "test-branch"}, nil)
+				m.vcs.EXPECT().MergePullRequest(ctx, gomock.Any()).Return(assert.AnError)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "Automerge Failed")
+						return nil
+					})
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, mock, t)
+			files := modifiedFiles
+			if tt.modifiedFiles != nil {
+				files = tt.modifiedFiles
+			}
+			err := app.automerge(ctx, 1, "test-sha", cfg, files)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
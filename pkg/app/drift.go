@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+const muDriftMeta = "<!-- mu:drift -->"
+
+// driftCommand is the command of the drift detection in the audit records.
+const driftCommand command.Type = "drift"
+
+// executeDriftDetection runs terraform plan against the checked out commit (usually the default branch)
+// and keeps one tracking issue per project up to date with the detected drift.
+// A project which fails does not stop the others, and the errors are returned together after the outputs are written.
+func (a *App) executeDriftDetection(ctx context.Context) error {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	projects := a.findDriftProjects(cfg)
+	if len(projects) == 0 {
+		a.logger.Info("There is no project to detect drift.")
+		return nil
+	}
+	auditCommand(ctx, driftCommand, nil)
+
+	outputProjects := make(OutputProjects, 0, len(projects))
+	var errs []error
+	for _, project := range projects {
+		out, err := a.tfDrift(ctx, project)
+		if err != nil {
+			a.logger.Error("failed to detect drift", log.String("project", project.ID()), log.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", project.ID(), err))
+			continue
+		}
+		outputProjects = append(outputProjects, &OutputProject{
+			Name:      project.Name,
+			Dir:       project.Dir,
+			Workspace: project.Workspace,
+			Mode:      "drift",
+			Result:    out.Result,
+			ActionURL: action.RunURL(),
+		})
+	}
+
+	if err := a.outputProjects(ctx, outputProjects); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (a *App) findDriftProjects(cfg *config.Config) config.Projects {
+	if len(a.driftProjects) == 0 {
+		return cfg.Projects
+	}
+	projects := make(config.Projects, 0, len(a.driftProjects))
+	for _, name := range a.driftProjects {
+		found := cfg.GetProjects(name, "")
+		if len(found) == 0 {
+			a.logger.Warn("not found", log.String("project", name))
+			continue
+		}
+		projects = append(projects, found...)
+	}
+	return projects
+}
+
+func (a *App) tfDrift(ctx context.Context, cfg *config.Project) (*terraform.Output, error) {
+	defer a.lockProject(cfg)()
+	auditProject(ctx, cfg)
+
+	tf := a.genTerraform(cfg)
+	if err := tf.Setup(ctx); err != nil {
+		return nil, err
+	}
+	if err := tf.CompareVersion(ctx, cfg.Terraform.GetVersion()); err != nil {
+		return nil, err
+	}
+	if err := tf.SwitchWorkspace(ctx, cfg.Workspace); err != nil {
+		return nil, err
+	}
+
+	hooks := &hookParams{project: cfg}
+	var planRet *terraform.Output
+	for _, step := range cfg.PlanSteps() {
+		switch step.Type() {
+		case config.StepInit:
+			if err := a.tfInit(ctx, tf, hooks, step.GetExtraArgs(), nil); err != nil {
+				return nil, err
+			}
+		case config.StepRun:
+			stepOut, err := a.runStep(ctx, hooks, step.Run)
+			if err != nil {
+				return nil, err
+			}
+			if stepOut.hasError {
+				return nil, a.outputStepFailedResult(ctx, 0, "", cfg, []*stepOutput{stepOut})
+			}
+		case config.StepPlan:
+			if err := a.runHooks(ctx, config.HookPrePlan, hooks); err != nil {
+				return nil, err
+			}
+			a.action.StartGroup(fmt.Sprintf("mu drift --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+			var err error
+			planRet, err = tf.Plan(ctx, &terraform.PlanParams{
+				Vars:        cfg.Terraform.GetVars(),
+				VarFiles:    cfg.Terraform.GetVarFiles(),
+				RefreshOnly: cfg.Drift.GetRefreshOnly(),
+				ExtraArgs:   step.GetExtraArgs(),
+			}, terraform.WithStream(os.Stdout))
+			_, _ = fmt.Fprintln(os.Stdout)
+			a.action.EndGroup()
+			if err != nil {
+				return nil, err
+			}
+			if !a.disableSummaryLog {
+				a.outputDriftSummary(cfg, planRet.RawLog)
+			}
+			if planRet.HasError {
+				return nil, errPlanFailed
+			}
+			if err := a.runPostHooks(ctx, config.HookPostPlan, hooks); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := a.reportDrift(ctx, cfg, planRet); err != nil {
+		return nil, err
+	}
+	return planRet, nil
+}
+
+func (a *App) genDriftLabel(project string) string {
+	const driftLabel = "mu_drift"
+	return fmt.Sprintf("%s_%s", driftLabel, project)
+}
+
+// reportDrift opens or updates the tracking issue of the project when drift is found,
+// and closes it once the project shows no drift again.
+func (a *App) reportDrift(ctx context.Context, cfg *config.Project, out *terraform.Output) error {
+	label := a.genDriftLabel(cfg.ID())
+	issue, err := a.vcs.FindIssueByLabel(ctx, label)
+	if err != nil && !errors.Is(err, vcs.ErrNotFound) {
+		return err
+	}
+
+	if !out.HasChanges {
+		if issue == nil {
+			return nil
+		}
+		msg := fmt.Sprintf(":white_check_mark: No drift is detected in the `%s` project anymore.\n\n%s", cfg.Name, action.RunURL())
+		if err := a.vcs.CreateComment(ctx, issue.Number, msg); err != nil {
+			return err
+		}
+		return a.vcs.CloseIssue(ctx, issue.Number)
+	}
+
+	body := a.driftMessage(cfg, out)
+	if issue != nil {
+		return a.vcs.UpdateIssueBody(ctx, issue.Number, body)
+	}
+	desc := fmt.Sprintf("Drift detected: %s", cfg.Name)
+	if err := a.vcs.CreateLabel(ctx, label, desc, cfg.LockLabelColor); err != nil {
+		if !errors.Is(err, vcs.ErrAlreadyExists) {
+			return err
+		}
+	}
+	title := fmt.Sprintf("mu: drift detected in the %s project", cfg.Name)
+	if _, err := a.vcs.CreateIssue(ctx, title, body, []string{label}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *App) outputDriftSummary(cfg *config.Project, log string) {
+	summary := new(strings.Builder)
+	summary.WriteString("## mu drift\n\n")
+	summary.WriteString(fmt.Sprintf("project: `%s` workspace: `%s`\n", cfg.Name, cfg.Workspace))
+	summary.WriteString("<details><summary>Show Output</summary>\n")
+	summary.WriteString("\n```\n")
+	summary.WriteString(log)
+	summary.WriteString("\n```\n")
+	summary.WriteString("</details>\n")
+	_ = a.action.AddStepSummary(summary.String())
+}
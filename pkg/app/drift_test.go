@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_reportDrift(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	project := &config.Project{
+		Name:      "test",
+		Dir:       "./testdata",
+		Workspace: "default",
+	}
+	tests := []struct {
+		name      string
+		out       *terraform.Output
+		prepare   prepare
+		expectErr error
+	}{
+		{
+			name: "no drift and no issue",
+			out:  &terraform.Output{Result: "No changes."},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().FindIssueByLabel(ctx, "mu_drift_test").Return(nil, vcs.ErrNotFound)
+			},
+		},
+		{
+			name: "no drift and close issue",
+			out:  &terraform.Output{Result: "No changes."},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().FindIssueByLabel(ctx, "mu_drift_test").Return(&vcs.Issue{Number: 10}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 10, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CloseIssue(ctx, 10).Return(nil)
+			},
+		},
+		{
+			name: "drift and create issue",
+			out:  &terraform.Output{Result: "Plan: 1 to add, 0 to change, 0 to destroy.", HasChanges: true},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().FindIssueByLabel(ctx, "mu_drift_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_drift_test", "Drift detected: test", "").Return(nil)
+				m.vcs.EXPECT().CreateIssue(ctx, "mu: drift detected in the test project", gomock.Any(), []string{"mu_drift_test"}).
+					DoAndReturn(func(_ context.Context, _, body string, _ []string) (*vcs.Issue, error) {
+						assert.Contains(t, body, "Plan: 1 to add, 0 to change, 0 to destroy.")
+						return &vcs.Issue{Number: 10}, nil
+					})
+			},
+		},
+		{
+			name: "drift and update issue",
+			out:  &terraform.Output{Result: "Plan: 1 to add, 0 to change, 0 to destroy.", HasChanges: true},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().FindIssueByLabel(ctx, "mu_drift_test").Return(&vcs.Issue{Number: 10}, nil)
+				m.vcs.EXPECT().UpdateIssueBody(ctx, 10, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "failed to find issue",
+			out:  &terraform.Output{Result: "No changes."},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().FindIssueByLabel(ctx, "mu_drift_test").Return(nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, mock, t)
+			err := app.reportDrift(ctx, project, tt.out)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestApp_executeDriftDetection(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	configPath := filepath.Join(dir, "mu.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 1
+projects:
+  - name: broken
+    dir: broken
+    plan:
+      paths: ["*.tf"]
+  - name: app
+    dir: app
+    plan:
+      paths: ["*.tf"]
+`), 0o644))
+	ctrl := gomock.NewController(t)
+	app, m := newTestAppAndMock(ctrl)
+	app.configPath = configPath
+	ctx := context.Background()
+
+	gomock.InOrder(
+		m.terraform.EXPECT().Setup(ctx).Return(assert.AnError),
+		m.terraform.EXPECT().Setup(ctx).Return(nil),
+	)
+	m.terraform.EXPECT().CompareVersion(ctx, gomock.Any()).Return(nil)
+	m.terraform.EXPECT().SwitchWorkspace(ctx, gomock.Any()).Return(nil)
+	m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).Return(&terraform.Output{}, nil)
+	m.terraform.EXPECT().Plan(ctx, gomock.Any(), gomock.Any()).Return(&terraform.Output{Result: "No changes."}, nil)
+	m.vcs.EXPECT().FindIssueByLabel(ctx, "mu_drift_app").Return(nil, vcs.ErrNotFound)
+
+	err := app.executeDriftDetection(ctx)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "broken: ")
+
+	// The project after the failed one is still reported in the outputs.
+	output, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(output), `"name":"app"`)
+	assert.NotContains(t, string(output), `"name":"broken"`)
+}
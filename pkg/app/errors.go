@@ -5,13 +5,23 @@ import "errors"
 var (
 	errInitFailed         = errors.New("init failed")
 	errPlanFailed         = errors.New("plan failed")
+	errPlanChecksFailed   = errors.New("plan checks failed")
+	errFmtFailed          = errors.New("fmt failed")
 	errApplyFailed        = errors.New("apply failed")
 	errNotFoundPlanFile   = errors.New("plan file is not found")
+	errStalePlan          = errors.New("stale plan")
+	errPlanFileTampered   = errors.New("plan file is tampered")
+	errDecryptionFailed   = errors.New("decryption failed")
 	errApprovalsRequired  = errors.New("approvals are required")
 	errForceUnlockFailed  = errors.New("force unlock failed")
 	errImportFailed       = errors.New("import failed")
+	errOutputFailed       = errors.New("output failed")
 	errAlreadyLocked      = errors.New("already locked")
 	errPanicOccurred      = errors.New("panic occurred")
 	errMultipleLockLabels = errors.New("multiple lock labels")
 	errInvalidForceUnlock = errors.New("invalid force unlock")
+	errPermissionDenied   = errors.New("permission denied")
+	errPermissionsChanged = errors.New("permissions are changed")
+	errHookFailed         = errors.New("hook failed")
+	errStepFailed         = errors.New("workflow step failed")
 )
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/hook"
+	"github.com/yu-icchi/mu/pkg/log"
+)
+
+// hookParams describes the project to the hooks and the run steps of the workflow.
+type hookParams struct {
+	prNum   int
+	sha     string
+	project *config.Project
+	// planFile is the path of the plan file, which is empty for the commands without it.
+	planFile string
+}
+
+func (p *hookParams) env() []string {
+	env := []string{
+		"MU_PROJECT=" + p.project.Name,
+		"MU_PROJECT_DIR=" + p.project.Dir,
+		"MU_WORKSPACE=" + p.project.Workspace,
+		"MU_SHA=" + p.sha,
+		"MU_PLAN_FILE=" + p.planFile,
+	}
+	if p.prNum > 0 {
+		env = append(env, "MU_PULL_REQUEST="+strconv.Itoa(p.prNum))
+	}
+	return env
+}
+
+// runHooks runs the hooks of the stage in order in the project directory, each in its own log group.
+// When a hook fails, the rest are skipped, its output is commented on the pull request and errHookFailed is returned.
+func (a *App) runHooks(ctx context.Context, stage string, params *hookParams) error {
+	cfg := params.project
+	for _, command := range cfg.Hooks.Get(stage) {
+		a.action.StartGroup(fmt.Sprintf("mu hook %s --project=%s --workspace=%s: %s",
+			stage, cfg.Name, cfg.Workspace, command))
+		out, err := hook.Run(ctx, &hook.Params{
+			Command: command,
+			Dir:     a.projectDir(cfg),
+			Env:     append(params.env(), "MU_HOOK="+stage),
+			Stream:  os.Stdout,
+		})
+		_, _ = fmt.Fprintln(os.Stdout)
+		a.action.EndGroup()
+		if err != nil {
+			return err
+		}
+		if !out.HasError {
+			continue
+		}
+		if params.prNum > 0 {
+			if err := a.outputHookFailedResult(ctx, params.prNum, stage, cfg, command, out); err != nil {
+				return err
+			}
+		}
+		return fmt.Errorf("%s hook %q: %w: %w", stage, command, out.Error, errHookFailed)
+	}
+	return nil
+}
+
+// runPostHooks runs the hooks after the Terraform step has finished.
+// A failed hook is commented on the pull request, but does not change the result of the step.
+func (a *App) runPostHooks(ctx context.Context, stage string, params *hookParams) error {
+	err := a.runHooks(ctx, stage, params)
+	if errors.Is(err, errHookFailed) {
+		a.logger.Warn("hook failed", log.String("project", params.project.Name), log.Error(err))
+		return nil
+	}
+	return err
+}
+
+func (a *App) outputHookFailedResult(
+	ctx context.Context, prNum int, stage string, cfg *config.Project, command string, out *hook.Output,
+) error {
+	comment := a.hookFailedMessage(stage, cfg, command, out)
+	messages := a.splitMessages(strings.NewReader(comment))
+	for _, msg := range messages {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/config"
+)
+
+func TestApp_runHooks(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		hooks   []string
+		prNum   int
+		prepare prepare
+		expect  string
+		err     error
+	}{
+		"success": {
+			hooks: []string{
+				`echo "$MU_HOOK $MU_PROJECT $MU_WORKSPACE $MU_PULL_REQUEST $MU_SHA $MU_PLAN_FILE" > hook.txt`,
+				"echo done >> hook.txt",
+			},
+			prNum:  1,
+			expect: "pre_plan test default 1 sha plan.tfplan\ndone\n",
+		},
+		"failure": {
+			hooks: []string{
+				"echo first > hook.txt",
+				"echo 'tflint: 1 issue' && exit 2",
+				"echo never >> hook.txt",
+			},
+			prNum: 1,
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ int, body string) error {
+						assert.True(t, strings.HasPrefix(body, muPlanMeta))
+						assert.Contains(t, body, ":x: **Hook Failed** `pre_plan`")
+						assert.Contains(t, body, "echo 'tflint: 1 issue' && exit 2")
+						assert.Contains(t, body, "> tflint: 1 issue")
+						return nil
+					})
+			},
+			expect: "first\n",
+			err:    errHookFailed,
+		},
+		"failure without pull request": {
+			hooks:  []string{"exit 1"},
+			prNum:  0,
+			expect: "",
+			err:    errHookFailed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+			app, m := newTestAppAndMock(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(ctx, m, t)
+			}
+			dir := t.TempDir()
+			project := &config.Project{
+				Name:      "test",
+				Dir:       dir,
+				Workspace: "default",
+				Hooks: &config.Hooks{
+					PrePlan: tt.hooks,
+				},
+			}
+			err := app.runHooks(ctx, config.HookPrePlan, &hookParams{
+				prNum:    tt.prNum,
+				sha:      "sha",
+				project:  project,
+				planFile: "plan.tfplan",
+			})
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, "hook.txt"))
+			if tt.expect == "" {
+				assert.True(t, os.IsNotExist(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, string(got))
+		})
+	}
+}
+
+func TestApp_runPostHooks(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	app, m := newTestAppAndMock(ctrl)
+	m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int, body string) error {
+			assert.True(t, strings.HasPrefix(body, muApplyMeta))
+			assert.Contains(t, body, ":x: **Hook Failed** `post_apply`")
+			return nil
+		})
+	project := &config.Project{
+		Name: "test",
+		Dir:  t.TempDir(),
+		Hooks: &config.Hooks{
+			PostApply: []string{"exit 1"},
+		},
+	}
+	err := app.runPostHooks(ctx, config.HookPostApply, &hookParams{prNum: 1, project: project})
+	assert.NoError(t, err)
+}
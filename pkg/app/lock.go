@@ -10,7 +10,7 @@ import (
 
 	"github.com/yu-icchi/mu/pkg/action"
 	"github.com/yu-icchi/mu/pkg/command"
-	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func (a *App) genLockLabel(project string) string {
@@ -22,8 +22,8 @@ func (a *App) lock(
 	ctx context.Context, project string, prNum int, commandType command.Type, color string,
 ) error {
 	label := a.genLockLabel(project)
-	pr, err := a.github.FindPullRequestByLabel(ctx, label)
-	if err != nil && !errors.Is(err, github.ErrNotFound) {
+	pr, err := a.vcs.FindPullRequestByLabel(ctx, label)
+	if err != nil && !errors.Is(err, vcs.ErrNotFound) {
 		return err
 	}
 	if pr != nil && pr.Number == prNum {
@@ -37,11 +37,11 @@ func (a *App) lock(
 		return errAlreadyLocked
 	}
 	lockDesc := fmt.Sprintf("PR: #%d", prNum)
-	if err := a.github.CreateLabel(ctx, label, lockDesc, color); err != nil {
-		if !github.IsErrAlreadyExists(err) {
+	if err := a.vcs.CreateLabel(ctx, label, lockDesc, color); err != nil {
+		if !errors.Is(err, vcs.ErrAlreadyExists) {
 			return err
 		}
-		lockLabel, err := a.github.GetLabel(ctx, label)
+		lockLabel, err := a.vcs.GetLabel(ctx, label)
 		if err != nil {
 			return err
 		}
@@ -50,21 +50,21 @@ func (a *App) lock(
 		}
 		return errAlreadyLocked
 	}
-	return a.github.AddPullRequestLabels(ctx, prNum, []string{label})
+	return a.vcs.AddPullRequestLabels(ctx, prNum, []string{label})
 }
 
 func (a *App) notifyLockedMessage(ctx context.Context, prNum int, commandType command.Type, description string) error {
 	cmdType := cases.Title(language.Und).String(string(commandType))
 	lockedMsg := fmt.Sprintf(":lock: **%s Failed** This project is currently locked by %s\nRemove lock label if not needed", cmdType, description)
-	return a.github.CreateIssueComment(ctx, prNum, lockedMsg)
+	return a.vcs.CreateComment(ctx, prNum, lockedMsg)
 }
 
-func (a *App) unlock(ctx context.Context, project string, pr *github.PullRequest) error {
+func (a *App) unlock(ctx context.Context, project string, pr *vcs.PullRequest) error {
 	label := a.genLockLabel(project)
 	if !pr.HasLabel(label) {
 		return nil
 	}
-	pullRequests, err := a.github.ListPullRequestsByLabel(ctx, label, 2)
+	pullRequests, err := a.vcs.ListPullRequestsByLabel(ctx, label, 2)
 	if err != nil {
 		return err
 	}
@@ -74,15 +74,15 @@ func (a *App) unlock(ctx context.Context, project string, pr *github.PullRequest
 		}
 		return errMultipleLockLabels
 	}
-	if err := a.github.DeleteLabel(ctx, label); err != nil {
+	if err := a.vcs.DeleteLabel(ctx, label); err != nil {
 		return err
 	}
 	unlockedMsg := fmt.Sprintf(":unlock: Unlocked the `%s` project", project)
-	return a.github.CreateIssueComment(ctx, pr.Number, unlockedMsg)
+	return a.vcs.CreateComment(ctx, pr.Number, unlockedMsg)
 }
 
 func (a *App) notifyFailedUnlockMessage(ctx context.Context, prNum int, label string) error {
 	url := action.LabelURL(label)
 	msg := fmt.Sprintf(":x: **Unlock failed**\nMultiple %s labels exist.\n\n%s", label, url)
-	return a.github.CreateIssueComment(ctx, prNum, msg)
+	return a.vcs.CreateComment(ctx, prNum, msg)
 }
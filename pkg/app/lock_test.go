@@ -4,13 +4,12 @@ import (
 	"context"
 	"testing"
 
-	githubv3 "github.com/google/go-github/v69/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/yu-icchi/mu/pkg/command"
-	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func TestApp_lock(t *testing.T) {
@@ -38,9 +37,9 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				mock.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(nil)
-				mock.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				mock.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(nil)
+				mock.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 			},
 			expect: nil,
 		},
@@ -54,7 +53,7 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					Number: 1,
 				}, nil)
 			},
@@ -70,11 +69,11 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					Number: 2,
 				}, nil)
 				lockedMsg := ":lock: **Plan Failed** This project is currently locked by PR: #2\nRemove lock label if not needed"
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, lockedMsg).Return(nil)
+				mock.vcs.EXPECT().CreateComment(ctx, 1, lockedMsg).Return(nil)
 			},
 			expect: errAlreadyLocked,
 		},
@@ -88,7 +87,7 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -102,8 +101,8 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				mock.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(assert.AnError)
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				mock.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -117,9 +116,9 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				mock.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(nil)
-				mock.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(assert.AnError)
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				mock.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(nil)
+				mock.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -133,11 +132,11 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					Number: 2,
 				}, nil)
 				lockedMsg := ":lock: **Plan Failed** This project is currently locked by PR: #2\nRemove lock label if not needed"
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, lockedMsg).Return(assert.AnError)
+				mock.vcs.EXPECT().CreateComment(ctx, 1, lockedMsg).Return(assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -151,20 +150,14 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				mock.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(&githubv3.ErrorResponse{
-					Errors: []githubv3.Error{
-						{
-							Code: "already_exists",
-						},
-					},
-				})
-				mock.github.EXPECT().GetLabel(ctx, "mu_lock_test").Return(&github.Label{
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				mock.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(vcs.ErrAlreadyExists)
+				mock.vcs.EXPECT().GetLabel(ctx, "mu_lock_test").Return(&vcs.Label{
 					Name:        "mu_lock_test",
 					Description: "PR: #2",
 				}, nil)
 				lockedMsg := ":lock: **Plan Failed** This project is currently locked by PR: #2\nRemove lock label if not needed"
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, lockedMsg).Return(nil)
+				mock.vcs.EXPECT().CreateComment(ctx, 1, lockedMsg).Return(nil)
 			},
 			expect: errAlreadyLocked,
 		},
@@ -178,15 +171,9 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				mock.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(&githubv3.ErrorResponse{
-					Errors: []githubv3.Error{
-						{
-							Code: "already_exists",
-						},
-					},
-				})
-				mock.github.EXPECT().GetLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				mock.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(vcs.ErrAlreadyExists)
+				mock.vcs.EXPECT().GetLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -200,20 +187,14 @@ func TestApp_lock(t *testing.T) {
 				color:       "ff0000",
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				mock.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(&githubv3.ErrorResponse{
-					Errors: []githubv3.Error{
-						{
-							Code: "already_exists",
-						},
-					},
-				})
-				mock.github.EXPECT().GetLabel(ctx, "mu_lock_test").Return(&github.Label{
+				mock.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				mock.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000").Return(vcs.ErrAlreadyExists)
+				mock.vcs.EXPECT().GetLabel(ctx, "mu_lock_test").Return(&vcs.Label{
 					Name:        "mu_lock_test",
 					Description: "PR: #2",
 				}, nil)
 				lockedMsg := ":lock: **Plan Failed** This project is currently locked by PR: #2\nRemove lock label if not needed"
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, lockedMsg).Return(assert.AnError)
+				mock.vcs.EXPECT().CreateComment(ctx, 1, lockedMsg).Return(assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -241,7 +222,7 @@ func TestApp_unlock(t *testing.T) {
 	type args struct {
 		ctx     context.Context
 		project string
-		pr      *github.PullRequest
+		pr      *vcs.PullRequest
 	}
 	tests := []struct {
 		name    string
@@ -254,9 +235,9 @@ func TestApp_unlock(t *testing.T) {
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test",
 						},
@@ -264,10 +245,10 @@ func TestApp_unlock(t *testing.T) {
 				},
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*github.PullRequest{}, nil)
-				mock.github.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(nil)
+				mock.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*vcs.PullRequest{}, nil)
+				mock.vcs.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(nil)
 				unlockedMsg := ":unlock: Unlocked the `test` project"
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, unlockedMsg).Return(nil)
+				mock.vcs.EXPECT().CreateComment(ctx, 1, unlockedMsg).Return(nil)
 			},
 			expect: nil,
 		},
@@ -276,9 +257,9 @@ func TestApp_unlock(t *testing.T) {
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test2",
 						},
@@ -293,9 +274,9 @@ func TestApp_unlock(t *testing.T) {
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test",
 						},
@@ -303,12 +284,12 @@ func TestApp_unlock(t *testing.T) {
 				},
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*github.PullRequest{
+				mock.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*vcs.PullRequest{
 					{
 						ID:     1,
 						Number: 1,
 						Title:  "test-1",
-						Labels: []*github.Label{
+						Labels: []*vcs.Label{
 							{
 								Name: "mu_lock_test",
 							},
@@ -318,14 +299,14 @@ func TestApp_unlock(t *testing.T) {
 						ID:     2,
 						Number: 2,
 						Title:  "test-2",
-						Labels: []*github.Label{
+						Labels: []*vcs.Label{
 							{
 								Name: "mu_lock_test",
 							},
 						},
 					},
 				}, nil)
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, `:x: **Unlock failed**
+				mock.vcs.EXPECT().CreateComment(ctx, 1, `:x: **Unlock failed**
 Multiple mu_lock_test labels exist.
 
 https://github.com/test/test/labels/mu_lock_test`).Return(nil)
@@ -337,9 +318,9 @@ https://github.com/test/test/labels/mu_lock_test`).Return(nil)
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test",
 						},
@@ -347,7 +328,7 @@ https://github.com/test/test/labels/mu_lock_test`).Return(nil)
 				},
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return(nil, assert.AnError)
+				mock.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return(nil, assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -356,9 +337,9 @@ https://github.com/test/test/labels/mu_lock_test`).Return(nil)
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test",
 						},
@@ -366,12 +347,12 @@ https://github.com/test/test/labels/mu_lock_test`).Return(nil)
 				},
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*github.PullRequest{
+				mock.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*vcs.PullRequest{
 					{
 						ID:     1,
 						Number: 1,
 						Title:  "test-1",
-						Labels: []*github.Label{
+						Labels: []*vcs.Label{
 							{
 								Name: "mu_lock_test",
 							},
@@ -381,14 +362,14 @@ https://github.com/test/test/labels/mu_lock_test`).Return(nil)
 						ID:     2,
 						Number: 2,
 						Title:  "test-2",
-						Labels: []*github.Label{
+						Labels: []*vcs.Label{
 							{
 								Name: "mu_lock_test",
 							},
 						},
 					},
 				}, nil)
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, `:x: **Unlock failed**
+				mock.vcs.EXPECT().CreateComment(ctx, 1, `:x: **Unlock failed**
 Multiple mu_lock_test labels exist.
 
 https://github.com/test/test/labels/mu_lock_test`).Return(assert.AnError)
@@ -400,9 +381,9 @@ https://github.com/test/test/labels/mu_lock_test`).Return(assert.AnError)
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test",
 						},
@@ -410,8 +391,8 @@ https://github.com/test/test/labels/mu_lock_test`).Return(assert.AnError)
 				},
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*github.PullRequest{}, nil)
-				mock.github.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(assert.AnError)
+				mock.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*vcs.PullRequest{}, nil)
+				mock.vcs.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -420,9 +401,9 @@ https://github.com/test/test/labels/mu_lock_test`).Return(assert.AnError)
 			args: args{
 				ctx:     context.Background(),
 				project: "test",
-				pr: &github.PullRequest{
+				pr: &vcs.PullRequest{
 					Number: 1,
-					Labels: []*github.Label{
+					Labels: []*vcs.Label{
 						{
 							Name: "mu_lock_test",
 						},
@@ -430,10 +411,10 @@ https://github.com/test/test/labels/mu_lock_test`).Return(assert.AnError)
 				},
 			},
 			prepare: func(ctx context.Context, mock *mock, t *testing.T) {
-				mock.github.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*github.PullRequest{}, nil)
-				mock.github.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(nil)
+				mock.vcs.EXPECT().ListPullRequestsByLabel(ctx, "mu_lock_test", 2).Return([]*vcs.PullRequest{}, nil)
+				mock.vcs.EXPECT().DeleteLabel(ctx, "mu_lock_test").Return(nil)
 				unlockedMsg := ":unlock: Unlocked the `test` project"
-				mock.github.EXPECT().CreateIssueComment(ctx, 1, unlockedMsg).Return(assert.AnError)
+				mock.vcs.EXPECT().CreateComment(ctx, 1, unlockedMsg).Return(assert.AnError)
 			},
 			expect: assert.AnError,
 		},
@@ -8,9 +8,11 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/yu-icchi/mu/pkg/action"
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/hook"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 const (
@@ -29,6 +31,17 @@ func (a *App) unknownCommandMessage(commandType string, allowCommands []string)
 	return msg.String()
 }
 
+func (a *App) configErrorsMessage(err error) string {
+	msg := new(strings.Builder)
+	msg.WriteString(":x: **Invalid Config**\n")
+	msg.WriteString(fmt.Sprintf("`%s` has the following problems:\n", a.configPath))
+	msg.WriteString("```\n")
+	msg.WriteString(err.Error())
+	msg.WriteString("\n```\n")
+	msg.WriteString("Run `mu config validate` to check the config locally.\n")
+	return msg.String()
+}
+
 func (a *App) helpMessage() string {
 	const msg = `Mu
 Terraform Pull Request Automation
@@ -49,12 +62,20 @@ Examples:
 Commands:
   plan     Runs 'terraform plan' for the changes in this pull request.
            To plan a specific project, use the -p flags.
+           To plan a specific workspace of the project, use the -w flags.
 
   apply    Runs 'terraform apply' on all unapplied plans from this pull request.
            To only apply a specific plan, use the -p flags.
+           To only apply a specific workspace of the project, use the -w flags.
 
   unlock   Removes all mu locks and discards all plans for this pull request.
 
+  fmt      Checks the formatting of the project's configuration files.
+           To commit the formatted files to this pull request, use the --fix flags.
+
+  output   Shows the output values of the project's root module.
+           To show a specific output, pass its name after the -p flags.
+
   help     View help.
 
 `
@@ -95,7 +116,7 @@ func (a *App) splitMessages(reader io.Reader) []string {
 		codeBlock    = "```"
 		diff         = "diff"
 		warning      = "> [!WARNING]"
-		size         = github.MaxCommentLen - 5536
+		size         = vcs.MaxCommentLen - 5536
 	)
 
 	var msgs []string
@@ -197,14 +218,30 @@ func (a *App) diffMarkdown(text string) string {
 	return formattedTerraformOutput
 }
 
-func (a *App) planSucceededMessage(cfg *config.Project, out *terraform.Output) string {
+func (a *App) planSucceededMessage(
+	cfg *config.Project, out *terraform.Output, checks *planChecks, steps []*stepOutput, planURL string,
+) string {
 	msg := new(strings.Builder)
 	msg.WriteString(muPlanMeta)
 	msg.WriteString("\n:white_check_mark: **Plan Result**\n")
 	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n", cfg.Name, cfg.Dir, cfg.Workspace))
 	msg.WriteString("\n```\n")
 	msg.WriteString(out.Result)
-	msg.WriteString("\n```\n\n\n")
+	msg.WriteString("\n```\n\n")
+	if planURL != "" {
+		msg.WriteString(fmt.Sprintf("plan file: [download](%s)\n", planURL))
+	}
+	msg.WriteString("\n")
+	if len(steps) > 0 {
+		msg.WriteString("<details><summary>Show Steps</summary>\n\n")
+		msg.WriteString(a.formatSteps(steps))
+		msg.WriteString("</details>\n\n")
+	}
+	if !checks.isEmpty() {
+		msg.WriteString("<details><summary>Show Checks</summary>\n\n")
+		msg.WriteString(a.formatPlanChecks(checks))
+		msg.WriteString("</details>\n\n")
+	}
 	changeResult := a.formatDiffMarkdownChangeResult(out.ChangedResult)
 	if changeResult != nil {
 		msg.WriteString("<details><summary>Show Output</summary>\n\n")
@@ -213,10 +250,14 @@ func (a *App) planSucceededMessage(cfg *config.Project, out *terraform.Output) s
 		msg.WriteString("\n```\n</details>\n\n")
 	}
 	msg.WriteString("**next step**\n")
-	msg.WriteString("- To apply this plan, comment:\n")
-	msg.WriteString("  ```\n")
-	msg.WriteString(fmt.Sprintf("  mu apply -p %s\n", cfg.Name))
-	msg.WriteString("  ```\n")
+	if cfg.Apply.IsAfterMerge() {
+		msg.WriteString("- This plan is applied automatically after the pull request is merged.\n")
+	} else {
+		msg.WriteString("- To apply this plan, comment:\n")
+		msg.WriteString("  ```\n")
+		msg.WriteString(fmt.Sprintf("  mu apply -p %s\n", cfg.Name))
+		msg.WriteString("  ```\n")
+	}
 	msg.WriteString("- To delete this plan and lock, comment:\n")
 	msg.WriteString("  ```\n")
 	msg.WriteString(fmt.Sprintf("  mu unlock -p %s\n", cfg.Name))
@@ -243,7 +284,101 @@ func (a *App) planFailedMessage(cfg *config.Project, out *terraform.Output) stri
 	return msg.String()
 }
 
-func (a *App) applySucceededMessage(cfg *config.Project, out *terraform.Output) string {
+func (a *App) planChecksFailedMessage(cfg *config.Project, checks *planChecks) string {
+	msg := new(strings.Builder)
+	msg.WriteString(muPlanMeta)
+	msg.WriteString("\n:x: **Plan Checks Failed**\n")
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	msg.WriteString(a.formatPlanChecks(checks))
+	if checks.fmt != nil && checks.fmt.HasDiff {
+		msg.WriteString("**next step**\n")
+		msg.WriteString("- To commit the formatted files to this pull request, comment:\n")
+		msg.WriteString("  ```\n")
+		msg.WriteString(fmt.Sprintf("  mu fmt -p %s --fix\n", cfg.Name))
+		msg.WriteString("  ```\n")
+	}
+	return msg.String()
+}
+
+func (a *App) formatPlanChecks(checks *planChecks) string {
+	msg := new(strings.Builder)
+	if checks.validate != nil {
+		if checks.validate.HasError {
+			msg.WriteString(":x: `terraform validate`\n")
+			msg.WriteString(a.formatMarkdownAlert("CAUTION", checks.validate.Result))
+		} else {
+			msg.WriteString(":white_check_mark: `terraform validate`\n")
+		}
+		msg.WriteString("\n")
+	}
+	if checks.fmt != nil {
+		switch {
+		case checks.fmt.HasDiff:
+			msg.WriteString(":x: `terraform fmt -check`\n")
+			msg.WriteString("\n```diff\n")
+			msg.WriteString(strings.TrimSpace(checks.fmt.Result))
+			msg.WriteString("\n```\n")
+		case checks.fmt.HasError:
+			msg.WriteString(":x: `terraform fmt -check`\n")
+			msg.WriteString(a.formatMarkdownAlert("CAUTION", checks.fmt.Result))
+		default:
+			msg.WriteString(":white_check_mark: `terraform fmt -check`\n")
+		}
+		msg.WriteString("\n")
+	}
+	return msg.String()
+}
+
+func (a *App) fmtMessage(cfg *config.Project, ret *terraform.FmtOutput) string {
+	msg := new(strings.Builder)
+	msg.WriteString("## mu fmt -p " + cfg.Name)
+	msg.WriteString("\n")
+	if !ret.HasError {
+		msg.WriteString(":white_check_mark: All files are formatted.\n")
+		return msg.String()
+	}
+	if !ret.HasDiff {
+		msg.WriteString(":x: **Fmt Failed**\n")
+		msg.WriteString(a.formatMarkdownAlert("CAUTION", ret.Result))
+		return msg.String()
+	}
+	msg.WriteString(":x: Some files are not formatted.\n")
+	msg.WriteString("\n```diff\n")
+	msg.WriteString(strings.TrimSpace(ret.Result))
+	msg.WriteString("\n```\n\n")
+	msg.WriteString("- To commit the formatted files to this pull request, comment:\n")
+	msg.WriteString("  ```\n")
+	msg.WriteString(fmt.Sprintf("  mu fmt -p %s --fix\n", cfg.Name))
+	msg.WriteString("  ```\n")
+	return msg.String()
+}
+
+func (a *App) fmtFixedMessage(cfg *config.Project, files []string, sha string) string {
+	msg := new(strings.Builder)
+	msg.WriteString("## mu fmt -p " + cfg.Name + " --fix")
+	msg.WriteString("\n")
+	if len(files) == 0 {
+		msg.WriteString(":white_check_mark: All files are already formatted.\n")
+		return msg.String()
+	}
+	msg.WriteString(fmt.Sprintf(":white_check_mark: Committed the formatted files in %s.\n\n", sha))
+	for _, file := range files {
+		msg.WriteString(fmt.Sprintf("- `%s`\n", file))
+	}
+	return msg.String()
+}
+
+func (a *App) fmtFixRefusedMessage(cfg *config.Project, reason string) string {
+	msg := new(strings.Builder)
+	msg.WriteString("## mu fmt -p " + cfg.Name + " --fix")
+	msg.WriteString("\n")
+	msg.WriteString(":x: " + reason + "\n")
+	return msg.String()
+}
+
+func (a *App) applySucceededMessage(
+	cfg *config.Project, out *terraform.Output, values []*terraform.OutputValue, steps []*stepOutput,
+) string {
 	msg := new(strings.Builder)
 	msg.WriteString(muApplyMeta)
 	msg.WriteString("\n:white_check_mark: **Apply Result**\n")
@@ -251,6 +386,16 @@ func (a *App) applySucceededMessage(cfg *config.Project, out *terraform.Output)
 	msg.WriteString("\n```\n")
 	msg.WriteString(out.Result)
 	msg.WriteString("\n```\n")
+	if len(values) > 0 {
+		msg.WriteString("\n<details><summary>Show Outputs</summary>\n\n")
+		msg.WriteString(a.formatOutputValuesTable(values))
+		msg.WriteString("</details>\n\n")
+	}
+	if len(steps) > 0 {
+		msg.WriteString("\n<details><summary>Show Steps</summary>\n\n")
+		msg.WriteString(a.formatSteps(steps))
+		msg.WriteString("</details>\n\n")
+	}
 	warnResult := a.formatMarkdownAlert("WARNING", out.Warning)
 	if warnResult != "" {
 		msg.WriteString(warnResult)
@@ -259,6 +404,34 @@ func (a *App) applySucceededMessage(cfg *config.Project, out *terraform.Output)
 	return msg.String()
 }
 
+func (a *App) applyRefusedMessage(cfg *config.Project, reason error) string {
+	msg := new(strings.Builder)
+	msg.WriteString(muApplyMeta)
+	msg.WriteString("\n:x: **Apply Refused**\n")
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	msg.WriteString(reason.Error())
+	msg.WriteString("\n\n")
+	msg.WriteString("Please run `mu plan` again:\n")
+	msg.WriteString("```\n")
+	msg.WriteString(fmt.Sprintf("mu plan -p %s\n", cfg.Name))
+	msg.WriteString("```\n")
+	return msg.String()
+}
+
+func (a *App) decryptionFailedMessage(cfg *config.Project, reason error) string {
+	msg := new(strings.Builder)
+	msg.WriteString(muApplyMeta)
+	msg.WriteString("\n:x: **Plan Decryption Failed**\n")
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	msg.WriteString(reason.Error())
+	msg.WriteString("\n\n")
+	msg.WriteString("Make sure `plan_encryption_key` is the same key as the one used by `mu plan`, or run `mu plan` again:\n")
+	msg.WriteString("```\n")
+	msg.WriteString(fmt.Sprintf("mu plan -p %s\n", cfg.Name))
+	msg.WriteString("```\n")
+	return msg.String()
+}
+
 func (a *App) applyFailedMessage(cfg *config.Project, out *terraform.Output) string {
 	msg := new(strings.Builder)
 	msg.WriteString(muApplyMeta)
@@ -269,6 +442,39 @@ func (a *App) applyFailedMessage(cfg *config.Project, out *terraform.Output) str
 	return msg.String()
 }
 
+func (a *App) driftMessage(cfg *config.Project, out *terraform.Output) string {
+	header := new(strings.Builder)
+	header.WriteString(muDriftMeta)
+	header.WriteString("\n:warning: **Drift Detected**\n")
+	header.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	header.WriteString("\n```\n")
+	header.WriteString(out.Result)
+	header.WriteString("\n```\n\n")
+
+	footer := new(strings.Builder)
+	if sha := action.SHA(); sha != "" {
+		footer.WriteString(fmt.Sprintf("commit: %s\n", sha))
+	}
+	if url := action.RunURL(); url != "" {
+		footer.WriteString(fmt.Sprintf("run: %s\n", url))
+	}
+
+	details := new(strings.Builder)
+	changeResult := a.formatDiffMarkdownChangeResult(out.ChangedResult)
+	if changeResult != nil {
+		details.WriteString("<details><summary>Show Output</summary>\n\n")
+		details.WriteString("```diff\n")
+		details.WriteString(changeResult.String())
+		details.WriteString("\n```\n</details>\n\n")
+	}
+	// The issue body has the same length limit as comments, so the diff is omitted if it is too long.
+	if header.Len()+details.Len()+footer.Len() > vcs.MaxCommentLen {
+		details.Reset()
+		details.WriteString("The diff is too long to show. See the workflow run for details.\n\n")
+	}
+	return header.String() + details.String() + footer.String()
+}
+
 func (a *App) forceUnlockMessage(ret *terraform.ForceUnlockOutput) string {
 	msg := new(strings.Builder)
 	if ret.HasError {
@@ -296,6 +502,49 @@ func (a *App) importMessage(
 	return msg.String()
 }
 
+func (a *App) outputMessage(cfg *config.Project, values []*terraform.OutputValue) string {
+	msg := new(strings.Builder)
+	msg.WriteString("## mu output -p " + cfg.Name)
+	msg.WriteString("\n")
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	if len(values) == 0 {
+		msg.WriteString("No outputs found.\n")
+		return msg.String()
+	}
+	msg.WriteString(a.formatOutputValuesTable(values))
+	return msg.String()
+}
+
+func (a *App) outputFailedMessage(cfg *config.Project, log string) string {
+	msg := new(strings.Builder)
+	msg.WriteString(":x: **Output Failed**\n")
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	msg.WriteString(a.formatMarkdownAlert("CAUTION", log))
+	return msg.String()
+}
+
+func (a *App) formatOutputValuesTable(values []*terraform.OutputValue) string {
+	const sensitive = "*(sensitive value)*"
+	table := new(strings.Builder)
+	table.WriteString("| Name | Type | Value |\n")
+	table.WriteString("| --- | --- | --- |\n")
+	for _, value := range values {
+		v := sensitive
+		if !value.Sensitive {
+			v = "`" + escapeMarkdownTableCell(value.Value) + "`"
+		}
+		table.WriteString(fmt.Sprintf("| %s | %s | %s |\n",
+			escapeMarkdownTableCell(value.Name), escapeMarkdownTableCell(value.Type), v))
+	}
+	return table.String()
+}
+
+func escapeMarkdownTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	text = strings.ReplaceAll(text, "\r\n", " ")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
 func (a *App) stateRmMessage(address, log string) string {
 	msg := new(strings.Builder)
 	msg.WriteString("### " + address)
@@ -304,3 +553,54 @@ func (a *App) stateRmMessage(address, log string) string {
 	msg.WriteString("\n```\n")
 	return msg.String()
 }
+
+func (a *App) hookFailedMessage(stage string, cfg *config.Project, command string, out *hook.Output) string {
+	msg := new(strings.Builder)
+	switch stage {
+	case config.HookPreInit:
+		msg.WriteString(muInitMeta)
+	case config.HookPrePlan, config.HookPostPlan:
+		msg.WriteString(muPlanMeta)
+	default:
+		msg.WriteString(muApplyMeta)
+	}
+	msg.WriteString(fmt.Sprintf("\n:x: **Hook Failed** `%s`\n", stage))
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	msg.WriteString("```\n")
+	msg.WriteString(command)
+	msg.WriteString("\n```\n\n")
+	result := strings.TrimSpace(out.Result)
+	if result == "" {
+		result = out.Error.Error()
+	}
+	msg.WriteString(a.formatMarkdownAlert("CAUTION", result))
+	return msg.String()
+}
+
+func (a *App) stepFailedMessage(meta string, cfg *config.Project, steps []*stepOutput) string {
+	msg := new(strings.Builder)
+	msg.WriteString(meta)
+	msg.WriteString("\n:x: **Workflow Step Failed**\n")
+	msg.WriteString(fmt.Sprintf("project: `%s` dir: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Dir, cfg.Workspace))
+	msg.WriteString(a.formatSteps(steps))
+	return msg.String()
+}
+
+func (a *App) formatSteps(steps []*stepOutput) string {
+	msg := new(strings.Builder)
+	for _, step := range steps {
+		if step.hasError {
+			msg.WriteString(fmt.Sprintf(":x: `%s`\n", step.command))
+			msg.WriteString(a.formatMarkdownAlert("CAUTION", step.result))
+		} else {
+			msg.WriteString(fmt.Sprintf(":white_check_mark: `%s`\n", step.command))
+			if step.result != "" {
+				msg.WriteString("\n```\n")
+				msg.WriteString(step.result)
+				msg.WriteString("\n```\n")
+			}
+		}
+		msg.WriteString("\n")
+	}
+	return msg.String()
+}
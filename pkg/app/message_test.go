@@ -1,9 +1,14 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yu-icchi/mu/pkg/terraform"
 )
 
 func Test_splitMessages(t *testing.T) {
@@ -20,3 +25,25 @@ func TestApp_helpMessage(t *testing.T) {
 	msg := app.helpMessage()
 	fmt.Println(msg)
 }
+
+func TestApp_configErrorsMessage(t *testing.T) {
+	app := &App{configPath: ".github/mu.yaml"}
+	msg := app.configErrorsMessage(errors.New(".github/mu.yaml:3:5: project \"app\": plan is required"))
+	assert.True(t, strings.HasPrefix(msg, ":x: **Invalid Config**\n"))
+	assert.Contains(t, msg, "```\n.github/mu.yaml:3:5: project \"app\": plan is required\n```\n")
+}
+
+func TestApp_formatOutputValuesTable(t *testing.T) {
+	app := &App{}
+	values := []*terraform.OutputValue{
+		{Name: "endpoint", Type: "string", Value: "https://example.com"},
+		{Name: "ids", Type: `["list","string"]`, Value: `["a|b","c"]`},
+		{Name: "password", Type: "string", Value: "secret", Sensitive: true},
+	}
+	expect := "| Name | Type | Value |\n" +
+		"| --- | --- | --- |\n" +
+		"| endpoint | string | `https://example.com` |\n" +
+		"| ids | [\"list\",\"string\"] | `[\"a\\|b\",\"c\"]` |\n" +
+		"| password | string | *(sensitive value)* |\n"
+	assert.Equal(t, expect, app.formatOutputValuesTable(values))
+}
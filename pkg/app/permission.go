@@ -0,0 +1,264 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// permissionKeys returns the keys of config.Permissions which restrict the command.
+func permissionKeys(cmd command.Command) []string {
+	switch cmd := cmd.(type) {
+	case *command.Help:
+		return nil
+	case *command.Unlock:
+		if cmd.ForceUnlockID != "" {
+			return []string{string(command.UnlockType), config.PermissionForceUnlock}
+		}
+	}
+	return []string{string(cmd.Type())}
+}
+
+// commandProject returns the project specified by the command, or an empty string for the changed projects.
+func commandProject(cmd command.Command) string {
+	switch cmd := cmd.(type) {
+	case *command.Plan:
+		return cmd.Project
+	case *command.Apply:
+		return cmd.Project
+	case *command.Unlock:
+		return cmd.Project
+	case *command.Import:
+		return cmd.Project
+	case *command.StateRm:
+		return cmd.Project
+	case *command.Output:
+		return cmd.Project
+	case *command.Fmt:
+		return cmd.Project
+	default:
+		return ""
+	}
+}
+
+// authorize checks that the user can run the command on every project it targets.
+// The permissions are read from the config of the base branch, so that the pull request cannot loosen them,
+// and the commands on a project whose permissions are changed by the pull request are denied until it is merged.
+// If denied, it comments on the pull request and returns errPermissionDenied.
+func (a *App) authorize(ctx context.Context, prNum int, baseRef, user string, cfg *config.Config, cmd command.Command) error {
+	keys := permissionKeys(cmd)
+	if len(keys) == 0 {
+		return nil
+	}
+	base, err := a.loadBaseConfig(ctx, baseRef)
+	if err != nil {
+		return err
+	}
+
+	var projects config.Projects
+	if name := commandProject(cmd); name != "" {
+		projects = a.findProjectConfigs(cfg, name, "", nil)
+	} else {
+		modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
+		if err != nil {
+			return err
+		}
+		projects = a.findProjectConfigs(cfg, "", "", modifiedFiles)
+	}
+
+	checker := a.newPermissionChecker(user, base)
+	for _, project := range projects {
+		key, err := checker.deniedKey(ctx, project, keys)
+		if errors.Is(err, errPermissionsChanged) {
+			auditProject(ctx, project)
+			a.logger.Warn("permissions are changed by the pull request",
+				log.String("user", user),
+				log.String("project", project.Name),
+				log.Int("pr", prNum),
+			)
+			if err := a.vcs.CreateComment(ctx, prNum, a.permissionsChangedMessage(project.Name)); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %w of %s", errPermissionDenied, errPermissionsChanged, project.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			continue
+		}
+		auditProject(ctx, project)
+		a.logger.Warn("permission denied",
+			log.String("user", user),
+			log.String("command", key),
+			log.String("project", project.Name),
+			log.Int("pr", prNum),
+		)
+		if err := a.vcs.CreateComment(ctx, prNum, a.permissionDeniedMessage(user, key, project.Name)); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s is not allowed to run %s on %s", errPermissionDenied, user, key, project.Name)
+	}
+	return nil
+}
+
+// loadBaseConfig loads the config file from the base branch of the pull request, whose permissions are enforced.
+// It returns an empty config if the base branch has no config file.
+func (a *App) loadBaseConfig(ctx context.Context, baseRef string) (*config.Config, error) {
+	data, err := a.vcs.GetFileContent(ctx, a.repoConfigPath(), baseRef)
+	if err != nil {
+		if errors.Is(err, vcs.ErrNotFound) {
+			return &config.Config{}, nil
+		}
+		return nil, err
+	}
+	return config.Parse(a.configPath, data)
+}
+
+// basePermissions returns the permissions of the project in the config of the base branch, which are those of
+// the projects with the same name or dir, or else the template of terragrunt.discover which finds the dir,
+// or else the defaults. Every one of them must allow the command.
+func basePermissions(base *config.Config, project *config.Project) []config.Permissions {
+	var permissions []config.Permissions
+	for _, baseProject := range base.Projects {
+		if baseProject.Name == project.Name || baseProject.Dir == project.Dir {
+			permissions = append(permissions, baseProject.Permissions)
+		}
+	}
+	if len(permissions) > 0 {
+		return permissions
+	}
+	if base.Terragrunt != nil {
+		for _, discover := range base.Terragrunt.Discover {
+			if discover.Project != nil && strings.HasPrefix(project.Dir, path.Clean(discover.Dir)+"/") {
+				permissions = append(permissions, discover.Project.Permissions)
+			}
+		}
+	}
+	if len(permissions) > 0 {
+		return permissions
+	}
+	if base.Defaults != nil {
+		return []config.Permissions{base.Defaults.Permissions}
+	}
+	return []config.Permissions{nil}
+}
+
+func (a *App) permissionsChangedMessage(project string) string {
+	return fmt.Sprintf(":no_entry: This pull request changes the permissions of the `%s` project. "+
+		"The commands on the project are not allowed until the change is merged.", project)
+}
+
+func (a *App) permissionDeniedMessage(user, key, project string) string {
+	cmd := "mu " + key
+	if key == config.PermissionForceUnlock {
+		cmd = "mu unlock --force-unlock"
+	}
+	if user == "" {
+		user = "The user"
+	} else {
+		user = "@" + user
+	}
+	return fmt.Sprintf(":no_entry: %s is not allowed to run `%s` on the `%s` project.", user, cmd, project)
+}
+
+// permissionChecker checks the permissions of the user against the config of the base branch,
+// and caches the permission level and the team memberships looked up from the VCS.
+type permissionChecker struct {
+	vcs   vcs.VCS
+	base  *config.Config
+	user  string
+	level string
+	teams map[string]bool
+}
+
+func (a *App) newPermissionChecker(user string, base *config.Config) *permissionChecker {
+	return &permissionChecker{
+		vcs:   a.vcs,
+		base:  base,
+		user:  user,
+		teams: make(map[string]bool),
+	}
+}
+
+// deniedKey returns the first of the keys which the user is not allowed to run on the project,
+// or an empty string if every key is allowed.
+// It returns errPermissionsChanged if the permissions of the project differ from those of the base branch.
+func (c *permissionChecker) deniedKey(ctx context.Context, project *config.Project, keys []string) (string, error) {
+	permissions := basePermissions(c.base, project)
+	if !slices.ContainsFunc(permissions, func(permissions config.Permissions) bool {
+		return equalPermissions(permissions, project.Permissions)
+	}) {
+		return "", errPermissionsChanged
+	}
+	for _, key := range keys {
+		for _, permission := range permissions {
+			ok, err := c.check(ctx, permission.Get(key))
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				return key, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func equalPermissions(a, b config.Permissions) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// check reports whether the user matches any condition of the permission. A nil permission allows everyone.
+func (c *permissionChecker) check(ctx context.Context, permission *config.Permission) (bool, error) {
+	if permission == nil {
+		return true, nil
+	}
+	if c.user == "" {
+		return false, nil
+	}
+	if slices.ContainsFunc(permission.Users, func(user string) bool {
+		return strings.EqualFold(user, c.user)
+	}) {
+		return true, nil
+	}
+	if permission.Role != "" {
+		if c.level == "" {
+			level, err := c.vcs.GetPermissionLevel(ctx, c.user)
+			if err != nil {
+				return false, err
+			}
+			c.level = level
+		}
+		if vcs.HasPermission(c.level, permission.Role) {
+			return true, nil
+		}
+	}
+	for _, team := range permission.Teams {
+		member, ok := c.teams[team]
+		if !ok {
+			var err error
+			member, err = c.vcs.IsTeamMember(ctx, team, c.user)
+			if err != nil {
+				return false, err
+			}
+			c.teams[team] = member
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,324 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestPermissionKeys(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		cmd    command.Command
+		expect []string
+	}{
+		"apply": {
+			cmd:    &command.Apply{},
+			expect: []string{"apply"},
+		},
+		"state": {
+			cmd:    &command.StateRm{},
+			expect: []string{"state"},
+		},
+		"unlock": {
+			cmd:    &command.Unlock{},
+			expect: []string{"unlock"},
+		},
+		"force unlock": {
+			cmd:    &command.Unlock{ForceUnlockID: "lock-id"},
+			expect: []string{"unlock", "force_unlock"},
+		},
+		"help": {
+			cmd:    &command.Help{},
+			expect: nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expect, permissionKeys(tt.cmd))
+		})
+	}
+}
+
+const basePermissionsConfig = `version: 1
+projects:
+  - name: dev
+    dir: dev
+    plan:
+      paths: ["*.tf"]
+  - name: prod
+    dir: prod
+    plan:
+      paths: ["*.tf"]
+    permissions:
+      apply:
+        users: ["alice"]
+        teams: ["sre"]
+        role: maintain
+      force_unlock:
+        role: admin
+`
+
+func TestApp_authorize(t *testing.T) {
+	t.Parallel()
+	newConfig := func(permissions config.Permissions) *config.Config {
+		return &config.Config{
+			Projects: []*config.Project{
+				{
+					Name: "dev",
+					Dir:  "dev",
+					Plan: &config.Plan{Paths: []string{"*.tf"}},
+				},
+				{
+					Name:        "prod",
+					Dir:         "prod",
+					Plan:        &config.Plan{Paths: []string{"*.tf"}},
+					Permissions: permissions,
+				},
+			},
+		}
+	}
+	cfg := newConfig(config.Permissions{
+		"apply": {
+			Users: []string{"alice"},
+			Teams: []string{"sre"},
+			Role:  "maintain",
+		},
+		"force_unlock": {
+			Role: "admin",
+		},
+	})
+	baseConfig := func(ctx context.Context, m *mock) {
+		m.vcs.EXPECT().GetFileContent(ctx, "mu.yaml", "main").Return([]byte(basePermissionsConfig), nil)
+	}
+	tests := []struct {
+		name      string
+		user      string
+		cfg       *config.Config
+		cmd       command.Command
+		prepare   prepare
+		expectErr error
+	}{
+		{
+			name:    "help",
+			user:    "bob",
+			cmd:     &command.Help{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {},
+		},
+		{
+			name: "not restricted command",
+			user: "bob",
+			cmd:  &command.Plan{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+			},
+		},
+		{
+			name: "not restricted project",
+			user: "bob",
+			cmd:  &command.Apply{Project: "dev"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+			},
+		},
+		{
+			name: "allowed user",
+			user: "Alice",
+			cmd:  &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+			},
+		},
+		{
+			name: "allowed role",
+			user: "bob",
+			cmd:  &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().GetPermissionLevel(ctx, "bob").Return("admin", nil)
+			},
+		},
+		{
+			name: "allowed team of changed project",
+			user: "bob",
+			cmd:  &command.Apply{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().ListFiles(ctx, 1).Return([]string{"dev/main.tf", "prod/main.tf"}, nil)
+				m.vcs.EXPECT().GetPermissionLevel(ctx, "bob").Return("write", nil)
+				m.vcs.EXPECT().IsTeamMember(ctx, "sre", "bob").Return(true, nil)
+			},
+		},
+		{
+			name: "denied",
+			user: "bob",
+			cmd:  &command.Apply{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().ListFiles(ctx, 1).Return([]string{"prod/main.tf"}, nil)
+				m.vcs.EXPECT().GetPermissionLevel(ctx, "bob").Return("write", nil)
+				m.vcs.EXPECT().IsTeamMember(ctx, "sre", "bob").Return(false, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, ":no_entry: @bob is not allowed to run `mu apply` on the `prod` project.").Return(nil)
+			},
+			expectErr: errPermissionDenied,
+		},
+		{
+			name: "denied force unlock",
+			user: "alice",
+			cmd:  &command.Unlock{Project: "prod", ForceUnlockID: "lock-id"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().GetPermissionLevel(ctx, "alice").Return("maintain", nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, ":no_entry: @alice is not allowed to run `mu unlock --force-unlock` on the `prod` project.").Return(nil)
+			},
+			expectErr: errPermissionDenied,
+		},
+		{
+			name: "rule dropped by the pull request",
+			user: "bob",
+			cfg:  newConfig(nil),
+			cmd:  &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().CreateComment(ctx, 1, ":no_entry: This pull request changes the permissions of the `prod` project. "+
+					"The commands on the project are not allowed until the change is merged.").Return(nil)
+			},
+			expectErr: errPermissionDenied,
+		},
+		{
+			name: "rule loosened by the pull request",
+			user: "bob",
+			cfg: newConfig(config.Permissions{
+				"apply": {Users: []string{"alice", "bob"}},
+			}),
+			cmd: &command.Apply{},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().ListFiles(ctx, 1).Return([]string{"mu.yaml", "prod/main.tf"}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+			},
+			expectErr: errPermissionsChanged,
+		},
+		{
+			name: "rule added by the pull request",
+			user: "bob",
+			cfg: newConfig(config.Permissions{
+				"apply": {Users: []string{"alice"}},
+			}),
+			cmd: &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().GetFileContent(ctx, "mu.yaml", "main").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+			},
+			expectErr: errPermissionsChanged,
+		},
+		{
+			name: "unknown user",
+			user: "",
+			cmd:  &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().CreateComment(ctx, 1, ":no_entry: The user is not allowed to run `mu apply` on the `prod` project.").Return(nil)
+			},
+			expectErr: errPermissionDenied,
+		},
+		{
+			name: "failed to get permission level",
+			user: "bob",
+			cmd:  &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				baseConfig(ctx, m)
+				m.vcs.EXPECT().GetPermissionLevel(ctx, "bob").Return("", assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "failed to get base config",
+			user: "bob",
+			cmd:  &command.Apply{Project: "prod"},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().GetFileContent(ctx, "mu.yaml", "main").Return(nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			app, m := newTestAppAndMock(ctrl)
+			app.configPath = "mu.yaml"
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			prCfg := tt.cfg
+			if prCfg == nil {
+				prCfg = cfg
+			}
+			err := app.authorize(ctx, 1, "main", tt.user, prCfg, tt.cmd)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBasePermissions(t *testing.T) {
+	t.Parallel()
+	apply := config.Permissions{"apply": {Role: "admin"}}
+	state := config.Permissions{"state": {Role: "admin"}}
+	defaults := config.Permissions{"apply": {Role: "write"}}
+	base := &config.Config{
+		Projects: []*config.Project{
+			{Name: "prod", Dir: "prod", Permissions: apply},
+			{Name: "prod-state", Dir: "prod-state", Permissions: state},
+		},
+		Terragrunt: &config.Terragrunt{
+			Discover: []*config.Discover{
+				{Dir: "live", Project: &config.Project{Permissions: state}},
+			},
+		},
+		Defaults: &config.Project{Permissions: defaults},
+	}
+	tests := map[string]struct {
+		project *config.Project
+		expect  []config.Permissions
+	}{
+		"same name": {
+			project: &config.Project{Name: "prod", Dir: "other"},
+			expect:  []config.Permissions{apply},
+		},
+		"renamed": {
+			project: &config.Project{Name: "renamed", Dir: "prod"},
+			expect:  []config.Permissions{apply},
+		},
+		"moved to another project": {
+			project: &config.Project{Name: "prod", Dir: "prod-state"},
+			expect:  []config.Permissions{apply, state},
+		},
+		"terragrunt unit": {
+			project: &config.Project{Name: "live-vpc", Dir: "live/vpc"},
+			expect:  []config.Permissions{state},
+		},
+		"new project": {
+			project: &config.Project{Name: "new", Dir: "new"},
+			expect:  []config.Permissions{defaults},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expect, basePermissions(base, tt.project))
+		})
+	}
+	assert.Equal(t, []config.Permissions{nil}, basePermissions(&config.Config{}, &config.Project{Name: "new", Dir: "new"}))
+}
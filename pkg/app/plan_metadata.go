@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/yu-icchi/mu/pkg/terraform"
+)
+
+// planMetadata is stored next to the plan file in the artifact
+// to detect stale or tampered plans before apply.
+type planMetadata struct {
+	HeadSHA          string `json:"head_sha"`
+	BaseSHA          string `json:"base_sha"`
+	TerraformVersion string `json:"terraform_version"`
+	// Vars and VarFiles are those of the project config, without the ones given to `mu plan`.
+	Vars     []string `json:"vars"`
+	VarFiles []string `json:"var_files"`
+	Checksum string   `json:"checksum"`
+	// Signature is the HMAC-SHA256 of the other fields keyed with the plan signing key, which is not in the plan store,
+	// so that the plan file and its checksum cannot be replaced together. It is empty without the key.
+	Signature string `json:"signature,omitempty"`
+}
+
+// sign returns the signature of the metadata with the key.
+func (m *planMetadata) sign(key string) (string, error) {
+	unsigned := *m
+	unsigned.Signature = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (a *App) genPlanMetadataFilename(planFilename string) string {
+	return planFilename + ".meta.json"
+}
+
+// writePlanMetadata writes the metadata of the plan file into the project dir and returns the file path.
+func (a *App) writePlanMetadata(
+	ctx context.Context, tf terraform.Terraform, dir, planFilename string, meta *planMetadata,
+) (string, error) {
+	version, _, err := tf.Version(ctx)
+	if err != nil {
+		return "", err
+	}
+	checksum, err := fileChecksum(filepath.Join(dir, planFilename))
+	if err != nil {
+		return "", err
+	}
+	meta.TerraformVersion = version
+	meta.Checksum = checksum
+	if a.planSigningKey != "" {
+		signature, err := meta.sign(a.planSigningKey)
+		if err != nil {
+			return "", err
+		}
+		meta.Signature = signature
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, a.genPlanMetadataFilename(planFilename))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a *App) readPlanMetadata(dir, planFilename string) (*planMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(dir, a.genPlanMetadataFilename(planFilename)))
+	if err != nil {
+		return nil, err
+	}
+	meta := &planMetadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// verifyPlanMetadata returns an error describing why the plan file must not be applied.
+// current has the SHAs of the pull request, and the Terraform version and the vars of the project at apply.
+// The signature is verified first when the plan signing key is set, so that the other fields can be trusted.
+func (a *App) verifyPlanMetadata(meta *planMetadata, planFilePath string, current *planMetadata) error {
+	if a.planSigningKey != "" {
+		signature, err := meta.sign(a.planSigningKey)
+		if err != nil {
+			return err
+		}
+		if meta.Signature == "" || !hmac.Equal([]byte(meta.Signature), []byte(signature)) {
+			return fmt.Errorf("%w: the signature of the plan metadata does not match", errPlanFileTampered)
+		}
+	}
+	if meta.HeadSHA != current.HeadSHA {
+		return fmt.Errorf("%w: the head of the pull request has moved since the plan (planned: `%s`, current: `%s`)",
+			errStalePlan, meta.HeadSHA, current.HeadSHA)
+	}
+	if meta.BaseSHA != current.BaseSHA {
+		return fmt.Errorf("%w: the base branch has moved since the plan (planned: `%s`, current: `%s`)",
+			errStalePlan, meta.BaseSHA, current.BaseSHA)
+	}
+	if meta.TerraformVersion != current.TerraformVersion {
+		return fmt.Errorf("%w: the Terraform version has changed since the plan (planned: `%s`, current: `%s`)",
+			errStalePlan, meta.TerraformVersion, current.TerraformVersion)
+	}
+	if !slices.Equal(meta.Vars, current.Vars) {
+		return fmt.Errorf("%w: the vars of the project have changed since the plan", errStalePlan)
+	}
+	if !slices.Equal(meta.VarFiles, current.VarFiles) {
+		return fmt.Errorf("%w: the var files of the project have changed since the plan", errStalePlan)
+	}
+	checksum, err := fileChecksum(planFilePath)
+	if err != nil {
+		return err
+	}
+	if meta.Checksum != checksum {
+		return fmt.Errorf("%w: the checksum of the plan file does not match", errPlanFileTampered)
+	}
+	return nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApp_writePlanMetadata(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app, mock := newTestAppAndMock(ctrl)
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.tfplan"), []byte("plan data"), 0644))
+	mock.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+
+	path, err := app.writePlanMetadata(ctx, mock.terraform, dir, "test.tfplan", &planMetadata{
+		HeadSHA:  "test-sha",
+		BaseSHA:  "test-base-sha",
+		Vars:     []string{"key=value"},
+		VarFiles: []string{"test.tfvars"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "test.tfplan.meta.json"), path)
+
+	meta, err := app.readPlanMetadata(dir, "test.tfplan")
+	require.NoError(t, err)
+	expect := &planMetadata{
+		HeadSHA:          "test-sha",
+		BaseSHA:          "test-base-sha",
+		TerraformVersion: "1.9.1",
+		Vars:             []string{"key=value"},
+		VarFiles:         []string{"test.tfvars"},
+		Checksum:         "afe80689da6e5d5d072cf0f93e5197b2fff0f3edf9046f0c6004bc61f9ff464b",
+	}
+	assert.Equal(t, expect, meta)
+}
+
+func TestApp_writePlanMetadata_Signed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	app, mock := newTestAppAndMock(ctrl)
+	app.planSigningKey = "test-key"
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.tfplan"), []byte("plan data"), 0644))
+	mock.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+
+	_, err := app.writePlanMetadata(ctx, mock.terraform, dir, "test.tfplan", &planMetadata{
+		HeadSHA: "test-sha",
+		BaseSHA: "test-base-sha",
+	})
+	require.NoError(t, err)
+	meta, err := app.readPlanMetadata(dir, "test.tfplan")
+	require.NoError(t, err)
+	assert.NotEmpty(t, meta.Signature)
+
+	signature, err := meta.sign("test-key")
+	require.NoError(t, err)
+	assert.Equal(t, signature, meta.Signature)
+	signature, err = meta.sign("other-key")
+	require.NoError(t, err)
+	assert.NotEqual(t, signature, meta.Signature)
+}
+
+func TestApp_verifyPlanMetadata(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	planFilePath := filepath.Join(dir, "test.tfplan")
+	require.NoError(t, os.WriteFile(planFilePath, []byte("plan data"), 0644))
+	const checksum = "afe80689da6e5d5d072cf0f93e5197b2fff0f3edf9046f0c6004bc61f9ff464b"
+	newMeta := func(update func(meta *planMetadata)) *planMetadata {
+		meta := &planMetadata{
+			HeadSHA:          "test-sha",
+			BaseSHA:          "test-base-sha",
+			TerraformVersion: "1.9.1",
+			Vars:             []string{"key=value"},
+			VarFiles:         []string{"test.tfvars"},
+			Checksum:         checksum,
+		}
+		update(meta)
+		return meta
+	}
+	signed := func(key string, update func(meta *planMetadata)) *planMetadata {
+		meta := newMeta(update)
+		signature, err := meta.sign(key)
+		require.NoError(t, err)
+		meta.Signature = signature
+		return meta
+	}
+	tests := []struct {
+		name       string
+		signingKey string
+		meta       *planMetadata
+		expectErr  error
+	}{
+		{
+			name: "success",
+			meta: newMeta(func(meta *planMetadata) {}),
+		},
+		{
+			name: "head moved",
+			meta: newMeta(func(meta *planMetadata) {
+				meta.HeadSHA = "test-old-sha"
+			}),
+			expectErr: errStalePlan,
+		},
+		{
+			name: "base moved",
+			meta: newMeta(func(meta *planMetadata) {
+				meta.BaseSHA = "test-old-base-sha"
+			}),
+			expectErr: errStalePlan,
+		},
+		{
+			name: "terraform version changed",
+			meta: newMeta(func(meta *planMetadata) {
+				meta.TerraformVersion = "1.8.0"
+			}),
+			expectErr: errStalePlan,
+		},
+		{
+			name: "vars changed",
+			meta: newMeta(func(meta *planMetadata) {
+				meta.Vars = []string{"key=old"}
+			}),
+			expectErr: errStalePlan,
+		},
+		{
+			name: "var files changed",
+			meta: newMeta(func(meta *planMetadata) {
+				meta.VarFiles = nil
+			}),
+			expectErr: errStalePlan,
+		},
+		{
+			name: "tampered",
+			meta: newMeta(func(meta *planMetadata) {
+				meta.Checksum = "invalid"
+			}),
+			expectErr: errPlanFileTampered,
+		},
+		{
+			name:       "signed",
+			signingKey: "test-key",
+			meta:       signed("test-key", func(meta *planMetadata) {}),
+		},
+		{
+			name:       "signed with another key",
+			signingKey: "test-key",
+			meta:       signed("other-key", func(meta *planMetadata) {}),
+			expectErr:  errPlanFileTampered,
+		},
+		{
+			name:       "not signed",
+			signingKey: "test-key",
+			meta:       newMeta(func(meta *planMetadata) {}),
+			expectErr:  errPlanFileTampered,
+		},
+		{
+			name:       "checksum rewritten after signing",
+			signingKey: "test-key",
+			meta: func() *planMetadata {
+				meta := signed("test-key", func(meta *planMetadata) {})
+				meta.Checksum = "afe80689da6e5d5d072cf0f93e5197b2fff0f3edf9046f0c6004bc61f9ff0000"
+				return meta
+			}(),
+			expectErr: errPlanFileTampered,
+		},
+	}
+	current := newMeta(func(meta *planMetadata) {})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			app := &App{planSigningKey: tt.signingKey}
+			err := app.verifyPlanMetadata(tt.meta, planFilePath, current)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -6,8 +6,8 @@ import (
 
 	"github.com/yu-icchi/mu/pkg/action"
 	"github.com/yu-icchi/mu/pkg/command"
-	"github.com/yu-icchi/mu/pkg/github"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func (a *App) genStatusSource(commandType command.Type, projectName string) string {
@@ -18,14 +18,14 @@ func (a *App) updatePendingStatus(ctx context.Context, sha, projectName string,
 	const desc = "in progress..."
 	url := action.RunURL()
 	src := a.genStatusSource(commandType, projectName)
-	commitStatus := &github.CommitStatus{
+	commitStatus := &vcs.CommitStatus{
 		Sha:       sha,
-		Status:    github.PendingStatus,
+		Status:    vcs.PendingStatus,
 		TargetURL: url,
 		Desc:      desc,
 		Context:   src,
 	}
-	return a.github.CreateCommitStatus(ctx, commitStatus)
+	return a.vcs.CreateCommitStatus(ctx, commitStatus)
 }
 
 func (a *App) updateSuccessStatus(ctx context.Context, sha, projectName string, commandType command.Type, output *terraform.Output) error {
@@ -37,27 +37,31 @@ func (a *App) updateSuccessStatus(ctx context.Context, sha, projectName string,
 		desc = output.Result
 	case command.ApplyType:
 		desc = "Apply succeeded."
+	case command.ValidateType:
+		desc = "Validation succeeded."
+	case command.FmtType:
+		desc = "Formatting check passed."
 	}
-	commitStatus := &github.CommitStatus{
+	commitStatus := &vcs.CommitStatus{
 		Sha:       sha,
-		Status:    github.SuccessStatus,
+		Status:    vcs.SuccessStatus,
 		TargetURL: url,
 		Desc:      desc,
 		Context:   src,
 	}
-	return a.github.CreateCommitStatus(ctx, commitStatus)
+	return a.vcs.CreateCommitStatus(ctx, commitStatus)
 }
 
 func (a *App) updateFailureStatus(ctx context.Context, sha, projectName string, commandType command.Type) error {
 	const desc = "failed."
 	url := action.RunURL()
 	src := a.genStatusSource(commandType, projectName)
-	commitStatus := &github.CommitStatus{
+	commitStatus := &vcs.CommitStatus{
 		Sha:       sha,
-		Status:    github.FailureStatus,
+		Status:    vcs.FailureStatus,
 		TargetURL: url,
 		Desc:      desc,
 		Context:   src,
 	}
-	return a.github.CreateCommitStatus(ctx, commitStatus)
+	return a.vcs.CreateCommitStatus(ctx, commitStatus)
 }
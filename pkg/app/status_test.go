@@ -9,8 +9,8 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/yu-icchi/mu/pkg/command"
-	"github.com/yu-icchi/mu/pkg/github"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func TestApp_updatePendingStatus(t *testing.T) {
@@ -37,9 +37,9 @@ func TestApp_updatePendingStatus(t *testing.T) {
 				commandType: command.PlanType,
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "in progress...",
 					Context:   "mu/plan: test-project",
@@ -56,9 +56,9 @@ func TestApp_updatePendingStatus(t *testing.T) {
 				commandType: command.ApplyType,
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "in progress...",
 					Context:   "mu/apply: test-project",
@@ -75,9 +75,9 @@ func TestApp_updatePendingStatus(t *testing.T) {
 				commandType: command.ApplyType,
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "in progress...",
 					Context:   "mu/apply: test-project",
@@ -132,9 +132,9 @@ func TestApp_updateSuccessStatus(t *testing.T) {
 				},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "Plan: 1 to add, 0 to change, 0 to destroy.",
 					Context:   "mu/plan: test-project",
@@ -154,9 +154,9 @@ func TestApp_updateSuccessStatus(t *testing.T) {
 				},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "No changes. Your infrastructure matches the configuration.",
 					Context:   "mu/plan: test-project",
@@ -176,9 +176,9 @@ func TestApp_updateSuccessStatus(t *testing.T) {
 				},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "Apply succeeded.",
 					Context:   "mu/apply: test-project",
@@ -198,9 +198,9 @@ func TestApp_updateSuccessStatus(t *testing.T) {
 				},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "Apply succeeded.",
 					Context:   "mu/apply: test-project",
@@ -251,9 +251,9 @@ func TestApp_updateFailureStatus(t *testing.T) {
 				commandType: command.PlanType,
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "failed.",
 					Context:   "mu/plan: test-project",
@@ -270,9 +270,9 @@ func TestApp_updateFailureStatus(t *testing.T) {
 				commandType: command.ApplyType,
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "failed.",
 					Context:   "mu/apply: test-project",
@@ -289,9 +289,9 @@ func TestApp_updateFailureStatus(t *testing.T) {
 				commandType: command.PlanType,
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: "https://github.com/test_repo/actions/runs/test_run_id",
 					Desc:      "failed.",
 					Context:   "mu/plan: test-project",
@@ -2,17 +2,17 @@ package app
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/yu-icchi/mu/pkg/action"
-	"github.com/yu-icchi/mu/pkg/artifact"
 	"github.com/yu-icchi/mu/pkg/command"
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
 	"github.com/yu-icchi/mu/pkg/log"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 type (
@@ -23,6 +23,7 @@ type (
 		Mode      string `json:"mode"`
 		Result    string `json:"result"`
 		ActionURL string `json:"action_url"`
+		PlanURL   string `json:"plan_url,omitempty"`
 	}
 	OutputProjects []*OutputProject
 )
@@ -40,7 +41,7 @@ func (a *App) executeTerraformAutoPlan(
 
 	// Duplicate execution prevention
 	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
-		if github.IsErrAlreadyExists(err) {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
 			if err := a.outputFailedProgress(ctx, prNum); err != nil {
 				return err
 			}
@@ -54,13 +55,20 @@ func (a *App) executeTerraformAutoPlan(
 		}
 	}()
 
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
+	if err != nil {
+		return err
+	}
+
 	outputProjects := make(OutputProjects, 0, len(projects))
-	artifacts := make([]*artifact.Artifact, 0, len(projects))
+	comments := &planComments{}
 	for _, project := range projects {
-		out, err := a.tfPlan(ctx, prNum, sha, project, &command.Plan{})
+		out, err := a.tfPlan(ctx, prNum, sha, pr.BaseSHA, project, &command.Plan{})
 		if err != nil {
-			return err
+			// The results of the projects planned so far are still commented.
+			return errors.Join(err, a.outputPlanSucceededResults(ctx, prNum, comments))
 		}
+		comments.add(project.Name, out.comment)
 		outputProjects = append(outputProjects, &OutputProject{
 			Name:      project.Name,
 			Dir:       project.Dir,
@@ -68,34 +76,21 @@ func (a *App) executeTerraformAutoPlan(
 			Mode:      "plan",
 			Result:    out.result,
 			ActionURL: action.RunURL(),
-		})
-		artifacts = append(artifacts, &artifact.Artifact{
-			Name:      a.genArtifactName(project.Name, project.Workspace, prNum),
-			Path:      out.path,
-			Overwrite: true,
+			PlanURL:   out.planURL,
 		})
 	}
 
-	outputProjectsStr, err := json.Marshal(outputProjects)
-	if err != nil {
+	if err := a.outputPlanSucceededResults(ctx, prNum, comments); err != nil {
 		return err
 	}
-	_ = a.action.Output("projects", string(outputProjectsStr))
-
-	err = artifact.UploadArtifacts(&artifact.UploadArtifactParams{
-		Version:   a.uploadArtifactVersion,
-		Dir:       a.uploadArtifactDir,
-		Artifacts: artifacts,
-	})
-	if err != nil {
+	if err := a.outputProjects(ctx, outputProjects); err != nil {
 		return err
 	}
-	_ = a.action.Output("upload_artifact", "true")
 	return nil
 }
 
 func (a *App) findAutoPlanProjects(ctx context.Context, prNum int, cfg *config.Config) ([]*config.Project, error) {
-	modifiedFiles, err := a.github.ListFiles(ctx, prNum)
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +111,7 @@ func (a *App) executeTerraformPlan(
 ) error {
 	// Duplicate execution prevention
 	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
-		if github.IsErrAlreadyExists(err) {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
 			if err := a.outputFailedProgress(ctx, prNum); err != nil {
 				return err
 			}
@@ -130,22 +125,26 @@ func (a *App) executeTerraformPlan(
 		}
 	}()
 
-	modifiedFiles, err := a.github.ListFiles(ctx, prNum)
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
 	if err != nil {
 		return err
 	}
 
-	projects := a.findProjectConfigs(cfg, cmd.Project, modifiedFiles)
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
 	if len(projects) == 0 {
 		const msg = "There is no project to run `mu plan` on."
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
 	}
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
+	if err != nil {
+		return err
+	}
 
 	outputProjects := make(OutputProjects, 0, len(projects))
-	artifacts := make([]*artifact.Artifact, 0, len(projects))
+	comments := &planComments{}
 	for _, project := range projects {
 		// If a specific project is specified, the terraform plan may proceed regardless of the actual changes.
 		if !project.HasModifiedFiles(modifiedFiles) {
@@ -153,10 +152,12 @@ func (a *App) executeTerraformPlan(
 			continue
 		}
 
-		out, err := a.tfPlan(ctx, prNum, sha, project, cmd)
+		out, err := a.tfPlan(ctx, prNum, sha, pr.BaseSHA, project, cmd)
 		if err != nil {
-			return err
+			// The results of the projects planned so far are still commented.
+			return errors.Join(err, a.outputPlanSucceededResults(ctx, prNum, comments))
 		}
+		comments.add(project.Name, out.comment)
 		outputProjects = append(outputProjects, &OutputProject{
 			Name:      project.Name,
 			Dir:       project.Dir,
@@ -164,51 +165,40 @@ func (a *App) executeTerraformPlan(
 			Mode:      "plan",
 			Result:    out.result,
 			ActionURL: action.RunURL(),
-		})
-		artifacts = append(artifacts, &artifact.Artifact{
-			Name:      a.genArtifactName(project.Name, project.Workspace, prNum),
-			Path:      out.path,
-			Overwrite: true,
+			PlanURL:   out.planURL,
 		})
 	}
 	if len(outputProjects) == 0 {
 		const msg = "The specified project could not be found."
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	outputProjectsStr, err := json.Marshal(outputProjects)
-	if err != nil {
+	if err := a.outputPlanSucceededResults(ctx, prNum, comments); err != nil {
 		return err
 	}
-	_ = a.action.Output("projects", string(outputProjectsStr))
-
-	err = artifact.UploadArtifacts(&artifact.UploadArtifactParams{
-		Version:   a.uploadArtifactVersion,
-		Dir:       a.uploadArtifactDir,
-		Artifacts: artifacts,
-	})
-	if err != nil {
+	if err := a.outputProjects(ctx, outputProjects); err != nil {
 		return err
 	}
-	_ = a.action.Output("upload_artifact", "true")
 	return nil
 }
 
+// findProjectConfigs returns the projects of the name, or the projects changed by modifiedFiles if the name is empty.
+// The projects are every workspace of the project unless workspace is given.
 func (a *App) findProjectConfigs(
-	cfg *config.Config, project string, modifiedFiles []string,
+	cfg *config.Config, project, workspace string, modifiedFiles []string,
 ) config.Projects {
+	if project != "" {
+		return cfg.GetProjects(project, workspace)
+	}
 	projects := make([]*config.Project, 0, len(cfg.Projects))
-	if project == "" {
-		for _, prj := range cfg.Projects {
-			if prj.Plan.HasMatchedPaths(prj.Dir, modifiedFiles) {
-				projects = append(projects, prj)
-			}
+	for _, prj := range cfg.Projects {
+		if workspace != "" && prj.Workspace != workspace {
+			continue
 		}
-	} else {
-		if prj := cfg.GetProject(project); prj != nil {
+		if prj.Plan.HasMatchedPaths(prj.Dir, modifiedFiles) {
 			projects = append(projects, prj)
 		}
 	}
@@ -217,10 +207,18 @@ func (a *App) findProjectConfigs(
 
 func (a *App) executeTerraformApply(
 	ctx context.Context, prNum int, sha string, cfg *config.Config, cmd *command.Apply,
+) error {
+	return a.applyProjects(ctx, prNum, sha, cfg, cmd, nil)
+}
+
+// applyProjects applies the projects selected by cmd. If filter is not nil, only the projects for which it returns true are applied.
+func (a *App) applyProjects(
+	ctx context.Context, prNum int, sha string, cfg *config.Config, cmd *command.Apply,
+	filter func(project *config.Project) bool,
 ) error {
 	// Duplicate execution prevention
 	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
-		if github.IsErrAlreadyExists(err) {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
 			if err := a.outputFailedProgress(ctx, prNum); err != nil {
 				return err
 			}
@@ -234,46 +232,51 @@ func (a *App) executeTerraformApply(
 		}
 	}()
 
-	modifiedFiles, err := a.github.ListFiles(ctx, prNum)
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
+	if err != nil {
+		return err
+	}
+	reviews, err := a.vcs.ListReviews(ctx, prNum)
 	if err != nil {
 		return err
 	}
-	reviews, err := a.github.ListReviews(ctx, prNum)
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
 	if err != nil {
 		return err
 	}
 
-	projects := a.findProjectConfigs(cfg, cmd.Project, modifiedFiles)
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
 	if len(projects) == 0 {
 		const msg = "There is no project to plan."
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	artifactNames := make([]string, 0, len(projects))
-	for _, project := range projects {
-		artifactName := a.genArtifactName(project.Name, project.Workspace, prNum)
-		artifactNames = append(artifactNames, artifactName)
-	}
-	artifacts, err := a.github.MultiGetArtifactsByNames(ctx, artifactNames)
-	if err != nil {
-		return err
-	}
-
 	outputProjects := make(OutputProjects, 0, len(projects))
 	deleteArtifactNames := make([]string, 0, len(projects))
+	var afterMergeProjects int
 	for _, project := range projects {
+		if filter != nil && !filter(project) {
+			continue
+		}
 		// If a specific project is specified, the terraform apply may proceed regardless of the actual changes.
 		if !project.HasModifiedFiles(modifiedFiles) {
 			a.logger.Info("Not found", log.String("project", cmd.Project))
 			continue
 		}
+		if project.Apply.IsAfterMerge() {
+			msg := fmt.Sprintf(":warning: The `%s` project is applied after the pull request is merged.", project.ID())
+			if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+				return err
+			}
+			afterMergeProjects++
+			continue
+		}
 
 		artifactName := a.genArtifactName(project.Name, project.Workspace, prNum)
-		artifactFile := artifacts.Get(artifactName)
-		out, err := a.tfApply(ctx, prNum, sha, project, artifactFile, reviews)
+		out, err := a.tfApply(ctx, prNum, sha, pr.BaseSHA, project, reviews)
 		if err != nil {
 			return err
 		}
@@ -287,23 +290,28 @@ func (a *App) executeTerraformApply(
 		})
 		deleteArtifactNames = append(deleteArtifactNames, artifactName)
 	}
+	if len(outputProjects) == 0 && afterMergeProjects > 0 {
+		return nil
+	}
 	if len(outputProjects) == 0 {
 		const msg = "The specified project could not be found."
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	outputProjectsStr, err := json.Marshal(outputProjects)
-	if err != nil {
+	if err := a.outputProjects(ctx, outputProjects); err != nil {
 		return err
 	}
-	_ = a.action.Output("projects", string(outputProjectsStr))
 
-	if err := a.github.DeleteArtifactsByNames(ctx, deleteArtifactNames); err != nil {
+	if err := a.planStore.Delete(ctx, deleteArtifactNames); err != nil {
 		return err
 	}
+
+	if cfg.Automerge.GetEnabled() {
+		return a.automerge(ctx, prNum, sha, cfg, modifiedFiles)
+	}
 	return nil
 }
 
@@ -312,7 +320,7 @@ func (a *App) executeTerraformImport(
 ) error {
 	// Duplicate execution prevention
 	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
-		if github.IsErrAlreadyExists(err) {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
 			if err := a.outputFailedProgress(ctx, prNum); err != nil {
 				return err
 			}
@@ -326,15 +334,15 @@ func (a *App) executeTerraformImport(
 		}
 	}()
 
-	modifiedFiles, err := a.github.ListFiles(ctx, prNum)
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
 	if err != nil {
 		return err
 	}
 
-	projects := a.findProjectConfigs(cfg, cmd.Project, modifiedFiles)
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
 	if len(projects) != 1 {
 		const msg = "Please limit to one target project."
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
@@ -343,11 +351,11 @@ func (a *App) executeTerraformImport(
 
 	artifactName := a.genArtifactName(project.Name, project.Workspace, prNum)
 	artifactNames := []string{artifactName}
-	artifacts, err := a.github.MultiGetArtifactsByNames(ctx, artifactNames)
+	storedNames, err := a.planStore.List(ctx, artifactName)
 	if err != nil {
 		return err
 	}
-	if artifactFile := artifacts.Get(artifactName); artifactFile == nil {
+	if !slices.Contains(storedNames, artifactName) {
 		return errNotFoundPlanFile
 	}
 
@@ -356,7 +364,7 @@ func (a *App) executeTerraformImport(
 	}
 
 	// The Terraform state file may have changed, so the plan file will be deleted.
-	if err := a.github.DeleteArtifactsByNames(ctx, artifactNames); err != nil {
+	if err := a.planStore.Delete(ctx, artifactNames); err != nil {
 		return err
 	}
 	return nil
@@ -367,7 +375,7 @@ func (a *App) executeTerraformStateRm(
 ) error {
 	// Duplicate execution prevention
 	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
-		if github.IsErrAlreadyExists(err) {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
 			if err := a.outputFailedProgress(ctx, prNum); err != nil {
 				return err
 			}
@@ -381,15 +389,15 @@ func (a *App) executeTerraformStateRm(
 		}
 	}()
 
-	modifiedFiles, err := a.github.ListFiles(ctx, prNum)
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
 	if err != nil {
 		return err
 	}
 
-	projects := a.findProjectConfigs(cfg, cmd.Project, modifiedFiles)
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
 	if len(projects) != 1 {
 		const msg = "Please limit to one target project."
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 		return nil
@@ -401,7 +409,94 @@ func (a *App) executeTerraformStateRm(
 	return nil
 }
 
+func (a *App) executeTerraformOutput(
+	ctx context.Context, prNum int, sha string, cfg *config.Config, cmd *command.Output,
+) error {
+	// Duplicate execution prevention
+	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
+			if err := a.outputFailedProgress(ctx, prNum); err != nil {
+				return err
+			}
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		if err := a.deleteProgressLabel(ctx, prNum); err != nil {
+			a.logger.Error(err.Error())
+		}
+	}()
+
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
+	if err != nil {
+		return err
+	}
+
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
+	if len(projects) != 1 {
+		const msg = "Please limit to one target project."
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := a.tfOutput(ctx, prNum, projects[0], cmd); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *App) executeTerraformFmt(
+	ctx context.Context, prNum int, sha string, cfg *config.Config, cmd *command.Fmt,
+) error {
+	// Duplicate execution prevention
+	if err := a.createProgressLabel(ctx, prNum, sha); err != nil {
+		if errors.Is(err, vcs.ErrAlreadyExists) {
+			if err := a.outputFailedProgress(ctx, prNum); err != nil {
+				return err
+			}
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		if err := a.deleteProgressLabel(ctx, prNum); err != nil {
+			a.logger.Error(err.Error())
+		}
+	}()
+
+	modifiedFiles, err := a.vcs.ListFiles(ctx, prNum)
+	if err != nil {
+		return err
+	}
+
+	projects := a.findProjectConfigs(cfg, cmd.Project, cmd.Workspace, modifiedFiles)
+	if len(projects) != 1 {
+		const msg = "Please limit to one target project."
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	pr, err := a.vcs.GetPullRequest(ctx, prNum)
+	if err != nil {
+		return err
+	}
+	if err := a.tfFmt(ctx, pr, projects[0], cmd); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (a *App) genTerraform(cfg *config.Project) terraform.Terraform {
+	return a.genTerraformIn(cfg, a.projectDir(cfg))
+}
+
+// genTerraformIn returns the terraform of the project which runs in dir instead of the project directory.
+func (a *App) genTerraformIn(cfg *config.Project, dir string) terraform.Terraform {
 	if a.terraform != nil {
 		return a.terraform
 	}
@@ -410,9 +505,11 @@ func (a *App) genTerraform(cfg *config.Project) terraform.Terraform {
 		version = terraform.LatestVersion
 	}
 	return terraform.New(&terraform.Params{
-		Version:  version,
-		WorkDir:  cfg.Dir,
-		ExecPath: cfg.Terraform.GetExecPath(),
+		Version:         version,
+		WorkDir:         dir,
+		ExecPath:        cfg.Terraform.GetExecPath(),
+		Wrapper:         cfg.Terraform.GetWrapper(),
+		WrapperExecPath: cfg.Terraform.GetWrapperExecPath(),
 	})
 }
 
@@ -436,21 +533,21 @@ func (a *App) createProgressLabel(ctx context.Context, prNum int, sha string) er
 	if sha != "" {
 		desc = fmt.Sprintf("commit: %s", sha)
 	}
-	if err := a.github.CreateLabel(ctx, label, desc, ""); err != nil {
+	if err := a.vcs.CreateLabel(ctx, label, desc, ""); err != nil {
 		return err
 	}
-	return a.github.AddPullRequestLabels(ctx, prNum, []string{label})
+	return a.vcs.AddPullRequestLabels(ctx, prNum, []string{label})
 }
 
 func (a *App) deleteProgressLabel(ctx context.Context, prNum int) error {
 	label := a.genProgressLabel(prNum)
-	return a.github.DeleteLabel(ctx, label)
+	return a.vcs.DeleteLabel(ctx, label)
 }
 
 func (a *App) outputFailedProgress(ctx context.Context, prNum int) error {
 	label := a.genProgressLabel(prNum)
 	msg := fmt.Sprintf("Error: The operation was canceled because #%d is currently in progress. Please remove the %q label to retry.", prNum, label)
-	return a.github.CreateIssueComment(ctx, prNum, msg)
+	return a.vcs.CreateComment(ctx, prNum, msg)
 }
 
 func (a *App) outputInitFailedSummary(cfg *config.Project, log string) {
@@ -472,7 +569,7 @@ func (a *App) outputInitFailedResult(
 	comment := a.initFailedMessage(cfg, out)
 	messages := a.splitMessages(strings.NewReader(comment))
 	for _, msg := range messages {
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 	}
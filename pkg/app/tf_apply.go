@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,9 +10,11 @@ import (
 
 	"github.com/yu-icchi/mu/pkg/command"
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/encryption"
 	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/planstore"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 type outputApply struct {
@@ -19,9 +22,12 @@ type outputApply struct {
 }
 
 func (a *App) tfApply(
-	ctx context.Context, prNum int, sha string,
-	projectCfg *config.Project, artifact *github.Artifact, reviews github.Reviews,
+	ctx context.Context, prNum int, sha, baseSHA string,
+	projectCfg *config.Project, reviews vcs.Reviews,
 ) (out *outputApply, err error) {
+	defer a.lockProject(projectCfg)()
+	auditProject(ctx, projectCfg)
+
 	defer func() {
 		rec := recover()
 		if err == nil && rec == nil {
@@ -31,7 +37,7 @@ func (a *App) tfApply(
 			err = fmt.Errorf("%w: %s", errPanicOccurred, rec)
 			a.logger.Debug(fmt.Sprintf("apply: %+v", rec))
 		}
-		if err := a.updateFailureStatus(ctx, sha, projectCfg.Name, command.ApplyType); err != nil {
+		if err := a.updateFailureStatus(ctx, sha, projectCfg.ID(), command.ApplyType); err != nil {
 			a.logger.Error("failed to update status", log.Error(err))
 		}
 	}()
@@ -39,7 +45,7 @@ func (a *App) tfApply(
 	if requireApprovals := projectCfg.Apply.GetRequireApprovals(); requireApprovals > 0 {
 		if approvals := reviews.Approves(); requireApprovals > approvals {
 			msg := fmt.Sprintf(":x: At least %d approvals are required before running `mu apply`.", requireApprovals)
-			if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+			if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 				return nil, err
 			}
 			return nil, fmt.Errorf("not enough approve: require_approvals: %d, count: %d: %w",
@@ -47,27 +53,27 @@ func (a *App) tfApply(
 		}
 	}
 
-	if err := a.lock(ctx, projectCfg.Name, prNum, command.ApplyType, projectCfg.LockLabelColor); err != nil {
+	if err := a.lock(ctx, projectCfg.ID(), prNum, command.ApplyType, projectCfg.LockLabelColor); err != nil {
 		return nil, err
 	}
-	if err := a.updatePendingStatus(ctx, sha, projectCfg.Name, command.ApplyType); err != nil {
+	if err := a.updatePendingStatus(ctx, sha, projectCfg.ID(), command.ApplyType); err != nil {
 		return nil, err
 	}
 
-	if artifact == nil {
-		const msgTemp = "%s\nThe plan file for the `%s` project is not in the Actions Artifacts. Please run `mu plan` again."
-		if err := a.github.CreateIssueComment(ctx, prNum, fmt.Sprintf(msgTemp, muPlanMeta, projectCfg.Name)); err != nil {
+	artifactName := a.genArtifactName(projectCfg.Name, projectCfg.Workspace, prNum)
+	if err := a.planStore.Get(ctx, artifactName, a.projectDir(projectCfg)); err != nil {
+		if !errors.Is(err, planstore.ErrNotFound) {
+			return nil, err
+		}
+		const msgTemp = "%s\nThe plan file for the `%s` project is not in the plan store. Please run `mu plan` again."
+		if err := a.vcs.CreateComment(ctx, prNum, fmt.Sprintf(msgTemp, muPlanMeta, projectCfg.Name)); err != nil {
 			return nil, err
 		}
 		return nil, fmt.Errorf("plan file is not found: %s: %w", projectCfg.Name, errNotFoundPlanFile)
 	}
 
 	filename := a.genPlanFilename(projectCfg.Name, projectCfg.Workspace, prNum)
-	planFilePath, err := a.downloadPlanFile(ctx, projectCfg.Dir, filename, artifact.ID)
-	if err != nil {
-		return nil, err
-	}
-	if err := a.archiver.Decompress(projectCfg.Dir, planFilePath); err != nil {
+	if err := a.decryptPlanFile(ctx, prNum, projectCfg, filename); err != nil {
 		return nil, err
 	}
 
@@ -78,56 +84,24 @@ func (a *App) tfApply(
 	if err := tf.CompareVersion(ctx, projectCfg.Terraform.GetVersion()); err != nil {
 		return nil, err
 	}
-	if err := tf.SwitchWorkspace(ctx, projectCfg.Workspace); err != nil {
+	if err := a.verifyPlanFile(ctx, prNum, sha, baseSHA, tf, projectCfg, filename); err != nil {
 		return nil, err
 	}
-
-	a.action.StartGroup(fmt.Sprintf("mu init --project %s --workspace %s", projectCfg.Name, projectCfg.Workspace))
-	initRet, err := tf.Init(ctx, &terraform.InitParams{
-		BackendConfig:     projectCfg.Terraform.GetBackendConfig(),
-		BackendConfigPath: projectCfg.Terraform.GetBackendConfigPath(),
-	}, terraform.WithStream(os.Stdout))
-	_, _ = fmt.Fprintln(os.Stdout)
-	a.action.EndGroup()
-	if err != nil {
+	if err := tf.SwitchWorkspace(ctx, projectCfg.Workspace); err != nil {
 		return nil, err
 	}
-	if initRet.HasError {
-		if !a.disableSummaryLog {
-			a.outputInitFailedSummary(projectCfg, initRet.RawLog)
-		}
-		if err := a.hideApplyResultComments(ctx, prNum); err != nil {
-			return nil, err
-		}
-		if err := a.outputInitFailedResult(ctx, prNum, projectCfg, initRet); err != nil {
-			return nil, err
-		}
-		return nil, errInitFailed
-	}
 
-	a.action.StartGroup(fmt.Sprintf("mu apply --project %s --workspace %s", projectCfg.Name, projectCfg.Workspace))
-	applyRet, err := tf.Apply(ctx, &terraform.ApplyParams{
-		PlanFilePath: filename,
-	}, terraform.WithStream(os.Stdout))
-	_, _ = fmt.Fprintln(os.Stdout)
-	a.action.EndGroup()
-	if err != nil {
-		return nil, err
-	}
-	if !a.disableSummaryLog {
-		a.outputApplySummary(projectCfg, applyRet.RawLog)
-	}
-	if err := a.hideApplyResultComments(ctx, prNum); err != nil {
-		return nil, err
+	hooks := &hookParams{
+		prNum:    prNum,
+		sha:      sha,
+		project:  projectCfg,
+		planFile: filepath.Join(a.projectDir(projectCfg), filename),
 	}
-	if err := a.outputApplyResult(ctx, prNum, projectCfg, applyRet); err != nil {
+	applyRet, err := a.runApplySteps(ctx, tf, hooks, filename, false, nil, a.hideApplyResultComments)
+	if err != nil {
 		return nil, err
 	}
-	if applyRet.HasError {
-		return nil, errApplyFailed
-	}
-
-	if err := a.updateSuccessStatus(ctx, sha, projectCfg.Name, command.ApplyType, applyRet); err != nil {
+	if err := a.updateSuccessStatus(ctx, sha, projectCfg.ID(), command.ApplyType, applyRet); err != nil {
 		return nil, err
 	}
 	return &outputApply{
@@ -135,28 +109,64 @@ func (a *App) tfApply(
 	}, nil
 }
 
-func (a *App) downloadPlanFile(ctx context.Context, dir, filename string, artifactID int64) (string, error) {
-	path := filepath.Join(dir, filename+".zip")
-	artifactFile, err := os.Create(path)
+// verifyPlanFile refuses to apply the plan file when it was created for another commit, Terraform version or vars,
+// or was modified.
+func (a *App) verifyPlanFile(
+	ctx context.Context, prNum int, sha, baseSHA string, tf terraform.Terraform, projectCfg *config.Project, filename string,
+) error {
+	version, _, err := tf.Version(ctx)
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer func() {
-		_ = artifactFile.Close()
-	}()
-	if err := a.github.DownloadArtifact(ctx, artifactID, artifactFile); err != nil {
-		return "", err
+	meta, err := a.readPlanMetadata(a.projectDir(projectCfg), filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		err = fmt.Errorf("%w: the metadata of the plan file is not found", errStalePlan)
+	} else {
+		err = a.verifyPlanMetadata(meta, filepath.Join(a.projectDir(projectCfg), filename), &planMetadata{
+			HeadSHA:          sha,
+			BaseSHA:          baseSHA,
+			TerraformVersion: version,
+			Vars:             projectCfg.Terraform.GetVars(),
+			VarFiles:         projectCfg.Terraform.GetVarFiles(),
+		})
+	}
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, errStalePlan) && !errors.Is(err, errPlanFileTampered) {
+		return err
+	}
+	if err := a.vcs.CreateComment(ctx, prNum, a.applyRefusedMessage(projectCfg, err)); err != nil {
+		return err
+	}
+	return err
+}
+
+// getOutputValues returns the root module outputs after apply.
+// The apply itself has already succeeded, so a failure here is only logged.
+func (a *App) getOutputValues(ctx context.Context, tf terraform.Terraform) []*terraform.OutputValue {
+	outputRet, err := tf.Output(ctx)
+	if err != nil {
+		a.logger.Warn("failed to get outputs", log.Error(err))
+		return nil
+	}
+	if outputRet.HasError {
+		a.logger.Warn("failed to get outputs", log.Error(outputRet.Error))
+		return nil
 	}
-	return path, nil
+	return outputRet.Values
 }
 
 func (a *App) hideApplyResultComments(ctx context.Context, prNum int) error {
-	comments, err := a.github.ListPullRequestComments(ctx, prNum)
+	comments, err := a.vcs.ListComments(ctx, prNum)
 	if err != nil {
 		return err
 	}
 	for _, comment := range comments {
-		if comment.Author.Login != github.ActionBotName {
+		if !a.isBotComment(comment.Author.Login) {
 			continue
 		}
 		if comment.IsMinimized {
@@ -165,29 +175,21 @@ func (a *App) hideApplyResultComments(ctx context.Context, prNum int) error {
 		if !strings.HasPrefix(comment.Body, muInitMeta) && !strings.HasPrefix(comment.Body, muApplyMeta) {
 			continue
 		}
-		if err := a.github.HideIssueComment(ctx, comment.ID); err != nil {
+		if err := a.vcs.HideComment(ctx, comment.ID); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (a *App) outputApplyResult(
-	ctx context.Context, prNum int, cfg *config.Project, out *terraform.Output,
-) error {
-	if out.HasError {
-		return a.outputApplyFailedResult(ctx, prNum, cfg, out)
-	}
-	return a.outputApplySucceededResult(ctx, prNum, cfg, out)
-}
-
 func (a *App) outputApplySucceededResult(
 	ctx context.Context, prNum int, cfg *config.Project, out *terraform.Output,
+	values []*terraform.OutputValue, steps []*stepOutput,
 ) error {
-	comment := a.applySucceededMessage(cfg, out)
+	comment := a.applySucceededMessage(cfg, out, values, steps)
 	messages := a.splitMessages(strings.NewReader(comment))
 	for _, msg := range messages {
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 	}
@@ -200,7 +202,7 @@ func (a *App) outputApplyFailedResult(
 	comment := a.applyFailedMessage(cfg, out)
 	messages := a.splitMessages(strings.NewReader(comment))
 	for _, msg := range messages {
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 	}
@@ -218,3 +220,32 @@ func (a *App) outputApplySummary(cfg *config.Project, log string) {
 	summary.WriteString("</details>\n")
 	_ = a.action.AddStepSummary(summary.String())
 }
+
+// decryptPlanFile decrypts the plan file in place if it was encrypted by `mu plan`.
+// Plaintext plan files are left as they are.
+func (a *App) decryptPlanFile(ctx context.Context, prNum int, projectCfg *config.Project, filename string) error {
+	path := filepath.Join(a.projectDir(projectCfg), filename)
+	encrypted, err := encryption.IsEncrypted(path)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return nil
+	}
+	if a.encrypter == nil {
+		err = fmt.Errorf("%w: the plan file is encrypted, but no encryption key is configured", errDecryptionFailed)
+	} else {
+		err = a.encrypter.Decrypt(path)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, encryption.ErrDecrypt) {
+			return err
+		}
+		err = fmt.Errorf("%w: %w", errDecryptionFailed, err)
+	}
+	if err := a.vcs.CreateComment(ctx, prNum, a.decryptionFailedMessage(projectCfg, err)); err != nil {
+		return err
+	}
+	return err
+}
@@ -2,8 +2,8 @@ package app
 
 import (
 	"context"
-	"io"
-	"strings"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,8 +12,10 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/encryption"
+	"github.com/yu-icchi/mu/pkg/planstore"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func TestApp_tfApply(t *testing.T) {
@@ -35,12 +37,12 @@ func TestApp_tfApply(t *testing.T) {
 		},
 	}
 	type args struct {
-		ctx      context.Context
-		prNum    int
-		sha      string
-		cfg      *config.Project
-		artifact *github.Artifact
-		reviews  github.Reviews
+		ctx     context.Context
+		prNum   int
+		sha     string
+		baseSHA string
+		cfg     *config.Project
+		reviews vcs.Reviews
 	}
 	tests := []struct {
 		name      string
@@ -52,20 +54,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "success",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -74,21 +73,17 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
 				m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, params *terraform.InitParams, opts ...terraform.Option) (*terraform.Output, error) {
@@ -122,11 +117,17 @@ func TestApp_tfApply(t *testing.T) {
 							RawLog:             "apply log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.terraform.EXPECT().Output(ctx).Return(&terraform.OutputsOutput{
+					Values: []*terraform.OutputValue{
+						{Name: "endpoint", Type: "string", Value: "https://example.com"},
+						{Name: "password", Type: "string", Value: "secret", Sensitive: true},
+					},
+				}, nil)
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: actionURL,
 					Desc:      "Apply succeeded.",
 					Context:   src,
@@ -140,20 +141,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed terraform init",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -162,21 +160,17 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
 				m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, params *terraform.InitParams, opts ...terraform.Option) (*terraform.Output, error) {
@@ -191,11 +185,11 @@ func TestApp_tfApply(t *testing.T) {
 							HasError: true,
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -206,20 +200,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed terraform apply",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -228,21 +219,17 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
 				m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, params *terraform.InitParams, opts ...terraform.Option) (*terraform.Output, error) {
@@ -276,11 +263,11 @@ func TestApp_tfApply(t *testing.T) {
 							RawLog:             "apply error log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -289,25 +276,22 @@ func TestApp_tfApply(t *testing.T) {
 			expectErr: errApplyFailed,
 		},
 		{
-			name: "failed to lock: github.FindPullRequestByLabel",
+			name: "failed to lock: vcs.FindPullRequestByLabel",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -318,20 +302,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed to update pending status",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -340,16 +321,16 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -358,22 +339,19 @@ func TestApp_tfApply(t *testing.T) {
 			expectErr: assert.AnError,
 		},
 		{
-			name: "failed to download plan file",
+			name: "failed to get plan file",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -382,22 +360,17 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						assert.NoError(t, err)
-						return assert.AnError
-					})
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -406,22 +379,19 @@ func TestApp_tfApply(t *testing.T) {
 			expectErr: assert.AnError,
 		},
 		{
-			name: "failed to decompress archive file",
+			name: "stale plan",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-new-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -430,46 +400,47 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
-					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha:       "test-new-sha",
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
+				m.terraform.EXPECT().Setup(ctx).Return(nil)
+				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "Apply Refused")
+						assert.Contains(t, body, "the head of the pull request has moved since the plan")
+						return nil
 					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
-					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha:       "test-new-sha",
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
 				}).Return(nil)
 			},
-			expectErr: assert.AnError,
+			expectErr: errStalePlan,
 		},
 		{
 			name: "failed to terraform setup",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -478,23 +449,18 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -505,20 +471,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed to terraform compare version",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -527,24 +490,19 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -555,27 +513,24 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed to terraform switch workspace",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -584,18 +539,14 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -606,20 +557,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed to terraform init",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -628,21 +576,17 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
 				m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, params *terraform.InitParams, opts ...terraform.Option) (*terraform.Output, error) {
@@ -654,9 +598,9 @@ func TestApp_tfApply(t *testing.T) {
 						assert.Len(t, opts, 1)
 						return nil, assert.AnError
 					})
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -667,27 +611,24 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed to terraform apply",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -696,14 +637,10 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
 				m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, params *terraform.InitParams, opts ...terraform.Option) (*terraform.Output, error) {
@@ -725,9 +662,9 @@ func TestApp_tfApply(t *testing.T) {
 						assert.Equal(t, expectParams, params)
 						return nil, assert.AnError
 					})
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -738,17 +675,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "not found plan file",
 			args: args{
-				ctx:      context.Background(),
-				prNum:    1,
-				sha:      "test-sha",
-				cfg:      project,
-				artifact: nil,
-				reviews:  github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -757,17 +694,18 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(planstore.ErrNotFound)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -776,19 +714,19 @@ func TestApp_tfApply(t *testing.T) {
 			expectErr: errNotFoundPlanFile,
 		},
 		{
-			name: "failed to github.CreateIssueComment",
+			name: "failed to vcs.CreateComment",
 			args: args{
-				ctx:      context.Background(),
-				prNum:    1,
-				sha:      "test-sha",
-				cfg:      project,
-				artifact: nil,
-				reviews:  github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -797,17 +735,18 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(planstore.ErrNotFound)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -818,20 +757,17 @@ func TestApp_tfApply(t *testing.T) {
 		{
 			name: "failed to update success status",
 			args: args{
-				ctx:   context.Background(),
-				prNum: 1,
-				sha:   "test-sha",
-				cfg:   project,
-				artifact: &github.Artifact{
-					ID:   1,
-					Name: "mu_test",
-				},
-				reviews: github.Reviews{},
+				ctx:     context.Background(),
+				prNum:   1,
+				sha:     "test-sha",
+				baseSHA: "test-base-sha",
+				cfg:     project,
+				reviews: vcs.Reviews{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/apply: test"
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&github.PullRequest{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(&vcs.PullRequest{
 					ID:             1,
 					Number:         1,
 					Title:          "title",
@@ -840,21 +776,17 @@ func TestApp_tfApply(t *testing.T) {
 					MergeableState: "clean",
 					Labels:         nil,
 				}, nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, artifactID int64, file io.Writer) error {
-						_, err := io.Copy(file, strings.NewReader("plan data"))
-						return err
-					})
-				m.archive.EXPECT().Decompress("./testdata", "testdata/test_default_1.tfplan.zip").Return(nil)
+				m.planStore.EXPECT().Get(ctx, "mu_test_default_1", "./testdata").Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
 				m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, params *terraform.InitParams, opts ...terraform.Option) (*terraform.Output, error) {
@@ -888,18 +820,24 @@ func TestApp_tfApply(t *testing.T) {
 							RawLog:             "apply log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.terraform.EXPECT().Output(ctx).Return(&terraform.OutputsOutput{
+					Values: []*terraform.OutputValue{
+						{Name: "endpoint", Type: "string", Value: "https://example.com"},
+						{Name: "password", Type: "string", Value: "secret", Sensitive: true},
+					},
+				}, nil)
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: actionURL,
 					Desc:      "Apply succeeded.",
 					Context:   src,
 				}).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -915,7 +853,7 @@ func TestApp_tfApply(t *testing.T) {
 			defer ctrl.Finish()
 			app, mock := newTestAppAndMock(ctrl)
 			tt.prepare(tt.args.ctx, mock, t)
-			out, err := app.tfApply(tt.args.ctx, tt.args.prNum, tt.args.sha, tt.args.cfg, tt.args.artifact, tt.args.reviews)
+			out, err := app.tfApply(tt.args.ctx, tt.args.prNum, tt.args.sha, tt.args.baseSHA, tt.args.cfg, tt.args.reviews)
 			if tt.expectErr != nil {
 				require.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectErr)
@@ -926,3 +864,87 @@ func TestApp_tfApply(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_decryptPlanFile(t *testing.T) {
+	const filename = "test_default_1.tfplan"
+	tests := []struct {
+		name      string
+		encrypted bool
+		noKey     bool
+		prepare   prepare
+		expectErr error
+	}{
+		{
+			name:      "success",
+			encrypted: true,
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.encrypter.EXPECT().Decrypt(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:      "plaintext",
+			encrypted: false,
+			prepare:   func(ctx context.Context, m *mock, t *testing.T) {},
+		},
+		{
+			name:      "wrong key",
+			encrypted: true,
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.encrypter.EXPECT().Decrypt(gomock.Any()).Return(encryption.ErrDecrypt)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "Plan Decryption Failed")
+						assert.Contains(t, body, "failed to decrypt")
+						return nil
+					})
+			},
+			expectErr: errDecryptionFailed,
+		},
+		{
+			name:      "no key",
+			encrypted: true,
+			noKey:     true,
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "no encryption key is configured")
+						return nil
+					})
+			},
+			expectErr: errDecryptionFailed,
+		},
+		{
+			name:      "failed to decrypt",
+			encrypted: true,
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.encrypter.EXPECT().Decrypt(gomock.Any()).Return(assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			if !tt.noKey {
+				app.encrypter = mock.encrypter
+			}
+			dir := t.TempDir()
+			path := filepath.Join(dir, filename)
+			require.NoError(t, os.WriteFile(path, []byte("plan data"), 0600))
+			if tt.encrypted {
+				require.NoError(t, encryption.NewAESGCM("passphrase").Encrypt(path))
+			}
+			ctx := context.Background()
+			tt.prepare(ctx, mock, t)
+			err := app.decryptPlanFile(ctx, 1, &config.Project{Name: "test", Dir: dir, Workspace: "default"}, filename)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+)
+
+type planChecks struct {
+	validate *terraform.ValidateOutput
+	fmt      *terraform.FmtOutput
+}
+
+func (c *planChecks) isEmpty() bool {
+	return c == nil || (c.validate == nil && c.fmt == nil)
+}
+
+func (c *planChecks) hasError() bool {
+	if c == nil {
+		return false
+	}
+	return (c.validate != nil && c.validate.HasError) || (c.fmt != nil && c.fmt.HasError)
+}
+
+// runPlanChecks runs the optional validate and fmt stages before terraform plan.
+// Each stage is reported as a separate commit status.
+func (a *App) runPlanChecks(
+	ctx context.Context, sha string, cfg *config.Project, tf terraform.Terraform,
+) (*planChecks, error) {
+	checks := &planChecks{}
+	if cfg.Plan.Validate {
+		ret, err := a.runValidateCheck(ctx, sha, cfg, tf)
+		if err != nil {
+			return nil, err
+		}
+		checks.validate = ret
+	}
+	if cfg.Plan.Fmt {
+		ret, err := a.runFmtCheck(ctx, sha, cfg, tf)
+		if err != nil {
+			return nil, err
+		}
+		checks.fmt = ret
+	}
+	return checks, nil
+}
+
+func (a *App) runValidateCheck(
+	ctx context.Context, sha string, cfg *config.Project, tf terraform.Terraform,
+) (*terraform.ValidateOutput, error) {
+	if err := a.updatePendingStatus(ctx, sha, cfg.ID(), command.ValidateType); err != nil {
+		return nil, err
+	}
+	a.action.StartGroup(fmt.Sprintf("mu validate --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	ret, err := tf.Validate(ctx, terraform.WithStream(os.Stdout))
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil || ret.HasError {
+		if err := a.updateFailureStatus(ctx, sha, cfg.ID(), command.ValidateType); err != nil {
+			return nil, err
+		}
+		return ret, err
+	}
+	if err := a.updateSuccessStatus(ctx, sha, cfg.ID(), command.ValidateType, nil); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (a *App) runFmtCheck(
+	ctx context.Context, sha string, cfg *config.Project, tf terraform.Terraform,
+) (*terraform.FmtOutput, error) {
+	if err := a.updatePendingStatus(ctx, sha, cfg.ID(), command.FmtType); err != nil {
+		return nil, err
+	}
+	a.action.StartGroup(fmt.Sprintf("mu fmt --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	ret, err := tf.Fmt(ctx, &terraform.FmtParams{}, terraform.WithStream(os.Stdout))
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil || ret.HasError {
+		if err := a.updateFailureStatus(ctx, sha, cfg.ID(), command.FmtType); err != nil {
+			return nil, err
+		}
+		return ret, err
+	}
+	if err := a.updateSuccessStatus(ctx, sha, cfg.ID(), command.FmtType, nil); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_runPlanChecks(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	const actionURL = "https://github.com/test/mu/actions/runs/test-run-id"
+	newProject := func(validate, fmt bool) *config.Project {
+		return &config.Project{
+			Name:      "test",
+			Dir:       "./testdata",
+			Workspace: "default",
+			Plan: &config.Plan{
+				Paths:    []string{"*.tf*"},
+				Validate: validate,
+				Fmt:      fmt,
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		cfg          *config.Project
+		prepare      prepare
+		expect       *planChecks
+		expectHasErr bool
+		expectErr    error
+	}{
+		{
+			name:    "disabled",
+			cfg:     newProject(false, false),
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {},
+			expect:  &planChecks{},
+		},
+		{
+			name: "success",
+			cfg:  newProject(true, true),
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.PendingStatus, TargetURL: actionURL,
+					Desc: "in progress...", Context: "mu/validate: test",
+				}).Return(nil)
+				m.terraform.EXPECT().Validate(ctx, gomock.Any()).Return(&terraform.ValidateOutput{
+					Result: "Success! The configuration is valid.",
+				}, nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.SuccessStatus, TargetURL: actionURL,
+					Desc: "Validation succeeded.", Context: "mu/validate: test",
+				}).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.PendingStatus, TargetURL: actionURL,
+					Desc: "in progress...", Context: "mu/fmt: test",
+				}).Return(nil)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{}, gomock.Any()).Return(&terraform.FmtOutput{}, nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.SuccessStatus, TargetURL: actionURL,
+					Desc: "Formatting check passed.", Context: "mu/fmt: test",
+				}).Return(nil)
+			},
+			expect: &planChecks{
+				validate: &terraform.ValidateOutput{
+					Result: "Success! The configuration is valid.",
+				},
+				fmt: &terraform.FmtOutput{},
+			},
+		},
+		{
+			name: "unformatted",
+			cfg:  newProject(false, true),
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.PendingStatus, TargetURL: actionURL,
+					Desc: "in progress...", Context: "mu/fmt: test",
+				}).Return(nil)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{}, gomock.Any()).Return(&terraform.FmtOutput{
+					Result:   "diff",
+					HasDiff:  true,
+					HasError: true,
+				}, nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.FailureStatus, TargetURL: actionURL,
+					Desc: "failed.", Context: "mu/fmt: test",
+				}).Return(nil)
+			},
+			expect: &planChecks{
+				fmt: &terraform.FmtOutput{
+					Result:   "diff",
+					HasDiff:  true,
+					HasError: true,
+				},
+			},
+			expectHasErr: true,
+		},
+		{
+			name: "failed to validate",
+			cfg:  newProject(true, false),
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.PendingStatus, TargetURL: actionURL,
+					Desc: "in progress...", Context: "mu/validate: test",
+				}).Return(nil)
+				m.terraform.EXPECT().Validate(ctx, gomock.Any()).Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+					Sha: "test-sha", Status: vcs.FailureStatus, TargetURL: actionURL,
+					Desc: "failed.", Context: "mu/validate: test",
+				}).Return(nil)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, mock, t)
+			checks, err := app.runPlanChecks(ctx, "test-sha", tt.cfg, mock.terraform)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, checks)
+			assert.Equal(t, tt.expectHasErr, checks.hasError())
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// fmtFileSuffixes are the suffixes of the files which `terraform fmt` formats.
+var fmtFileSuffixes = []string{".tf", ".tfvars", ".tftest.hcl", ".tfmock.hcl"}
+
+func (a *App) tfFmt(ctx context.Context, pr *vcs.PullRequest, cfg *config.Project, cmd *command.Fmt) error {
+	defer a.lockProject(cfg)()
+	auditProject(ctx, cfg)
+
+	// The head branch of a fork is not in this repository, so a branch with the same name would be committed.
+	if cmd.Fix && pr.Fork {
+		const reason = "The formatted files cannot be committed to a pull request from a fork. " +
+			"Run `terraform fmt -recursive` and push them."
+		return a.vcs.CreateComment(ctx, pr.Number, a.fmtFixRefusedMessage(cfg, reason))
+	}
+
+	// The checked out working tree may be the merge commit with the base branch,
+	// so the files of the head commit are formatted to commit them on top of it.
+	dir := a.projectDir(cfg)
+	headDir := ""
+	if cmd.Fix {
+		var err error
+		headDir, err = os.MkdirTemp("", "mu-fmt-")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.RemoveAll(headDir)
+		}()
+		if err := a.downloadFmtFiles(ctx, cfg, pr.HeadSHA, headDir); err != nil {
+			return err
+		}
+		dir = filepath.Join(headDir, cfg.Dir)
+	}
+
+	tf := a.genTerraformIn(cfg, dir)
+	if err := tf.Setup(ctx); err != nil {
+		return err
+	}
+	if err := tf.CompareVersion(ctx, cfg.Terraform.GetVersion()); err != nil {
+		return err
+	}
+
+	a.action.StartGroup(fmt.Sprintf("mu fmt --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	fmtRet, err := tf.Fmt(ctx, &terraform.FmtParams{
+		Write: cmd.Fix,
+	}, terraform.WithStream(os.Stdout))
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil {
+		return err
+	}
+	if !cmd.Fix || fmtRet.HasError {
+		if err := a.vcs.CreateComment(ctx, pr.Number, a.fmtMessage(cfg, fmtRet)); err != nil {
+			return err
+		}
+		if fmtRet.HasError && !fmtRet.HasDiff {
+			return errFmtFailed
+		}
+		return nil
+	}
+
+	files := make(map[string]string, len(fmtRet.Files))
+	paths := make([]string, 0, len(fmtRet.Files))
+	for _, file := range fmtRet.Files {
+		path := filepath.ToSlash(filepath.Join(cfg.Dir, file))
+		content, err := os.ReadFile(filepath.Join(headDir, path))
+		if err != nil {
+			return err
+		}
+		files[path] = string(content)
+		paths = append(paths, path)
+	}
+	if len(files) == 0 {
+		return a.vcs.CreateComment(ctx, pr.Number, a.fmtFixedMessage(cfg, nil, ""))
+	}
+	sha, err := a.vcs.CommitFiles(ctx, &vcs.CommitFilesParams{
+		Branch:  pr.HeadRef,
+		HeadSHA: pr.HeadSHA,
+		Message: fmt.Sprintf("mu fmt -p %s --fix", cfg.Name),
+		Files:   files,
+	})
+	if err != nil {
+		if errors.Is(err, vcs.ErrOutdated) {
+			reason := fmt.Sprintf("The head branch was updated while formatting. Comment `mu fmt -p %s --fix` again.", cfg.Name)
+			return a.vcs.CreateComment(ctx, pr.Number, a.fmtFixRefusedMessage(cfg, reason))
+		}
+		return err
+	}
+	return a.vcs.CreateComment(ctx, pr.Number, a.fmtFixedMessage(cfg, paths, sha))
+}
+
+// downloadFmtFiles writes the files of the project at the ref, which `terraform fmt` formats, under dir.
+func (a *App) downloadFmtFiles(ctx context.Context, cfg *config.Project, ref, dir string) error {
+	projectDir := path.Clean(filepath.ToSlash(cfg.Dir))
+	if err := os.MkdirAll(filepath.Join(dir, projectDir), 0755); err != nil {
+		return err
+	}
+	files, err := a.vcs.ListRepositoryFiles(ctx, projectDir, ref)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !hasFmtFileSuffix(file) {
+			continue
+		}
+		content, err := a.vcs.GetFileContent(ctx, file, ref)
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, content, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasFmtFileSuffix(file string) bool {
+	for _, suffix := range fmtFileSuffixes {
+		if strings.HasSuffix(file, suffix) {
+			return true
+		}
+	}
+	return false
+}
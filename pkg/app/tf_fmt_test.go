@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_tfFmt(t *testing.T) {
+	t.Parallel()
+	project := &config.Project{
+		Name:      "test",
+		Dir:       "./testdata",
+		Workspace: "default",
+		Terraform: &config.Terraform{
+			Version: "1.9.1",
+		},
+	}
+	pr := &vcs.PullRequest{
+		Number:  1,
+		HeadSHA: "test-head-sha",
+		HeadRef: "feature",
+	}
+	forkPR := &vcs.PullRequest{
+		Number:  1,
+		HeadSHA: "test-head-sha",
+		HeadRef: "main",
+		Fork:    true,
+	}
+	prepareHeadFiles := func(ctx context.Context, m *mock) {
+		m.vcs.EXPECT().ListRepositoryFiles(ctx, "testdata", "test-head-sha").
+			Return([]string{"testdata/main.tf", "testdata/README.md"}, nil)
+		m.vcs.EXPECT().GetFileContent(ctx, "testdata/main.tf", "test-head-sha").
+			Return([]byte("resource \"null_resource\" \"test\" {}\n"), nil)
+		m.terraform.EXPECT().Setup(ctx).Return(nil)
+		m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+	}
+	type args struct {
+		ctx context.Context
+		pr  *vcs.PullRequest
+		cmd *command.Fmt
+	}
+	tests := []struct {
+		name      string
+		args      args
+		prepare   prepare
+		expectErr error
+	}{
+		{
+			name: "no changes",
+			args: args{
+				ctx: context.Background(),
+				pr:  pr,
+				cmd: &command.Fmt{Fix: true},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareHeadFiles(ctx, m)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{Write: true}, gomock.Any()).
+					Return(&terraform.FmtOutput{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "All files are already formatted.")
+						return nil
+					})
+			},
+		},
+		{
+			name: "changes committed",
+			args: args{
+				ctx: context.Background(),
+				pr:  pr,
+				cmd: &command.Fmt{Fix: true},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareHeadFiles(ctx, m)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{Write: true}, gomock.Any()).
+					Return(&terraform.FmtOutput{
+						Files:   []string{"main.tf"},
+						HasDiff: true,
+					}, nil)
+				m.vcs.EXPECT().CommitFiles(ctx, &vcs.CommitFilesParams{
+					Branch:  "feature",
+					HeadSHA: "test-head-sha",
+					Message: "mu fmt -p test --fix",
+					Files: map[string]string{
+						"testdata/main.tf": "resource \"null_resource\" \"test\" {}\n",
+					},
+				}).Return("test-commit-sha", nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "Committed the formatted files in test-commit-sha.")
+						assert.Contains(t, body, "- `testdata/main.tf`")
+						return nil
+					})
+			},
+		},
+		{
+			name: "fork pull request refused",
+			args: args{
+				ctx: context.Background(),
+				pr:  forkPR,
+				cmd: &command.Fmt{Fix: true},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "cannot be committed to a pull request from a fork")
+						return nil
+					})
+			},
+		},
+		{
+			name: "fork pull request without fix",
+			args: args{
+				ctx: context.Background(),
+				pr:  forkPR,
+				cmd: &command.Fmt{},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.terraform.EXPECT().Setup(ctx).Return(nil)
+				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{Write: false}, gomock.Any()).
+					Return(&terraform.FmtOutput{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "All files are formatted.")
+						return nil
+					})
+			},
+		},
+		{
+			name: "head branch updated",
+			args: args{
+				ctx: context.Background(),
+				pr:  pr,
+				cmd: &command.Fmt{Fix: true},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareHeadFiles(ctx, m)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{Write: true}, gomock.Any()).
+					Return(&terraform.FmtOutput{
+						Files:   []string{"main.tf"},
+						HasDiff: true,
+					}, nil)
+				m.vcs.EXPECT().CommitFiles(ctx, gomock.Any()).Return("", vcs.ErrOutdated)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "The head branch was updated while formatting.")
+						return nil
+					})
+			},
+		},
+		{
+			name: "failed to commit files",
+			args: args{
+				ctx: context.Background(),
+				pr:  pr,
+				cmd: &command.Fmt{Fix: true},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareHeadFiles(ctx, m)
+				m.terraform.EXPECT().Fmt(ctx, &terraform.FmtParams{Write: true}, gomock.Any()).
+					Return(&terraform.FmtOutput{
+						Files:   []string{"main.tf"},
+						HasDiff: true,
+					}, nil)
+				m.vcs.EXPECT().CommitFiles(ctx, gomock.Any()).Return("", assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "failed to list the files of the head commit",
+			args: args{
+				ctx: context.Background(),
+				pr:  pr,
+				cmd: &command.Fmt{Fix: true},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.vcs.EXPECT().ListRepositoryFiles(ctx, "testdata", "test-head-sha").Return(nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			tt.prepare(tt.args.ctx, mock, t)
+			err := app.tfFmt(tt.args.ctx, tt.args.pr, project, tt.args.cmd)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
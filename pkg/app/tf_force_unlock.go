@@ -12,6 +12,9 @@ import (
 )
 
 func (a *App) tfForceUnlock(ctx context.Context, prNum int, cfg *config.Project, cmd *command.Unlock) error {
+	defer a.lockProject(cfg)()
+	auditProject(ctx, cfg)
+
 	tf := a.genTerraform(cfg)
 	if err := tf.Setup(ctx); err != nil {
 		return err
@@ -23,6 +26,10 @@ func (a *App) tfForceUnlock(ctx context.Context, prNum int, cfg *config.Project,
 		return err
 	}
 
+	if err := a.runHooks(ctx, config.HookPreInit, &hookParams{prNum: prNum, project: cfg}); err != nil {
+		return err
+	}
+
 	a.action.StartGroup(fmt.Sprintf("mu init --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
 	initRet, err := tf.Init(ctx, &terraform.InitParams{
 		BackendConfig:     cfg.Terraform.GetBackendConfig(),
@@ -55,7 +62,7 @@ func (a *App) tfForceUnlock(ctx context.Context, prNum int, cfg *config.Project,
 		a.outputForceUnlockSummary(cfg, forceUnlockRet.Result)
 	}
 	message := a.forceUnlockMessage(forceUnlockRet)
-	if err := a.github.CreateIssueComment(ctx, prNum, message); err != nil {
+	if err := a.vcs.CreateComment(ctx, prNum, message); err != nil {
 		return fmt.Errorf("%w: %w", errForceUnlockFailed, err)
 	}
 	if forceUnlockRet.HasError {
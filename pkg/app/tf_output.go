@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+)
+
+func (a *App) tfOutput(ctx context.Context, prNum int, cfg *config.Project, cmd *command.Output) error {
+	defer a.lockProject(cfg)()
+	auditProject(ctx, cfg)
+
+	tf := a.genTerraform(cfg)
+	if err := tf.Setup(ctx); err != nil {
+		return err
+	}
+	if err := tf.CompareVersion(ctx, cfg.Terraform.GetVersion()); err != nil {
+		return err
+	}
+	if err := tf.SwitchWorkspace(ctx, cfg.Workspace); err != nil {
+		return err
+	}
+
+	if err := a.runHooks(ctx, config.HookPreInit, &hookParams{prNum: prNum, project: cfg}); err != nil {
+		return err
+	}
+
+	a.action.StartGroup(fmt.Sprintf("mu init --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	initRet, err := tf.Init(ctx, &terraform.InitParams{
+		BackendConfig:     cfg.Terraform.GetBackendConfig(),
+		BackendConfigPath: cfg.Terraform.GetBackendConfigPath(),
+	}, terraform.WithStream(os.Stdout))
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil {
+		return err
+	}
+	if initRet.HasError {
+		if !a.disableSummaryLog {
+			a.outputInitFailedSummary(cfg, initRet.RawLog)
+		}
+		if err := a.outputInitFailedResult(ctx, prNum, cfg, initRet); err != nil {
+			return err
+		}
+		return errInitFailed
+	}
+
+	a.action.StartGroup(fmt.Sprintf("mu output --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	outputRet, err := tf.Output(ctx, terraform.WithStream(os.Stdout))
+	a.action.EndGroup()
+	if err != nil {
+		return err
+	}
+	if outputRet.HasError {
+		if err := a.vcs.CreateComment(ctx, prNum, a.outputFailedMessage(cfg, outputRet.Result)); err != nil {
+			return err
+		}
+		return errOutputFailed
+	}
+
+	values := outputRet.Values
+	if cmd.Name != "" {
+		values = filterOutputValues(values, cmd.Name)
+		if len(values) == 0 {
+			msg := fmt.Sprintf("The output `%s` could not be found in the `%s` project.", cmd.Name, cfg.Name)
+			return a.vcs.CreateComment(ctx, prNum, msg)
+		}
+	}
+	if !a.disableSummaryLog {
+		a.outputOutputSummary(cfg, values)
+	}
+	return a.vcs.CreateComment(ctx, prNum, a.outputMessage(cfg, values))
+}
+
+func filterOutputValues(values []*terraform.OutputValue, name string) []*terraform.OutputValue {
+	for _, value := range values {
+		if value.Name == name {
+			return []*terraform.OutputValue{value}
+		}
+	}
+	return nil
+}
+
+func (a *App) outputOutputSummary(cfg *config.Project, values []*terraform.OutputValue) {
+	summary := new(strings.Builder)
+	summary.WriteString("## mu output\n\n")
+	summary.WriteString(fmt.Sprintf("project: `%s` workspace: `%s`\n\n", cfg.Name, cfg.Workspace))
+	summary.WriteString(a.formatOutputValuesTable(values))
+	_ = a.action.AddStepSummary(summary.String())
+}
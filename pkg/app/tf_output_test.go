@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+)
+
+func TestApp_tfOutput(t *testing.T) {
+	t.Parallel()
+	project := &config.Project{
+		Name:      "test",
+		Dir:       "./testdata",
+		Workspace: "default",
+		Terraform: &config.Terraform{
+			Version: "1.9.1",
+		},
+	}
+	values := []*terraform.OutputValue{
+		{Name: "endpoint", Type: "string", Value: "https://example.com"},
+		{Name: "password", Type: "string", Value: "secret", Sensitive: true},
+	}
+	prepareInit := func(ctx context.Context, m *mock) {
+		m.terraform.EXPECT().Setup(ctx).Return(nil)
+		m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+		m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
+		m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).Return(&terraform.Output{RawLog: "init log"}, nil)
+	}
+	type args struct {
+		ctx context.Context
+		cmd *command.Output
+	}
+	tests := []struct {
+		name      string
+		args      args
+		prepare   prepare
+		expectErr error
+	}{
+		{
+			name: "all outputs with the sensitive values masked",
+			args: args{
+				ctx: context.Background(),
+				cmd: &command.Output{},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Output(ctx, gomock.Any()).Return(&terraform.OutputsOutput{Values: values}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "| endpoint | string | `https://example.com` |")
+						assert.Contains(t, body, "| password | string | *(sensitive value)* |")
+						assert.NotContains(t, body, "secret")
+						return nil
+					})
+			},
+		},
+		{
+			name: "output with the name",
+			args: args{
+				ctx: context.Background(),
+				cmd: &command.Output{Name: "endpoint"},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Output(ctx, gomock.Any()).Return(&terraform.OutputsOutput{Values: values}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "| endpoint | string | `https://example.com` |")
+						assert.NotContains(t, body, "password")
+						return nil
+					})
+			},
+		},
+		{
+			name: "output not found",
+			args: args{
+				ctx: context.Background(),
+				cmd: &command.Output{Name: "missing"},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Output(ctx, gomock.Any()).Return(&terraform.OutputsOutput{Values: values}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, "The output `missing` could not be found in the `test` project.").Return(nil)
+			},
+		},
+		{
+			name: "output failed",
+			args: args{
+				ctx: context.Background(),
+				cmd: &command.Output{},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Output(ctx, gomock.Any()).Return(&terraform.OutputsOutput{
+					Result:   "Error: Unsupported state file format",
+					HasError: true,
+				}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, "**Output Failed**")
+						assert.Contains(t, body, "Error: Unsupported state file format")
+						return nil
+					})
+			},
+			expectErr: errOutputFailed,
+		},
+		{
+			name: "failed to run output",
+			args: args{
+				ctx: context.Background(),
+				cmd: &command.Output{},
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				prepareInit(ctx, m)
+				m.terraform.EXPECT().Output(ctx, gomock.Any()).Return(nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			app, mock := newTestAppAndMock(ctrl)
+			app.disableSummaryLog = true
+			tt.prepare(tt.args.ctx, mock, t)
+			err := app.tfOutput(tt.args.ctx, 1, project, tt.args.cmd)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -9,19 +9,23 @@ import (
 
 	"github.com/yu-icchi/mu/pkg/command"
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
 	"github.com/yu-icchi/mu/pkg/log"
 	"github.com/yu-icchi/mu/pkg/terraform"
 )
 
 type outputPlan struct {
-	path   string
-	result string
+	result  string
+	planURL string
+	// comment is the plan result, which is commented by the caller to group the workspaces of the project.
+	comment string
 }
 
 func (a *App) tfPlan(
-	ctx context.Context, prNum int, sha string, projectCfg *config.Project, cmd *command.Plan,
+	ctx context.Context, prNum int, sha, baseSHA string, projectCfg *config.Project, cmd *command.Plan,
 ) (out *outputPlan, err error) {
+	defer a.lockProject(projectCfg)()
+	auditProject(ctx, projectCfg)
+
 	defer func() {
 		rec := recover()
 		if err == nil && rec == nil {
@@ -31,16 +35,16 @@ func (a *App) tfPlan(
 			err = fmt.Errorf("%w: %s", errPanicOccurred, rec)
 			a.logger.Debug(fmt.Sprintf("plan: %s", err))
 		}
-		if err := a.updateFailureStatus(ctx, sha, projectCfg.Name, cmd.Type()); err != nil {
+		if err := a.updateFailureStatus(ctx, sha, projectCfg.ID(), cmd.Type()); err != nil {
 			a.logger.Error("failed to update commit state", log.Error(err))
 		}
 	}()
 
-	if err := a.updatePendingStatus(ctx, sha, projectCfg.Name, cmd.Type()); err != nil {
+	if err := a.updatePendingStatus(ctx, sha, projectCfg.ID(), cmd.Type()); err != nil {
 		return nil, err
 	}
 
-	if err := a.lock(ctx, projectCfg.Name, prNum, cmd.Type(), projectCfg.LockLabelColor); err != nil {
+	if err := a.lock(ctx, projectCfg.ID(), prNum, cmd.Type(), projectCfg.LockLabelColor); err != nil {
 		return nil, err
 	}
 
@@ -55,76 +59,142 @@ func (a *App) tfPlan(
 		return nil, err
 	}
 
-	a.action.StartGroup(fmt.Sprintf("mu init --project=%s --workspace=%s", projectCfg.Name, projectCfg.Workspace))
-	initRet, err := tf.Init(ctx, &terraform.InitParams{
-		BackendConfig:     projectCfg.Terraform.GetBackendConfig(),
-		BackendConfigPath: projectCfg.Terraform.GetBackendConfigPath(),
-	}, terraform.WithStream(os.Stdout))
-	_, _ = fmt.Fprintln(os.Stdout)
-	a.action.EndGroup()
-	if err != nil {
-		return nil, err
-	}
-	if initRet.HasError {
-		if !a.disableSummaryLog {
-			a.outputInitFailedSummary(projectCfg, initRet.RawLog)
-		}
-		if err := a.hidePlanResultComments(ctx, prNum); err != nil {
-			return nil, err
-		}
-		if err := a.outputInitFailedResult(ctx, prNum, projectCfg, initRet); err != nil {
-			return nil, err
-		}
-		return nil, errInitFailed
-	}
-
 	filename := a.genPlanFilename(projectCfg.Name, projectCfg.Workspace, prNum)
+	vars := append(projectCfg.Terraform.GetVars(), cmd.Vars...)
 	varFiles := projectCfg.Terraform.GetVarFiles()
 	if len(cmd.VarFiles) > 0 {
 		varFiles = append(varFiles, cmd.VarFiles...)
 	}
-	a.action.StartGroup(fmt.Sprintf("mu plan --project=%s --workspce=%s", projectCfg.Name, projectCfg.Workspace))
-	planRet, err := tf.Plan(ctx, &terraform.PlanParams{
-		Vars:     append(projectCfg.Terraform.GetVars(), cmd.Vars...),
-		VarFiles: varFiles,
-		Destroy:  cmd.Destroy,
-		Out:      filename,
-	}, terraform.WithStream(os.Stdout))
-	_, _ = fmt.Fprintln(os.Stdout)
-	a.action.EndGroup()
-	if err != nil {
-		return nil, err
+	hooks := &hookParams{
+		prNum:    prNum,
+		sha:      sha,
+		project:  projectCfg,
+		planFile: filepath.Join(a.projectDir(projectCfg), filename),
 	}
-	if !a.disableSummaryLog {
-		a.outputPlanSummary(projectCfg, planRet.RawLog)
+
+	var (
+		checks  *planChecks
+		steps   []*stepOutput
+		planRet *terraform.Output
+	)
+	for _, step := range projectCfg.PlanSteps() {
+		switch step.Type() {
+		case config.StepInit:
+			if err := a.tfInit(ctx, tf, hooks, step.GetExtraArgs(), a.hidePlanResultComments); err != nil {
+				return nil, err
+			}
+		case config.StepRun:
+			stepOut, err := a.runStep(ctx, hooks, step.Run)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, stepOut)
+			if stepOut.hasError {
+				if err := a.hidePlanResultComments(ctx, prNum); err != nil {
+					return nil, err
+				}
+				return nil, a.outputStepFailedResult(ctx, prNum, muPlanMeta, projectCfg, steps)
+			}
+		case config.StepPlan:
+			checks, err = a.runPlanChecks(ctx, sha, projectCfg, tf)
+			if err != nil {
+				return nil, err
+			}
+			if checks.hasError() {
+				if err := a.hidePlanResultComments(ctx, prNum); err != nil {
+					return nil, err
+				}
+				if err := a.outputPlanChecksFailedResult(ctx, prNum, projectCfg, checks); err != nil {
+					return nil, err
+				}
+				return nil, errPlanChecksFailed
+			}
+
+			if err := a.runHooks(ctx, config.HookPrePlan, hooks); err != nil {
+				return nil, err
+			}
+			a.action.StartGroup(fmt.Sprintf("mu plan --project=%s --workspace=%s", projectCfg.Name, projectCfg.Workspace))
+			planRet, err = tf.Plan(ctx, &terraform.PlanParams{
+				Vars:      vars,
+				VarFiles:  varFiles,
+				Destroy:   cmd.Destroy,
+				Out:       filename,
+				ExtraArgs: step.GetExtraArgs(),
+			}, terraform.WithStream(os.Stdout))
+			_, _ = fmt.Fprintln(os.Stdout)
+			a.action.EndGroup()
+			if err != nil {
+				return nil, err
+			}
+			if !a.disableSummaryLog {
+				a.outputPlanSummary(projectCfg, planRet.RawLog)
+			}
+			if planRet.HasError {
+				if err := a.hidePlanResultComments(ctx, prNum); err != nil {
+					return nil, err
+				}
+				if err := a.outputPlanFailedResult(ctx, prNum, projectCfg, planRet); err != nil {
+					return nil, err
+				}
+				return nil, errPlanFailed
+			}
+			if err := a.runPostHooks(ctx, config.HookPostPlan, hooks); err != nil {
+				return nil, err
+			}
+		}
 	}
-	if err := a.hidePlanResultComments(ctx, prNum); err != nil {
+
+	planURL, err := a.storePlanFile(ctx, prNum, sha, baseSHA, tf, projectCfg, filename)
+	if err != nil {
 		return nil, err
 	}
-	if err := a.outputPlanResult(ctx, prNum, projectCfg, planRet); err != nil {
+	if err := a.hidePlanResultComments(ctx, prNum); err != nil {
 		return nil, err
 	}
-	if planRet.HasError {
-		return nil, errPlanFailed
-	}
-	if err := a.updateSuccessStatus(ctx, sha, projectCfg.Name, cmd.Type(), planRet); err != nil {
+	if err := a.updateSuccessStatus(ctx, sha, projectCfg.ID(), cmd.Type(), planRet); err != nil {
 		return nil, err
 	}
 
 	out = &outputPlan{
-		path:   filepath.Join(projectCfg.Dir, filename),
-		result: planRet.Result,
+		result:  planRet.Result,
+		planURL: planURL,
+		comment: a.planSucceededMessage(projectCfg, planRet, checks, steps, planURL),
 	}
 	return out, nil
 }
 
+// storePlanFile stores the plan file with its metadata in the plan store, and returns the URL of the stored plan.
+// The metadata is written before the encryption so that the checksum is of the plaintext plan file.
+func (a *App) storePlanFile(
+	ctx context.Context, prNum int, sha, baseSHA string, tf terraform.Terraform,
+	projectCfg *config.Project, filename string,
+) (string, error) {
+	metadataPath, err := a.writePlanMetadata(ctx, tf, a.projectDir(projectCfg), filename, &planMetadata{
+		HeadSHA:  sha,
+		BaseSHA:  baseSHA,
+		Vars:     projectCfg.Terraform.GetVars(),
+		VarFiles: projectCfg.Terraform.GetVarFiles(),
+	})
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(a.projectDir(projectCfg), filename)
+	if a.encrypter != nil {
+		if err := a.encrypter.Encrypt(path); err != nil {
+			return "", err
+		}
+	}
+	artifactName := a.genArtifactName(projectCfg.Name, projectCfg.Workspace, prNum)
+	return a.planStore.Put(ctx, artifactName, []string{path, metadataPath})
+}
+
 func (a *App) hidePlanResultComments(ctx context.Context, prNum int) error {
-	comments, err := a.github.ListPullRequestComments(ctx, prNum)
+	comments, err := a.vcs.ListComments(ctx, prNum)
 	if err != nil {
 		return err
 	}
 	for _, comment := range comments {
-		if comment.Author.Login != github.ActionBotName {
+		if !a.isBotComment(comment.Author.Login) {
 			continue
 		}
 		if comment.IsMinimized {
@@ -133,40 +203,69 @@ func (a *App) hidePlanResultComments(ctx context.Context, prNum int) error {
 		if !strings.HasPrefix(comment.Body, muInitMeta) && !strings.HasPrefix(comment.Body, muPlanMeta) {
 			continue
 		}
-		if err := a.github.HideIssueComment(ctx, comment.ID); err != nil {
+		if err := a.vcs.HideComment(ctx, comment.ID); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (a *App) outputPlanResult(ctx context.Context, prNum int, cfg *config.Project, out *terraform.Output) error {
-	if out.HasError {
-		return a.outputPlanFailedResult(ctx, prNum, cfg, out)
+// planComments groups the plan results by the project, so that the workspaces of a project are commented together.
+type planComments struct {
+	projects []string
+	comments map[string][]string
+}
+
+func (c *planComments) add(project, comment string) {
+	if c.comments == nil {
+		c.comments = make(map[string][]string)
+	}
+	if _, ok := c.comments[project]; !ok {
+		c.projects = append(c.projects, project)
 	}
-	return a.outputPlanSucceededResult(ctx, prNum, cfg, out)
+	c.comments[project] = append(c.comments[project], comment)
 }
 
-func (a *App) outputPlanSucceededResult(
+// outputPlanSucceededResults comments the plan results with a comment per project.
+func (a *App) outputPlanSucceededResults(ctx context.Context, prNum int, comments *planComments) error {
+	for _, project := range comments.projects {
+		comment := new(strings.Builder)
+		for i, result := range comments.comments[project] {
+			if i > 0 {
+				result = strings.TrimPrefix(result, muPlanMeta)
+			}
+			comment.WriteString(result)
+		}
+		messages := a.splitMessages(strings.NewReader(comment.String()))
+		for _, msg := range messages {
+			if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *App) outputPlanFailedResult(
 	ctx context.Context, prNum int, cfg *config.Project, out *terraform.Output,
 ) error {
-	comment := a.planSucceededMessage(cfg, out)
+	comment := a.planFailedMessage(cfg, out)
 	messages := a.splitMessages(strings.NewReader(comment))
 	for _, msg := range messages {
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (a *App) outputPlanFailedResult(
-	ctx context.Context, prNum int, cfg *config.Project, out *terraform.Output,
+func (a *App) outputPlanChecksFailedResult(
+	ctx context.Context, prNum int, cfg *config.Project, checks *planChecks,
 ) error {
-	comment := a.planFailedMessage(cfg, out)
+	comment := a.planChecksFailedMessage(cfg, checks)
 	messages := a.splitMessages(strings.NewReader(comment))
 	for _, msg := range messages {
-		if err := a.github.CreateIssueComment(ctx, prNum, msg); err != nil {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
 			return err
 		}
 	}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,16 +12,18 @@ import (
 
 	"github.com/yu-icchi/mu/pkg/command"
 	"github.com/yu-icchi/mu/pkg/config"
-	"github.com/yu-icchi/mu/pkg/github"
 	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func TestApp_tfPlan(t *testing.T) {
 	t.Setenv("GITHUB_REPOSITORY", "test/mu")
 	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test_default_1.tfplan"), []byte("plan data"), 0644))
 	project := &config.Project{
 		Name:      "test",
-		Dir:       ".",
+		Dir:       dir,
 		Workspace: "default",
 		Terraform: &config.Terraform{
 			Version: "1.9.1",
@@ -33,12 +37,14 @@ func TestApp_tfPlan(t *testing.T) {
 		ctx   context.Context
 		prNum int
 		sha   string
+		base  string
 		cfg   *config.Project
 		cmd   *command.Plan
 	}
 	type expect struct {
-		out *outputPlan
-		err error
+		out     *outputPlan
+		comment string
+		err     error
 	}
 	tests := []struct {
 		name    string
@@ -52,22 +58,23 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -107,11 +114,15 @@ func TestApp_tfPlan(t *testing.T) {
 							RawLog:             "init log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.planStore.EXPECT().Put(ctx, "mu_test_default_1", []string{
+					filepath.Join(dir, "test_default_1.tfplan"),
+					filepath.Join(dir, "test_default_1.tfplan.meta.json"),
+				}).Return("https://github.com/test/mu/actions/runs/test-run-id/artifacts/1", nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: actionURL,
 					Desc:      "plan result",
 					Context:   src,
@@ -119,10 +130,11 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 			expect: expect{
 				out: &outputPlan{
-					path:   "test_default_1.tfplan",
-					result: "plan result",
+					result:  "plan result",
+					planURL: "https://github.com/test/mu/actions/runs/test-run-id/artifacts/1",
 				},
-				err: nil,
+				comment: "plan file: [download](https://github.com/test/mu/actions/runs/test-run-id/artifacts/1)",
+				err:     nil,
 			},
 		},
 		{
@@ -131,6 +143,7 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd: &command.Plan{
 					Project:  "test",
@@ -140,16 +153,16 @@ func TestApp_tfPlan(t *testing.T) {
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -191,7 +204,7 @@ func TestApp_tfPlan(t *testing.T) {
 							RawLog:             "init log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{
 					{
 						ID: "test-commit-id-01",
 						Author: struct {
@@ -230,11 +243,15 @@ func TestApp_tfPlan(t *testing.T) {
 						Body: "message",
 					},
 				}, nil)
-				m.github.EXPECT().HideIssueComment(ctx, "test-commit-id-01").Return(nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().HideComment(ctx, "test-commit-id-01").Return(nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.planStore.EXPECT().Put(ctx, "mu_test_default_1", []string{
+					filepath.Join(dir, "test_default_1.tfplan"),
+					filepath.Join(dir, "test_default_1.tfplan.meta.json"),
+				}).Return("https://github.com/test/mu/actions/runs/test-run-id/artifacts/1", nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: actionURL,
 					Desc:      "plan result",
 					Context:   src,
@@ -242,10 +259,11 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 			expect: expect{
 				out: &outputPlan{
-					path:   "test_default_1.tfplan",
-					result: "plan result",
+					result:  "plan result",
+					planURL: "https://github.com/test/mu/actions/runs/test-run-id/artifacts/1",
 				},
-				err: nil,
+				comment: "plan file: [download](https://github.com/test/mu/actions/runs/test-run-id/artifacts/1)",
+				err:     nil,
 			},
 		},
 		{
@@ -254,22 +272,23 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -297,11 +316,11 @@ func TestApp_tfPlan(t *testing.T) {
 					Error:              nil,
 					RawLog:             "init log",
 				}, nil)
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -317,22 +336,23 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -349,11 +369,11 @@ func TestApp_tfPlan(t *testing.T) {
 							HasError: true,
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -364,59 +384,80 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to github.updatePendingStatus",
+			name: "pre_init hook failed",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
-				cfg:   project,
-				cmd:   &command.Plan{},
+				base:  "test-base-sha",
+				cfg: &config.Project{
+					Name:      "test",
+					Dir:       dir,
+					Workspace: "default",
+					Terraform: project.Terraform,
+					Plan:      project.Plan,
+					Hooks: &config.Hooks{
+						PreInit: []string{"exit 1"},
+					},
+				},
+				cmd: &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
-				}).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.terraform.EXPECT().Setup(ctx).Return(nil)
+				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, body string) error {
+						assert.Contains(t, body, ":x: **Hook Failed** `pre_init`")
+						return nil
+					})
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
 				}).Return(nil)
 			},
 			expect: expect{
-				err: assert.AnError,
+				err: errHookFailed,
 			},
 		},
 		{
-			name: "failed to github.FindPullRequestByLabel",
+			name: "failed to github.updatePendingStatus",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
-				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				}).Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -427,31 +468,29 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to terraform.Setup",
+			name: "failed to vcs.FindPullRequestByLabel",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
-				m.terraform.EXPECT().Setup(ctx).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -462,32 +501,32 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to terraform.CompareVersion",
+			name: "failed to terraform.Setup",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
-				m.terraform.EXPECT().Setup(ctx).Return(nil)
-				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.terraform.EXPECT().Setup(ctx).Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -498,37 +537,33 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to terraform.Init",
+			name: "failed to terraform.CompareVersion",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
-				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
-				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
-				m.terraform.EXPECT().Init(ctx, &terraform.InitParams{
-					BackendConfig:     nil,
-					BackendConfigPath: "",
-				}, gomock.Any()).Return(nil, assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -539,33 +574,38 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to terraform.SwitchWorkspace",
+			name: "failed to terraform.Init",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
-				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
+				m.terraform.EXPECT().Init(ctx, &terraform.InitParams{
+					BackendConfig:     nil,
+					BackendConfigPath: "",
+				}, gomock.Any()).Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -576,45 +616,34 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to terraform.Plan",
+			name: "failed to terraform.SwitchWorkspace",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
-				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
-				m.terraform.EXPECT().Init(ctx, &terraform.InitParams{
-					BackendConfig:     nil,
-					BackendConfigPath: "",
-				}, gomock.Any()).Return(&terraform.Output{
-					RawLog: "init log",
-				}, nil)
-				m.terraform.EXPECT().Plan(ctx, &terraform.PlanParams{
-					Vars:     nil,
-					VarFiles: nil,
-					Destroy:  false,
-					Out:      "test_default_1.tfplan",
-				}, gomock.Any()).Return(nil, assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -625,27 +654,28 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to github.ListPullRequestComments",
+			name: "failed to terraform.Plan",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -660,23 +690,10 @@ func TestApp_tfPlan(t *testing.T) {
 					VarFiles: nil,
 					Destroy:  false,
 					Out:      "test_default_1.tfplan",
-				}, gomock.Any()).Return(&terraform.Output{
-					Result:             "result",
-					OutsideTerraform:   "",
-					ChangedResult:      "change_result",
-					Warning:            "",
-					HasAddOrUpdateOnly: true,
-					HasDestroy:         false,
-					HasNoChanges:       false,
-					HasError:           false,
-					HasParseError:      false,
-					Error:              nil,
-					RawLog:             "init log",
-				}, nil)
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return(nil, assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				}, gomock.Any()).Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -687,27 +704,28 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to github.CreateIssueComment",
+			name: "failed to vcs.ListComments",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -735,11 +753,12 @@ func TestApp_tfPlan(t *testing.T) {
 					Error:              nil,
 					RawLog:             "init log",
 				}, nil)
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.planStore.EXPECT().Put(ctx, "mu_test_default_1", gomock.Any()).Return("", nil)
+				m.vcs.EXPECT().ListComments(ctx, 1).Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -750,27 +769,28 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to github.CreateCommitStatus succeeded",
+			name: "failed to vcs.CreateCommitStatus succeeded",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -798,18 +818,22 @@ func TestApp_tfPlan(t *testing.T) {
 					Error:              nil,
 					RawLog:             "init log",
 				}, nil)
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.planStore.EXPECT().Put(ctx, "mu_test_default_1", []string{
+					filepath.Join(dir, "test_default_1.tfplan"),
+					filepath.Join(dir, "test_default_1.tfplan.meta.json"),
+				}).Return("https://github.com/test/mu/actions/runs/test-run-id/artifacts/1", nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: actionURL,
 					Desc:      "result",
 					Context:   src,
 				}).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -825,24 +849,25 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
-				}).DoAndReturn(func(ctx context.Context, commitStatus *github.CommitStatus) error {
+				}).DoAndReturn(func(ctx context.Context, commitStatus *vcs.CommitStatus) error {
 					panic("panic create commit status")
 				})
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -858,22 +883,23 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   "mu/plan: test",
@@ -889,22 +915,23 @@ func TestApp_tfPlan(t *testing.T) {
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -944,11 +971,15 @@ func TestApp_tfPlan(t *testing.T) {
 							RawLog:             "init log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(nil)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.planStore.EXPECT().Put(ctx, "mu_test_default_1", []string{
+					filepath.Join(dir, "test_default_1.tfplan"),
+					filepath.Join(dir, "test_default_1.tfplan.meta.json"),
+				}).Return("https://github.com/test/mu/actions/runs/test-run-id/artifacts/1", nil)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.SuccessStatus,
+					Status:    vcs.SuccessStatus,
 					TargetURL: actionURL,
 					Desc:      "plan result",
 					Context:   src,
@@ -956,34 +987,36 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 			expect: expect{
 				out: &outputPlan{
-					path:   "test_default_1.tfplan",
-					result: "plan result",
+					result:  "plan result",
+					planURL: "https://github.com/test/mu/actions/runs/test-run-id/artifacts/1",
 				},
-				err: nil,
+				comment: "plan file: [download](https://github.com/test/mu/actions/runs/test-run-id/artifacts/1)",
+				err:     nil,
 			},
 		},
 		{
-			name: "init failed: failed to github.ListPullRequestComments",
+			name: "init failed: failed to vcs.ListComments",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -1000,10 +1033,10 @@ func TestApp_tfPlan(t *testing.T) {
 							HasError: true,
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return(nil, assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return(nil, assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -1014,27 +1047,28 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "init failed: failed to github.CreateIssueComment",
+			name: "init failed: failed to vcs.CreateComment",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -1051,11 +1085,11 @@ func TestApp_tfPlan(t *testing.T) {
 							HasError: true,
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -1066,11 +1100,12 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to github.HideIssueComment",
+			name: "failed to vcs.HideComment",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd: &command.Plan{
 					Project: "test",
@@ -1079,16 +1114,16 @@ func TestApp_tfPlan(t *testing.T) {
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -1128,7 +1163,9 @@ func TestApp_tfPlan(t *testing.T) {
 							RawLog:             "init log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{
+				m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+				m.planStore.EXPECT().Put(ctx, "mu_test_default_1", gomock.Any()).Return("", nil)
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{
 					{
 						ID: "test-commit-id-01",
 						Author: struct {
@@ -1139,10 +1176,10 @@ func TestApp_tfPlan(t *testing.T) {
 						Body: "<!-- mu:plan -->\ntest plan log",
 					},
 				}, nil)
-				m.github.EXPECT().HideIssueComment(ctx, "test-commit-id-01").Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().HideComment(ctx, "test-commit-id-01").Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -1153,27 +1190,28 @@ func TestApp_tfPlan(t *testing.T) {
 			},
 		},
 		{
-			name: "failed to github.CreateIssueComment",
+			name: "failed to vcs.CreateComment",
 			args: args{
 				ctx:   context.Background(),
 				prNum: 1,
 				sha:   "test-sha",
+				base:  "test-base-sha",
 				cfg:   project,
 				cmd:   &command.Plan{},
 			},
 			prepare: func(ctx context.Context, m *mock, t *testing.T) {
 				actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
 				src := "mu/plan: test"
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.PendingStatus,
+					Status:    vcs.PendingStatus,
 					TargetURL: actionURL,
 					Desc:      "in progress...",
 					Context:   src,
 				}).Return(nil)
-				m.github.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, github.ErrNotFound)
-				m.github.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
-				m.github.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+				m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+				m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+				m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
 				m.terraform.EXPECT().Setup(ctx).Return(nil)
 				m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
 				m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
@@ -1213,11 +1251,11 @@ func TestApp_tfPlan(t *testing.T) {
 							RawLog:             "plan failed log",
 						}, nil
 					})
-				m.github.EXPECT().ListPullRequestComments(ctx, 1).Return([]*github.Comment{}, nil)
-				m.github.EXPECT().CreateIssueComment(ctx, 1, gomock.Any()).Return(assert.AnError)
-				m.github.EXPECT().CreateCommitStatus(ctx, &github.CommitStatus{
+				m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+				m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).Return(assert.AnError)
+				m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
 					Sha:       "test-sha",
-					Status:    github.FailureStatus,
+					Status:    vcs.FailureStatus,
 					TargetURL: actionURL,
 					Desc:      "failed.",
 					Context:   src,
@@ -1235,14 +1273,33 @@ func TestApp_tfPlan(t *testing.T) {
 			defer ctrl.Finish()
 			app, mock := newTestAppAndMock(ctrl)
 			tt.prepare(tt.args.ctx, mock, t)
-			out, err := app.tfPlan(tt.args.ctx, tt.args.prNum, tt.args.sha, tt.args.cfg, tt.args.cmd)
+			out, err := app.tfPlan(tt.args.ctx, tt.args.prNum, tt.args.sha, tt.args.base, tt.args.cfg, tt.args.cmd)
 			if tt.expect.err != nil {
 				require.Error(t, err)
 				assert.ErrorIs(t, err, tt.expect.err)
 				return
 			}
 			require.NoError(t, err)
-			assert.Equal(t, tt.expect.out, out)
+			assert.Equal(t, tt.expect.out.result, out.result)
+			assert.Equal(t, tt.expect.out.planURL, out.planURL)
+			assert.Contains(t, out.comment, tt.expect.comment)
 		})
 	}
 }
+
+func TestApp_outputPlanSucceededResults(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	app, m := newTestAppAndMock(ctrl)
+
+	comments := &planComments{}
+	comments.add("app", muPlanMeta+"\nworkspace: `dev`\n")
+	comments.add("network", muPlanMeta+"\nworkspace: `default`\n")
+	comments.add("app", muPlanMeta+"\nworkspace: `prod`\n")
+	gomock.InOrder(
+		m.vcs.EXPECT().CreateComment(ctx, 1, muPlanMeta+"\nworkspace: `dev`\n\nworkspace: `prod`\n").Return(nil),
+		m.vcs.EXPECT().CreateComment(ctx, 1, muPlanMeta+"\nworkspace: `default`\n").Return(nil),
+	)
+	require.NoError(t, app.outputPlanSucceededResults(ctx, 1, comments))
+}
@@ -12,7 +12,10 @@ import (
 )
 
 func (a *App) tfStateRm(ctx context.Context, prNum int, cfg *config.Project, cmd *command.StateRm) error {
-	if err := a.lock(ctx, cfg.Name, prNum, cmd.Type(), cfg.LockLabelColor); err != nil {
+	defer a.lockProject(cfg)()
+	auditProject(ctx, cfg)
+
+	if err := a.lock(ctx, cfg.ID(), prNum, cmd.Type(), cfg.LockLabelColor); err != nil {
 		return err
 	}
 
@@ -27,6 +30,10 @@ func (a *App) tfStateRm(ctx context.Context, prNum int, cfg *config.Project, cmd
 		return err
 	}
 
+	if err := a.runHooks(ctx, config.HookPreInit, &hookParams{prNum: prNum, project: cfg}); err != nil {
+		return err
+	}
+
 	a.action.StartGroup(fmt.Sprintf("mu init --project %s --workspace %s", cfg.Name, cfg.Workspace))
 	initRet, err := tf.Init(ctx, &terraform.InitParams{
 		BackendConfig:     cfg.Terraform.GetBackendConfig(),
@@ -68,7 +75,7 @@ func (a *App) tfStateRm(ctx context.Context, prNum int, cfg *config.Project, cmd
 		}
 	}
 
-	if err := a.github.CreateIssueComment(ctx, prNum, msg.String()); err != nil {
+	if err := a.vcs.CreateComment(ctx, prNum, msg.String()); err != nil {
 		return err
 	}
 	return nil
@@ -1 +1,53 @@
 package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yu-icchi/mu/pkg/config"
+)
+
+func TestApp_findProjectConfigs(t *testing.T) {
+	t.Parallel()
+	dev := &config.Project{Name: "app", Dir: "app", Workspace: "dev", Plan: &config.Plan{Paths: []string{"*.tf"}}}
+	prod := &config.Project{Name: "app", Dir: "app", Workspace: "prod", Plan: &config.Plan{Paths: []string{"*.tf"}}}
+	network := &config.Project{Name: "network", Dir: "network", Workspace: "prod", Plan: &config.Plan{Paths: []string{"*.tf"}}}
+	cfg := &config.Config{Projects: []*config.Project{dev, prod, network}}
+	app := &App{}
+
+	tests := []struct {
+		name      string
+		project   string
+		workspace string
+		expect    config.Projects
+	}{
+		{
+			name:    "every workspace of the project",
+			project: "app",
+			expect:  config.Projects{dev, prod},
+		},
+		{
+			name:      "workspace of the project",
+			project:   "app",
+			workspace: "prod",
+			expect:    config.Projects{prod},
+		},
+		{
+			name:   "changed projects",
+			expect: config.Projects{dev, prod},
+		},
+		{
+			name:      "workspace of the changed projects",
+			workspace: "dev",
+			expect:    config.Projects{dev},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			projects := app.findProjectConfigs(cfg, tt.project, tt.workspace, []string{"app/main.tf"})
+			assert.Equal(t, tt.expect, projects)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/hook"
+	"github.com/yu-icchi/mu/pkg/terraform"
+)
+
+// stepOutput is the output of a run step of the workflow, which is shown in the comment.
+type stepOutput struct {
+	command  string
+	result   string
+	hasError bool
+}
+
+// runStep runs the shell command of the run step in the project directory.
+func (a *App) runStep(ctx context.Context, params *hookParams, command string) (*stepOutput, error) {
+	cfg := params.project
+	a.action.StartGroup(fmt.Sprintf("mu run --project=%s --workspace=%s: %s", cfg.Name, cfg.Workspace, command))
+	out, err := hook.Run(ctx, &hook.Params{
+		Command: command,
+		Dir:     a.projectDir(cfg),
+		Env:     params.env(),
+		Stream:  os.Stdout,
+	})
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil {
+		return nil, err
+	}
+	result := strings.TrimSpace(out.Result)
+	if out.HasError && result == "" {
+		result = out.Error.Error()
+	}
+	return &stepOutput{
+		command:  command,
+		result:   result,
+		hasError: out.HasError,
+	}, nil
+}
+
+// tfInit runs the pre_init hooks and terraform init with the extra arguments of the init step.
+// The failure of init is commented on the pull request, after hide hides the previous results if it is not nil.
+func (a *App) tfInit(
+	ctx context.Context, tf terraform.Terraform, hooks *hookParams, extraArgs []string,
+	hide func(ctx context.Context, prNum int) error,
+) error {
+	cfg := hooks.project
+	if err := a.runHooks(ctx, config.HookPreInit, hooks); err != nil {
+		return err
+	}
+
+	a.action.StartGroup(fmt.Sprintf("mu init --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	initRet, err := tf.Init(ctx, &terraform.InitParams{
+		BackendConfig:     cfg.Terraform.GetBackendConfig(),
+		BackendConfigPath: cfg.Terraform.GetBackendConfigPath(),
+		ExtraArgs:         extraArgs,
+	}, terraform.WithStream(os.Stdout))
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil {
+		return err
+	}
+	if !initRet.HasError {
+		return nil
+	}
+	if !a.disableSummaryLog {
+		a.outputInitFailedSummary(cfg, initRet.RawLog)
+	}
+	if hooks.prNum == 0 {
+		return errInitFailed
+	}
+	if hide != nil {
+		if err := hide(ctx, hooks.prNum); err != nil {
+			return err
+		}
+	}
+	if err := a.outputInitFailedResult(ctx, hooks.prNum, cfg, initRet); err != nil {
+		return err
+	}
+	return errInitFailed
+}
+
+// runApplySteps runs the apply steps of the workflow, and comments the result with the outputs of the run steps,
+// which follow the outputs of the steps run before. The init step is skipped if the project is already initialized.
+// The failures are commented on the pull request, after hide hides the previous results if it is not nil.
+func (a *App) runApplySteps(
+	ctx context.Context, tf terraform.Terraform, hooks *hookParams, filename string,
+	initialized bool, steps []*stepOutput, hide func(ctx context.Context, prNum int) error,
+) (*terraform.Output, error) {
+	cfg := hooks.project
+	var (
+		applyRet     *terraform.Output
+		outputValues []*terraform.OutputValue
+	)
+	for _, step := range cfg.ApplySteps() {
+		switch step.Type() {
+		case config.StepInit:
+			if initialized {
+				continue
+			}
+			if err := a.tfInit(ctx, tf, hooks, step.GetExtraArgs(), hide); err != nil {
+				return nil, err
+			}
+		case config.StepRun:
+			stepOut, err := a.runStep(ctx, hooks, step.Run)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, stepOut)
+			if !stepOut.hasError {
+				continue
+			}
+			if hide != nil {
+				if err := hide(ctx, hooks.prNum); err != nil {
+					return nil, err
+				}
+			}
+			// The plan has been applied when a step after apply fails.
+			if applyRet != nil {
+				err := a.outputApplySucceededResult(ctx, hooks.prNum, cfg, applyRet, outputValues, steps[:len(steps)-1])
+				if err != nil {
+					return nil, err
+				}
+			}
+			return nil, a.outputStepFailedResult(ctx, hooks.prNum, muApplyMeta, cfg, steps)
+		case config.StepApply:
+			var err error
+			applyRet, outputValues, err = a.runApplyStep(ctx, tf, hooks, filename, step.GetExtraArgs(), hide)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if hide != nil {
+		if err := hide(ctx, hooks.prNum); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.outputApplySucceededResult(ctx, hooks.prNum, cfg, applyRet, outputValues, steps); err != nil {
+		return nil, err
+	}
+	return applyRet, nil
+}
+
+// runApplyStep runs the pre_apply hooks, terraform apply of the plan file with the extra arguments of the apply step,
+// and the post_apply hooks. It returns the output values of the root module after apply.
+// The failure of apply is commented on the pull request, after hide hides the previous results if it is not nil.
+func (a *App) runApplyStep(
+	ctx context.Context, tf terraform.Terraform, hooks *hookParams, filename string, extraArgs []string,
+	hide func(ctx context.Context, prNum int) error,
+) (*terraform.Output, []*terraform.OutputValue, error) {
+	cfg := hooks.project
+	if err := a.runHooks(ctx, config.HookPreApply, hooks); err != nil {
+		return nil, nil, err
+	}
+
+	a.action.StartGroup(fmt.Sprintf("mu apply --project=%s --workspace=%s", cfg.Name, cfg.Workspace))
+	applyRet, err := tf.Apply(ctx, &terraform.ApplyParams{
+		PlanFilePath: filename,
+		ExtraArgs:    extraArgs,
+	}, terraform.WithStream(os.Stdout))
+	_, _ = fmt.Fprintln(os.Stdout)
+	a.action.EndGroup()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !a.disableSummaryLog {
+		a.outputApplySummary(cfg, applyRet.RawLog)
+	}
+	if applyRet.HasError {
+		if hide != nil {
+			if err := hide(ctx, hooks.prNum); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := a.outputApplyFailedResult(ctx, hooks.prNum, cfg, applyRet); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errApplyFailed
+	}
+	outputValues := a.getOutputValues(ctx, tf)
+	if err := a.runPostHooks(ctx, config.HookPostApply, hooks); err != nil {
+		return nil, nil, err
+	}
+	return applyRet, outputValues, nil
+}
+
+// outputStepFailedResult comments the outputs of the run steps up to the failed one, and returns errStepFailed.
+func (a *App) outputStepFailedResult(
+	ctx context.Context, prNum int, meta string, cfg *config.Project, steps []*stepOutput,
+) error {
+	failed := steps[len(steps)-1]
+	err := fmt.Errorf("%s: %w", failed.command, errStepFailed)
+	if prNum == 0 {
+		return err
+	}
+	comment := a.stepFailedMessage(meta, cfg, steps)
+	messages := a.splitMessages(strings.NewReader(comment))
+	for _, msg := range messages {
+		if err := a.vcs.CreateComment(ctx, prNum, msg); err != nil {
+			return err
+		}
+	}
+	return err
+}
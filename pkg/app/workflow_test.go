@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/command"
+	"github.com/yu-icchi/mu/pkg/config"
+	"github.com/yu-icchi/mu/pkg/terraform"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestApp_tfPlan_Workflow(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	actionURL := "https://github.com/test/mu/actions/runs/test-run-id"
+	newProject := func(t *testing.T, steps []*config.Step) *config.Project {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test_default_1.tfplan"), []byte("plan data"), 0644))
+		project := &config.Project{
+			Name:      "test",
+			Dir:       dir,
+			Workspace: "default",
+			Terraform: &config.Terraform{Version: "1.9.1"},
+			Plan:      &config.Plan{Paths: []string{"*.tf*"}},
+		}
+		project.SetWorkflow(&config.Workflow{Plan: steps})
+		return project
+	}
+	prepareLock := func(ctx context.Context, m *mock) {
+		m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+			Sha:       "test-sha",
+			Status:    vcs.PendingStatus,
+			TargetURL: actionURL,
+			Desc:      "in progress...",
+			Context:   "mu/plan: test",
+		}).Return(nil)
+		m.vcs.EXPECT().FindPullRequestByLabel(ctx, "mu_lock_test").Return(nil, vcs.ErrNotFound)
+		m.vcs.EXPECT().CreateLabel(ctx, "mu_lock_test", "PR: #1", "").Return(nil)
+		m.vcs.EXPECT().AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}).Return(nil)
+		m.terraform.EXPECT().Setup(ctx).Return(nil)
+		m.terraform.EXPECT().CompareVersion(ctx, "1.9.1").Return(nil)
+		m.terraform.EXPECT().SwitchWorkspace(ctx, "default").Return(nil)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+		app, m := newTestAppAndMock(ctrl)
+		project := newProject(t, []*config.Step{
+			{Run: "echo generated > backend.tf"},
+			{Init: &config.StepArgs{ExtraArgs: []string{"-upgrade"}}},
+			{Plan: &config.StepArgs{ExtraArgs: []string{"-parallelism=5"}}},
+			{Run: `test -f "$MU_PLAN_FILE" && echo "policy ok"`},
+		})
+		prepareLock(ctx, m)
+		m.terraform.EXPECT().Init(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params *terraform.InitParams, _ ...terraform.Option) (*terraform.Output, error) {
+				assert.FileExists(t, filepath.Join(project.Dir, "backend.tf"))
+				assert.Equal(t, []string{"-upgrade"}, params.ExtraArgs)
+				return &terraform.Output{}, nil
+			})
+		m.terraform.EXPECT().Plan(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params *terraform.PlanParams, _ ...terraform.Option) (*terraform.Output, error) {
+				assert.Equal(t, []string{"-parallelism=5"}, params.ExtraArgs)
+				return &terraform.Output{Result: "plan result"}, nil
+			})
+		m.terraform.EXPECT().Version(ctx).Return("1.9.1", nil, nil)
+		m.planStore.EXPECT().Put(ctx, "mu_test_default_1", gomock.Any()).Return("", nil)
+		m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+		m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+			Sha:       "test-sha",
+			Status:    vcs.SuccessStatus,
+			TargetURL: actionURL,
+			Desc:      "plan result",
+			Context:   "mu/plan: test",
+		}).Return(nil)
+
+		out, err := app.tfPlan(ctx, 1, "test-sha", "test-base-sha", project, &command.Plan{})
+		require.NoError(t, err)
+		assert.Equal(t, "plan result", out.result)
+		assert.Contains(t, out.comment, "<details><summary>Show Steps</summary>")
+		assert.Contains(t, out.comment, ":white_check_mark: `echo generated > backend.tf`\n")
+		assert.Contains(t, out.comment, "```\npolicy ok\n```")
+	})
+
+	t.Run("run step failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx := context.Background()
+		app, m := newTestAppAndMock(ctrl)
+		project := newProject(t, []*config.Step{
+			{Run: "echo 'missing secret' && exit 1"},
+			{Init: &config.StepArgs{}},
+			{Plan: &config.StepArgs{}},
+		})
+		prepareLock(ctx, m)
+		m.vcs.EXPECT().ListComments(ctx, 1).Return([]*vcs.Comment{}, nil)
+		m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, body string) error {
+				assert.Contains(t, body, muPlanMeta+"\n:x: **Workflow Step Failed**")
+				assert.Contains(t, body, ":x: `echo 'missing secret' && exit 1`\n> [!CAUTION]\n> missing secret\n")
+				return nil
+			})
+		m.vcs.EXPECT().CreateCommitStatus(ctx, &vcs.CommitStatus{
+			Sha:       "test-sha",
+			Status:    vcs.FailureStatus,
+			TargetURL: actionURL,
+			Desc:      "failed.",
+			Context:   "mu/plan: test",
+		}).Return(nil)
+
+		_, err := app.tfPlan(ctx, 1, "test-sha", "test-base-sha", project, &command.Plan{})
+		assert.ErrorIs(t, err, errStepFailed)
+	})
+}
+
+func TestApp_runApplySteps(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	app, m := newTestAppAndMock(ctrl)
+	project := &config.Project{
+		Name:      "test",
+		Dir:       t.TempDir(),
+		Workspace: "default",
+	}
+	project.SetWorkflow(&config.Workflow{
+		Apply: []*config.Step{
+			{Init: &config.StepArgs{}},
+			{Apply: &config.StepArgs{ExtraArgs: []string{"-parallelism=5"}}},
+			{Run: "echo notified"},
+			{Run: "exit 1"},
+		},
+	})
+	m.terraform.EXPECT().Apply(ctx, &terraform.ApplyParams{
+		PlanFilePath: "test_default_1.tfplan",
+		ExtraArgs:    []string{"-parallelism=5"},
+	}, gomock.Any()).Return(&terraform.Output{Result: "Apply complete!"}, nil)
+	m.terraform.EXPECT().Output(ctx).Return(&terraform.OutputsOutput{}, nil)
+	gomock.InOrder(
+		m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, body string) error {
+				assert.Contains(t, body, ":white_check_mark: **Apply Result**")
+				assert.Contains(t, body, ":white_check_mark: `echo notified`")
+				assert.NotContains(t, body, "exit 1")
+				return nil
+			}),
+		m.vcs.EXPECT().CreateComment(ctx, 1, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, body string) error {
+				assert.Contains(t, body, muApplyMeta+"\n:x: **Workflow Step Failed**")
+				assert.Contains(t, body, ":x: `exit 1`")
+				return nil
+			}),
+	)
+
+	hooks := &hookParams{prNum: 1, project: project}
+	_, err := app.runApplySteps(ctx, app.terraform, hooks, "test_default_1.tfplan", true, nil, nil)
+	assert.ErrorIs(t, err, errStepFailed)
+}
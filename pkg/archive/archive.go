@@ -4,5 +4,6 @@ package archive
 //go:generate mockgen -source=archive.go -package=mock -destination=mock/mock.go Archive
 
 type Archive interface {
+	Compress(dest string, files []string) error
 	Decompress(dest, src string) error
 }
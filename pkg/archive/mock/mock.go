@@ -39,6 +39,20 @@ func (m *MockArchive) EXPECT() *MockArchiveMockRecorder {
 	return m.recorder
 }
 
+// Compress mocks base method.
+func (m *MockArchive) Compress(dest string, files []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compress", dest, files)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compress indicates an expected call of Compress.
+func (mr *MockArchiveMockRecorder) Compress(dest, files any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compress", reflect.TypeOf((*MockArchive)(nil).Compress), dest, files)
+}
+
 // Decompress mocks base method.
 func (m *MockArchive) Decompress(dest, src string) error {
 	m.ctrl.T.Helper()
@@ -17,6 +17,53 @@ func NewZipArchiver() Archive {
 	return &zipArchiver{}
 }
 
+// Compress writes the files into the zip file at dest.
+// The files are stored with their base names.
+func (z *zipArchiver) Compress(dest string, files []string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+
+	w := zip.NewWriter(out)
+	for _, file := range files {
+		if err := z.zip(w, file); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func (z *zipArchiver) zip(w *zip.Writer, src string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.Base(src)
+	header.Method = zip.Deflate
+	fw, err := w.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, file)
+	return err
+}
+
 func (z *zipArchiver) Decompress(dest, src string) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
@@ -2,6 +2,7 @@ package archive
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,3 +22,21 @@ func TestUnzip(t *testing.T) {
 	assert.Equal(t, "aws.tfplan", info.Name())
 	assert.False(t, info.IsDir())
 }
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	src := filepath.Join(dir, "aws.tfplan")
+	require.NoError(t, os.WriteFile(src, []byte("plan data"), 0644))
+	dest := filepath.Join(dir, "mu_aws.zip")
+	archiver := NewZipArchiver()
+	err := archiver.Compress(dest, []string{src})
+	require.NoError(t, err)
+
+	out := t.TempDir()
+	err = archiver.Decompress(out, dest)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(out, "aws.tfplan"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan data", string(data))
+}
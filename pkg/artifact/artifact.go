@@ -1,75 +1,257 @@
 package artifact
 
+//go:generate mkdir -p mock
+//go:generate mockgen -source=artifact.go -package=mock -destination=mock/mock.go Client
+
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+const (
+	artifactService = "/twirp/github.actions.results.api.v1.ArtifactService/"
+	artifactVersion = 4
+)
 
-	"gopkg.in/yaml.v3"
+var (
+	errInvalidRuntimeToken = errors.New("invalid runtime token")
+	errEmptyResultsURL     = errors.New("results url is empty")
+	errUnexpectedStatus    = errors.New("unexpected status")
+	errUploadFailed        = errors.New("upload failed")
 )
 
-type uploadArtifactWithOptions struct {
-	Name      string `yaml:"name,omitempty"`
-	Path      string `yaml:"path,omitempty"`
-	Overwrite bool   `yaml:"overwrite,omitempty"`
+// Client uploads the artifacts with the GitHub Actions artifact v4 protocol,
+// which is the same protocol as actions/upload-artifact@v4.
+type Client interface {
+	// Upload uploads the file at path as the artifact named name.
+	Upload(ctx context.Context, name, path string) (*Artifact, error)
 }
 
-func (u *uploadArtifactWithOptions) withType() string {
-	return "upload_artifact"
+type Artifact struct {
+	ID   int64
+	Name string
+	Size int64
 }
 
-type UploadArtifactParams struct {
-	Version   string
-	Dir       string
-	Artifacts []*Artifact
+type Params struct {
+	// RuntimeToken is ACTIONS_RUNTIME_TOKEN.
+	RuntimeToken string
+	// ResultsURL is ACTIONS_RESULTS_URL.
+	ResultsURL string
+	HTTPClient *http.Client
+	MaxTries   uint
 }
 
-type Artifact struct {
-	Name      string
-	Path      string
-	Overwrite bool
+type client struct {
+	token      string
+	resultsURL string
+	cli        *http.Client
+	maxTries   uint
+	newBackOff func() backoff.BackOff
 }
 
-func UploadArtifacts(params *UploadArtifactParams) error {
-	const (
-		using      = "composite"
-		uses       = "actions/upload-artifact@"
-		actionFile = "action.yaml"
-	)
-	steps := make([]Setup, 0, len(params.Artifacts))
-	for _, artifact := range params.Artifacts {
-		step := Setup{
-			Name: artifact.Name,
-			Uses: uses + params.Version,
-			With: &uploadArtifactWithOptions{
-				Name:      artifact.Name,
-				Path:      artifact.Path,
-				Overwrite: artifact.Overwrite,
-			},
-		}
-		steps = append(steps, step)
+func New(params *Params) Client {
+	cli := params.HTTPClient
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	maxTries := params.MaxTries
+	if maxTries == 0 {
+		maxTries = 5
 	}
-	action := &Action{
-		Runs: Runs{
-			Using: using,
-			Steps: steps,
+	return &client{
+		token:      params.RuntimeToken,
+		resultsURL: strings.TrimSuffix(params.ResultsURL, "/"),
+		cli:        cli,
+		maxTries:   maxTries,
+		newBackOff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
 		},
 	}
-	if fd, err := os.Stat(params.Dir); os.IsNotExist(err) || !fd.IsDir() {
-		if err := os.Mkdir(params.Dir, 0755); err != nil {
-			return err
+}
+
+// parseBackendIDs extracts the workflow run and job backend IDs from the `Actions.Results:<run>:<job>` scope of the runtime token.
+func parseBackendIDs(token string) (string, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("%w: malformed token", errInvalidRuntimeToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", errInvalidRuntimeToken, err)
+	}
+	claims := &struct {
+		Scp string `json:"scp"`
+	}{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return "", "", fmt.Errorf("%w: %w", errInvalidRuntimeToken, err)
+	}
+	for _, scope := range strings.Fields(claims.Scp) {
+		ids := strings.Split(scope, ":")
+		if len(ids) == 3 && ids[0] == "Actions.Results" {
+			return ids[1], ids[2], nil
 		}
 	}
-	file, err := os.Create(filepath.Join(params.Dir, actionFile))
+	return "", "", fmt.Errorf("%w: backend ids are not found", errInvalidRuntimeToken)
+}
+
+type createArtifactRequest struct {
+	WorkflowRunBackendID    string `json:"workflow_run_backend_id"`
+	WorkflowJobRunBackendID string `json:"workflow_job_run_backend_id"`
+	Name                    string `json:"name"`
+	Version                 int    `json:"version"`
+}
+
+type createArtifactResponse struct {
+	OK              bool   `json:"ok"`
+	SignedUploadURL string `json:"signed_upload_url"`
+}
+
+type finalizeArtifactRequest struct {
+	WorkflowRunBackendID    string `json:"workflow_run_backend_id"`
+	WorkflowJobRunBackendID string `json:"workflow_job_run_backend_id"`
+	Name                    string `json:"name"`
+	Size                    string `json:"size"`
+	Hash                    string `json:"hash"`
+}
+
+type finalizeArtifactResponse struct {
+	OK         bool   `json:"ok"`
+	ArtifactID string `json:"artifact_id"`
+}
+
+func (c *client) Upload(ctx context.Context, name, path string) (*Artifact, error) {
+	runBackendID, jobRunBackendID, err := parseBackendIDs(c.token)
+	if err != nil {
+		return nil, err
+	}
+	if c.resultsURL == "" {
+		return nil, errEmptyResultsURL
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &createArtifactResponse{}
+	err = c.call(ctx, "CreateArtifact", &createArtifactRequest{
+		WorkflowRunBackendID:    runBackendID,
+		WorkflowJobRunBackendID: jobRunBackendID,
+		Name:                    name,
+		Version:                 artifactVersion,
+	}, created)
+	if err != nil {
+		return nil, err
+	}
+	if !created.OK {
+		return nil, fmt.Errorf("%w: failed to create artifact: %s", errUploadFailed, name)
+	}
+
+	if err := c.uploadBlob(ctx, created.SignedUploadURL, data); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	finalized := &finalizeArtifactResponse{}
+	err = c.call(ctx, "FinalizeArtifact", &finalizeArtifactRequest{
+		WorkflowRunBackendID:    runBackendID,
+		WorkflowJobRunBackendID: jobRunBackendID,
+		Name:                    name,
+		Size:                    strconv.Itoa(len(data)),
+		Hash:                    "sha256:" + hex.EncodeToString(sum[:]),
+	}, finalized)
+	if err != nil {
+		return nil, err
+	}
+	if !finalized.OK {
+		return nil, fmt.Errorf("%w: failed to finalize artifact: %s", errUploadFailed, name)
+	}
+	id, err := strconv.ParseInt(finalized.ArtifactID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid artifact id: %s", errUploadFailed, finalized.ArtifactID)
+	}
+	return &Artifact{
+		ID:   id,
+		Name: name,
+		Size: int64(len(data)),
+	}, nil
+}
+
+func (c *client) call(ctx context.Context, method string, in, out any) error {
+	body, err := json.Marshal(in)
 	if err != nil {
 		return err
 	}
+	operation := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.resultsURL+artifactService+method, bytes.NewReader(body))
+		if err != nil {
+			return nil, backoff.Permanent(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Content-Type", "application/json")
+		return c.do(req)
+	}
+	resp, err := c.retry(ctx, operation)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
 	defer func() {
-		_ = file.Close()
+		_ = resp.Body.Close()
 	}()
-	encoder := yaml.NewEncoder(file)
-	encoder.SetIndent(2)
-	if err := encoder.Encode(action); err != nil {
-		return err
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *client) uploadBlob(ctx context.Context, url string, data []byte) error {
+	operation := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+		if err != nil {
+			return nil, backoff.Permanent(err)
+		}
+		req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+		req.Header.Set("Content-Type", "application/zip")
+		return c.do(req)
+	}
+	resp, err := c.retry(ctx, operation)
+	if err != nil {
+		return fmt.Errorf("upload blob: %w", err)
+	}
+	return resp.Body.Close()
+}
+
+// do sends the request, and marks the error as permanent unless the request may succeed on retry.
+func (c *client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
 	}
-	return encoder.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	_ = resp.Body.Close()
+	err = fmt.Errorf("%w: %s %s", errUnexpectedStatus, resp.Status, strings.TrimSpace(string(msg)))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, err
+	}
+	return nil, backoff.Permanent(err)
+}
+
+func (c *client) retry(ctx context.Context, operation backoff.Operation[*http.Response]) (*http.Response, error) {
+	return backoff.Retry(ctx, operation,
+		backoff.WithBackOff(c.newBackOff()),
+		backoff.WithMaxTries(c.maxTries),
+	)
 }
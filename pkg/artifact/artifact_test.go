@@ -1,50 +1,200 @@
 package artifact
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/cenkalti/backoff/v5"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestUploadArtifact(t *testing.T) {
+func genRuntimeToken(scp string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"scp":"` + scp + `"}`))
+	return header + "." + payload + ".signature"
+}
+
+type fakeResults struct {
+	server        *httptest.Server
+	failures      atomic.Int32
+	blobFailures  atomic.Int32
+	createStatus  int
+	blob          []byte
+	finalizeInput *finalizeArtifactRequest
+}
+
+func newFakeResults(t *testing.T) *fakeResults {
+	f := &fakeResults{createStatus: http.StatusOK}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+artifactService+"CreateArtifact", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer "+genRuntimeToken("Actions.Results:run-id:job-id"), r.Header.Get("Authorization"))
+		if f.failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if f.createStatus != http.StatusOK {
+			w.WriteHeader(f.createStatus)
+			_, _ = io.WriteString(w, `{"code":"already_exists","msg":"artifact already exists"}`)
+			return
+		}
+		req := &createArtifactRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		assert.Equal(t, &createArtifactRequest{
+			WorkflowRunBackendID:    "run-id",
+			WorkflowJobRunBackendID: "job-id",
+			Name:                    "mu_test_default_1",
+			Version:                 4,
+		}, req)
+		_ = json.NewEncoder(w).Encode(&createArtifactResponse{
+			OK:              true,
+			SignedUploadURL: f.server.URL + "/blob/mu_test_default_1?sig=xxx",
+		})
+	})
+	mux.HandleFunc("PUT /blob/mu_test_default_1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "BlockBlob", r.Header.Get("X-Ms-Blob-Type"))
+		if f.blobFailures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		f.blob, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("POST "+artifactService+"FinalizeArtifact", func(w http.ResponseWriter, r *http.Request) {
+		f.finalizeInput = &finalizeArtifactRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(f.finalizeInput))
+		_ = json.NewEncoder(w).Encode(&finalizeArtifactResponse{
+			OK:         true,
+			ArtifactID: "123",
+		})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func TestClient_Upload(t *testing.T) {
 	t.Parallel()
-	params := &UploadArtifactParams{
-		Version: "v4",
-		Dir:     "./testdata",
-		Artifacts: []*Artifact{
-			{
-				Name:      "test-01",
-				Path:      "test-01.tfplan",
-				Overwrite: true,
-			},
-			{
-				Name:      "test-02",
-				Path:      "test-02.tfplan",
-				Overwrite: true,
-			},
+	tests := []struct {
+		name         string
+		token        string
+		failures     int32
+		blobFailures int32
+		createStatus int
+		expect       *Artifact
+		expectErr    error
+	}{
+		{
+			name:   "success",
+			expect: &Artifact{ID: 123, Name: "mu_test_default_1", Size: 8},
 		},
+		{
+			name:         "retry",
+			failures:     2,
+			blobFailures: 2,
+			expect:       &Artifact{ID: 123, Name: "mu_test_default_1", Size: 8},
+		},
+		{
+			name:      "too many failures",
+			failures:  3,
+			expectErr: errUnexpectedStatus,
+		},
+		{
+			name:      "invalid runtime token",
+			token:     "token",
+			expectErr: errInvalidRuntimeToken,
+		},
+		{
+			name:         "conflict",
+			createStatus: http.StatusConflict,
+			expectErr:    errUnexpectedStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := newFakeResults(t)
+			f.failures.Store(tt.failures)
+			f.blobFailures.Store(tt.blobFailures)
+			if tt.createStatus != 0 {
+				f.createStatus = tt.createStatus
+			}
+			path := filepath.Join(t.TempDir(), "plan.zip")
+			require.NoError(t, os.WriteFile(path, []byte("zip data"), 0600))
+
+			token := tt.token
+			if token == "" {
+				token = genRuntimeToken("Actions.Results:run-id:job-id")
+			}
+			cli := New(&Params{
+				RuntimeToken: token,
+				ResultsURL:   f.server.URL + "/",
+				MaxTries:     3,
+			})
+			cli.(*client).newBackOff = func() backoff.BackOff {
+				return &backoff.ZeroBackOff{}
+			}
+
+			artifact, err := cli.Upload(context.Background(), "mu_test_default_1", path)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, artifact)
+			assert.Equal(t, "zip data", string(f.blob))
+			assert.Equal(t, "8", f.finalizeInput.Size)
+			assert.True(t, strings.HasPrefix(f.finalizeInput.Hash, "sha256:"))
+		})
+	}
+}
+
+func TestParseBackendIDs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		token     string
+		expectRun string
+		expectJob string
+		expectErr error
+	}{
+		{
+			name:      "success",
+			token:     genRuntimeToken("Actions.ExampleScope Actions.Results:run-id:job-id"),
+			expectRun: "run-id",
+			expectJob: "job-id",
+		},
+		{
+			name:      "no results scope",
+			token:     genRuntimeToken("Actions.ExampleScope"),
+			expectErr: errInvalidRuntimeToken,
+		},
+		{
+			name:      "malformed",
+			token:     "token",
+			expectErr: errInvalidRuntimeToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			run, job, err := parseBackendIDs(tt.token)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectRun, run)
+			assert.Equal(t, tt.expectJob, job)
+		})
 	}
-	err := UploadArtifacts(params)
-	require.NoError(t, err)
-
-	file, err := os.ReadFile("./testdata/action.yaml")
-	require.NoError(t, err)
-	expect := `runs:
-  using: composite
-  steps:
-    - name: test-01
-      uses: actions/upload-artifact@v4
-      with:
-        name: test-01
-        path: test-01.tfplan
-        overwrite: true
-    - name: test-02
-      uses: actions/upload-artifact@v4
-      with:
-        name: test-02
-        path: test-02.tfplan
-        overwrite: true
-`
-	require.Equal(t, expect, string(file))
 }
@@ -0,0 +1,95 @@
+// Package audit writes the audit trail of mu, which is one JSON record per project of every command, to the sinks.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// The results of the command.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	// ResultDenied is the result of the command which the user is not allowed to run.
+	ResultDenied = "denied"
+)
+
+type Record struct {
+	Time        time.Time `json:"time"`
+	Actor       string    `json:"actor"`
+	PullRequest int       `json:"pull_request,omitempty"`
+	SHA         string    `json:"sha,omitempty"`
+	Project     string    `json:"project,omitempty"`
+	Workspace   string    `json:"workspace,omitempty"`
+	Command     string    `json:"command"`
+	Args        []string  `json:"args,omitempty"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+	// Changes are the resource counts of the plan or the apply. It is nil if the output has no counts.
+	Changes    *Changes `json:"changes,omitempty"`
+	DurationMS int64    `json:"duration_ms"`
+	RunURL     string   `json:"run_url,omitempty"`
+}
+
+type Changes struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
+var (
+	planCountsRegexp  = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy`)
+	applyCountsRegexp = regexp.MustCompile(`Resources: (\d+) added, (\d+) changed, (\d+) destroyed`)
+)
+
+// ParseChanges returns the resource counts in the result of `terraform plan` or `terraform apply`.
+func ParseChanges(result string) *Changes {
+	match := planCountsRegexp.FindStringSubmatch(result)
+	if match == nil {
+		match = applyCountsRegexp.FindStringSubmatch(result)
+	}
+	if match == nil {
+		return nil
+	}
+	add, _ := strconv.Atoi(match[1])
+	change, _ := strconv.Atoi(match[2])
+	destroy, _ := strconv.Atoi(match[3])
+	return &Changes{
+		Add:     add,
+		Change:  change,
+		Destroy: destroy,
+	}
+}
+
+// Sink appends the records to the audit trail.
+type Sink interface {
+	Write(ctx context.Context, records []*Record) error
+}
+
+// Write writes the records to every sink, and returns the joined errors of the sinks which failed.
+func Write(ctx context.Context, sinks []Sink, records []*Record) error {
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Write(ctx, records); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// marshalLines encodes the records in JSON Lines.
+func marshalLines(records []*Record) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
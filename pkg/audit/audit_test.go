@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChanges(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		result string
+		expect *Changes
+	}{
+		"plan": {
+			result: "Plan: 1 to add, 2 to change, 3 to destroy.",
+			expect: &Changes{Add: 1, Change: 2, Destroy: 3},
+		},
+		"apply": {
+			result: "Apply complete! Resources: 4 added, 0 changed, 1 destroyed.",
+			expect: &Changes{Add: 4, Change: 0, Destroy: 1},
+		},
+		"no changes": {
+			result: "No changes. Your infrastructure matches the configuration.",
+			expect: nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expect, ParseChanges(tt.result))
+		})
+	}
+}
+
+type sinkFunc func(ctx context.Context, records []*Record) error
+
+func (f sinkFunc) Write(ctx context.Context, records []*Record) error {
+	return f(ctx, records)
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	var written [][]*Record
+	ok := sinkFunc(func(_ context.Context, records []*Record) error {
+		written = append(written, records)
+		return nil
+	})
+	ng := sinkFunc(func(_ context.Context, _ []*Record) error {
+		return assert.AnError
+	})
+	records := []*Record{{Command: "plan", Result: ResultSuccess}}
+
+	err := Write(context.Background(), []Sink{ng, ok}, records)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, [][]*Record{records}, written)
+	assert.NoError(t, Write(context.Background(), nil, records))
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+const branchAttempts = 3
+
+type branchSink struct {
+	vcs    vcs.VCS
+	branch string
+	now    func() time.Time
+}
+
+// NewBranch returns the sink which commits the records in a new JSON Lines file to the branch,
+// e.g. audit/2006/01/02/150405.000000000.jsonl. The branch has to exist beforehand.
+// Every write adds a new file, so that the history of the branch is append-only.
+func NewBranch(v vcs.VCS, branch string) Sink {
+	return &branchSink{
+		vcs:    v,
+		branch: branch,
+		now:    time.Now,
+	}
+}
+
+func (b *branchSink) Write(ctx context.Context, records []*Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	data, err := marshalLines(records)
+	if err != nil {
+		return err
+	}
+	path := "audit/" + b.now().UTC().Format("2006/01/02/150405.000000000") + ".jsonl"
+	msg := fmt.Sprintf("mu audit: %s", records[0].Command)
+	if pr := records[0].PullRequest; pr > 0 {
+		msg += fmt.Sprintf(" #%d", pr)
+	}
+	params := &vcs.CommitFilesParams{
+		Branch:  b.branch,
+		Message: msg,
+		Files:   map[string]string{path: string(data)},
+	}
+	// The branch is updated without force, so the commit fails when another run updates the branch at the same time.
+	for attempt := 1; ; attempt++ {
+		_, err := b.vcs.CommitFiles(ctx, params)
+		if err == nil || attempt == branchAttempts {
+			return err
+		}
+	}
+}
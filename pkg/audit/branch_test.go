@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
+	vcsMock "github.com/yu-icchi/mu/pkg/vcs/mock"
+)
+
+func TestBranchSink_Write(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	v := vcsMock.NewMockVCS(ctrl)
+	ctx := context.Background()
+	expect := &vcs.CommitFilesParams{
+		Branch:  "audit",
+		Message: "mu audit: apply #1",
+		Files: map[string]string{
+			"audit/2026/10/18/123456.000000000.jsonl": `{"time":"0001-01-01T00:00:00Z","actor":"alice","pull_request":1,"command":"apply","result":"success","duration_ms":0}` + "\n",
+		},
+	}
+	gomock.InOrder(
+		v.EXPECT().CommitFiles(ctx, expect).Return("", assert.AnError),
+		v.EXPECT().CommitFiles(ctx, expect).Return("sha", nil),
+	)
+	sink := NewBranch(v, "audit").(*branchSink)
+	sink.now = func() time.Time {
+		return time.Date(2026, 10, 18, 12, 34, 56, 0, time.UTC)
+	}
+	require.NoError(t, sink.Write(ctx, []*Record{{Actor: "alice", PullRequest: 1, Command: "apply", Result: ResultSuccess}}))
+}
+
+func TestBranchSink_Write_Failed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	v := vcsMock.NewMockVCS(ctrl)
+	ctx := context.Background()
+	v.EXPECT().CommitFiles(ctx, gomock.Any()).Return("", assert.AnError).Times(branchAttempts)
+	err := NewBranch(v, "audit").Write(ctx, []*Record{{Command: "plan"}})
+	require.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/yu-icchi/mu/pkg/artifact"
+)
+
+type fileSink struct {
+	path string
+}
+
+// NewFile returns the sink which appends the records to the JSON Lines file at path.
+func NewFile(path string) Sink {
+	return &fileSink{path: path}
+}
+
+func (f *fileSink) Write(_ context.Context, records []*Record) error {
+	data, err := marshalLines(records)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type artifactSink struct {
+	client artifact.Client
+	name   string
+	dir    string
+}
+
+// NewArtifact returns the sink which uploads the records in a JSON Lines file as the artifact named name.
+// The artifacts cannot be overwritten, so name has to be unique, e.g. contain the run ID and the run attempt.
+func NewArtifact(client artifact.Client, name, dir string) Sink {
+	return &artifactSink{
+		client: client,
+		name:   name,
+		dir:    dir,
+	}
+}
+
+func (a *artifactSink) Write(ctx context.Context, records []*Record) error {
+	path := filepath.Join(a.dir, a.name+".jsonl")
+	if err := NewFile(path).Write(ctx, records); err != nil {
+		return err
+	}
+	_, err := a.client.Upload(ctx, a.name, path)
+	return err
+}
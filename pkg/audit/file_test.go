@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/artifact"
+	artifactMock "github.com/yu-icchi/mu/pkg/artifact/mock"
+)
+
+func TestFileSink_Write(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "audit", "mu.jsonl")
+	sink := NewFile(path)
+	ctx := context.Background()
+	require.NoError(t, sink.Write(ctx, []*Record{{Command: "plan", Result: ResultSuccess}}))
+	require.NoError(t, sink.Write(ctx, []*Record{{Command: "apply", Result: ResultFailure, Error: "apply failed"}}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	expect := `{"time":"0001-01-01T00:00:00Z","actor":"","command":"plan","result":"success","duration_ms":0}` + "\n" +
+		`{"time":"0001-01-01T00:00:00Z","actor":"","command":"apply","result":"failure","error":"apply failed","duration_ms":0}` + "\n"
+	assert.Equal(t, expect, string(data))
+}
+
+func TestArtifactSink_Write(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	client := artifactMock.NewMockClient(ctrl)
+	dir := t.TempDir()
+	ctx := context.Background()
+	client.EXPECT().Upload(ctx, "mu-audit-1-1", filepath.Join(dir, "mu-audit-1-1.jsonl")).
+		DoAndReturn(func(_ context.Context, name, path string) (*artifact.Artifact, error) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"command":"plan"`)
+			return &artifact.Artifact{ID: 1, Name: name}, nil
+		})
+	sink := NewArtifact(client, "mu-audit-1-1", dir)
+	require.NoError(t, sink.Write(ctx, []*Record{{Command: "plan", Result: ResultSuccess}}))
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+type httpSink struct {
+	url   string
+	token string
+	cli   *http.Client
+}
+
+// NewHTTP returns the sink which posts the records in JSON Lines to url,
+// with the token as the bearer token if it is not empty.
+func NewHTTP(url, token string, cli *http.Client) Sink {
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	return &httpSink{
+		url:   url,
+		token: token,
+		cli:   cli,
+	}
+}
+
+func (h *httpSink) Write(ctx context.Context, records []*Record) error {
+	data, err := marshalLines(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	resp, err := h.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %d %s", errUnexpectedStatus, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
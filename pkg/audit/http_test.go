@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSink_Write(t *testing.T) {
+	t.Parallel()
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+	records := []*Record{{Command: "plan"}, {Command: "apply"}}
+
+	require.NoError(t, NewHTTP(srv.URL, "token", nil).Write(ctx, records))
+	assert.Contains(t, body, `"command":"plan"`)
+	assert.Contains(t, body, `"command":"apply"`)
+
+	err := NewHTTP(srv.URL, "", nil).Write(ctx, records)
+	require.ErrorIs(t, err, errUnexpectedStatus)
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StepSummary is the job summary of GitHub Actions, which is *action.Action.
+type StepSummary interface {
+	AddStepSummary(msg string) error
+}
+
+type summarySink struct {
+	summary StepSummary
+}
+
+// NewSummary returns the sink which writes the records to the job summary as a table.
+func NewSummary(summary StepSummary) Sink {
+	return &summarySink{summary: summary}
+}
+
+func (s *summarySink) Write(_ context.Context, records []*Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	msg := new(strings.Builder)
+	msg.WriteString("### mu audit\n\n")
+	msg.WriteString("| Actor | PR | Project | Workspace | Command | Result | Changes | Duration |\n")
+	msg.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, r := range records {
+		var pr, changes string
+		if r.PullRequest > 0 {
+			pr = fmt.Sprintf("#%d", r.PullRequest)
+		}
+		if r.Changes != nil {
+			changes = fmt.Sprintf("+%d ~%d -%d", r.Changes.Add, r.Changes.Change, r.Changes.Destroy)
+		}
+		command := strings.TrimSpace("mu " + r.Command + " " + strings.Join(r.Args, " "))
+		msg.WriteString(fmt.Sprintf("| %s | %s | %s | %s | `%s` | %s | %s | %.1fs |\n",
+			r.Actor, pr, r.Project, r.Workspace, strings.ReplaceAll(command, "|", `\|`), r.Result, changes,
+			float64(r.DurationMS)/1000))
+	}
+	return s.summary.AddStepSummary(msg.String())
+}
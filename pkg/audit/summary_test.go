@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stepSummary struct {
+	msg string
+}
+
+func (s *stepSummary) AddStepSummary(msg string) error {
+	s.msg += msg
+	return nil
+}
+
+func TestSummarySink_Write(t *testing.T) {
+	t.Parallel()
+	summary := &stepSummary{}
+	sink := NewSummary(summary)
+	require.NoError(t, sink.Write(context.Background(), []*Record{
+		{
+			Actor:       "alice",
+			PullRequest: 1,
+			Project:     "prod",
+			Workspace:   "default",
+			Command:     "apply",
+			Args:        []string{"-p", "prod"},
+			Result:      ResultSuccess,
+			Changes:     &Changes{Add: 1, Change: 2, Destroy: 3},
+			DurationMS:  1500,
+		},
+	}))
+	assert.Contains(t, summary.msg, "| alice | #1 | prod | default | `mu apply -p prod` | success | +1 ~2 -3 | 1.5s |\n")
+
+	summary.msg = ""
+	require.NoError(t, sink.Write(context.Background(), nil))
+	assert.Empty(t, summary.msg)
+}
@@ -11,12 +11,15 @@ import (
 type Type string
 
 const (
-	PlanType   Type = "plan"
-	ApplyType  Type = "apply"
-	UnlockType Type = "unlock"
-	HelpType   Type = "help"
-	ImportType Type = "import"
-	StateType  Type = "state"
+	PlanType     Type = "plan"
+	ApplyType    Type = "apply"
+	UnlockType   Type = "unlock"
+	HelpType     Type = "help"
+	ImportType   Type = "import"
+	StateType    Type = "state"
+	OutputType   Type = "output"
+	FmtType      Type = "fmt"
+	ValidateType Type = "validate"
 )
 
 const (
@@ -105,6 +108,18 @@ func Parse(msg string) (Command, error) {
 			return nil, fmt.Errorf("%w: %w", ErrInvalidCommand, err)
 		}
 		return cmd, nil
+	case OutputType:
+		cmd, err := parseOutputCommand(args)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCommand, err)
+		}
+		return cmd, nil
+	case FmtType:
+		cmd, err := parseFmtCommand(args)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCommand, err)
+		}
+		return cmd, nil
 	default:
 		return nil, ErrInvalidCommand
 	}
@@ -79,6 +79,15 @@ func TestParse(t *testing.T) {
 				DryRun: true,
 			},
 		},
+		{
+			name: "output",
+			msg:  "mu output --project test --workspace dev endpoint",
+			expect: &Output{
+				Project:   "test",
+				Workspace: "dev",
+				Name:      "endpoint",
+			},
+		},
 		{
 			name:      "invalid command",
 			msg:       "mu test",
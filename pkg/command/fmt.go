@@ -0,0 +1,33 @@
+package command
+
+import (
+	"flag"
+	"io"
+)
+
+type Fmt struct {
+	Project   string
+	Workspace string
+	Fix       bool
+}
+
+var _ Command = (*Fmt)(nil)
+
+func (f *Fmt) Type() Type {
+	return FmtType
+}
+
+func parseFmtCommand(args []string) (*Fmt, error) {
+	fmtCmd := &Fmt{}
+	flagSet := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	flagSet.StringVar(&fmtCmd.Project, "p", "", "")
+	flagSet.StringVar(&fmtCmd.Project, "project", "", "")
+	flagSet.StringVar(&fmtCmd.Workspace, "w", "", "")
+	flagSet.StringVar(&fmtCmd.Workspace, "workspace", "", "")
+	flagSet.BoolVar(&fmtCmd.Fix, "fix", false, "")
+	if err := flagSet.Parse(args[2:]); err != nil {
+		return nil, err
+	}
+	return fmtCmd, nil
+}
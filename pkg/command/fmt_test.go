@@ -0,0 +1,53 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFmt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		command   string
+		expect    *Fmt
+		expectErr error
+	}{
+		{
+			command: "mu fmt",
+			expect:  &Fmt{},
+		},
+		{
+			command: "mu fmt -p test",
+			expect: &Fmt{
+				Project: "test",
+			},
+		},
+		{
+			command: "mu fmt --project test --workspace dev --fix",
+			expect: &Fmt{
+				Project:   "test",
+				Workspace: "dev",
+				Fix:       true,
+			},
+		},
+		{
+			command:   "mu fmt --unknown",
+			expectErr: ErrInvalidCommand,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+			cmd, err := Parse(tt.command)
+			if tt.expectErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expect, cmd)
+			} else {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			}
+		})
+	}
+}
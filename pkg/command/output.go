@@ -0,0 +1,33 @@
+package command
+
+import (
+	"flag"
+	"io"
+)
+
+type Output struct {
+	Project   string
+	Workspace string
+	Name      string
+}
+
+var _ Command = (*Output)(nil)
+
+func (o *Output) Type() Type {
+	return OutputType
+}
+
+func parseOutputCommand(args []string) (*Output, error) {
+	output := &Output{}
+	flagSet := flag.NewFlagSet("output", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	flagSet.StringVar(&output.Project, "p", "", "")
+	flagSet.StringVar(&output.Project, "project", "", "")
+	flagSet.StringVar(&output.Workspace, "w", "", "")
+	flagSet.StringVar(&output.Workspace, "workspace", "", "")
+	if err := flagSet.Parse(args[2:]); err != nil {
+		return nil, err
+	}
+	output.Name = flagSet.Arg(0)
+	return output, nil
+}
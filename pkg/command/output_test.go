@@ -0,0 +1,53 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		command   string
+		expect    *Output
+		expectErr error
+	}{
+		{
+			command: "mu output",
+			expect:  &Output{},
+		},
+		{
+			command: "mu output -p test",
+			expect: &Output{
+				Project: "test",
+			},
+		},
+		{
+			command: "mu output --project test --workspace dev endpoint",
+			expect: &Output{
+				Project:   "test",
+				Workspace: "dev",
+				Name:      "endpoint",
+			},
+		},
+		{
+			command:   "mu output --unknown",
+			expectErr: ErrInvalidCommand,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+			cmd, err := Parse(tt.command)
+			if tt.expectErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expect, cmd)
+			} else {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectErr)
+			}
+		})
+	}
+}
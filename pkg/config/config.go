@@ -3,24 +3,46 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/moby/patternmatcher"
 	"gopkg.in/yaml.v3"
+
+	"github.com/yu-icchi/mu/pkg/terragrunt"
 )
 
 var ErrInvalidConfig = errors.New("invalid config")
 
 type Config struct {
-	Version                 int        `yaml:"version" validate:"oneof=1"`
-	Projects                []*Project `yaml:"projects" validate:"required,dive,required"`
+	Version   int        `yaml:"version" validate:"oneof=1"`
+	Projects  []*Project `yaml:"projects" validate:"required,dive,required"`
+	Automerge *Automerge `yaml:"automerge"`
+	// Workflows are the steps of plan and apply referenced by the projects, keyed by the name.
+	Workflows  map[string]*Workflow `yaml:"workflows" validate:"dive,required"`
+	Terragrunt *Terragrunt          `yaml:"terragrunt"`
+	// Defaults are merged into every project, and Templates into the projects which extend them, before the projects are decoded.
+	// They are kept for reference, since Load has already merged them.
+	Defaults  *Project            `yaml:"defaults" validate:"-"`
+	Templates map[string]*Project `yaml:"templates" validate:"-"`
+	// Include are the globs of the config fragments whose projects are added to Projects, relative to the base dir.
+	Include []string `yaml:"include"`
+	// defaultTerraformVersion is the version of the projects without terraform.version.
 	defaultTerraformVersion string
+	// baseDir is the directory which the dirs of the projects are relative to.
+	baseDir string
+	// source is the config file which the config is loaded from, to report the position of the problems.
+	source *source
 }
 
+// GetProject returns the first project of the name, which is the first workspace of the project with multiple workspaces.
 func (c *Config) GetProject(name string) *Project {
 	if c == nil {
 		return nil
@@ -33,13 +55,109 @@ func (c *Config) GetProject(name string) *Project {
 	return nil
 }
 
+// GetProjects returns the projects of the name, which are every workspace of the project unless workspace is given.
+func (c *Config) GetProjects(name, workspace string) Projects {
+	if c == nil {
+		return nil
+	}
+	var projects Projects
+	for _, project := range c.Projects {
+		if project.Name != name {
+			continue
+		}
+		if workspace != "" && project.Workspace != workspace {
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects
+}
+
+// Secrets returns the values of the environment variables referenced by ${secret:NAME}, which are masked in the logs.
+func (c *Config) Secrets() []string {
+	if c == nil || c.source == nil {
+		return nil
+	}
+	return c.source.secrets
+}
+
+// Validate checks the config, and returns Errors with every problem at its position in the config file.
 func (c *Config) Validate() error {
-	validate := validator.New()
-	err := validate.Struct(c)
-	if err != nil {
-		return fmt.Errorf("%w: %w", err, ErrInvalidConfig)
+	var errs Errors
+	if err := validator.New().Struct(c); err != nil {
+		var verrs validator.ValidationErrors
+		if !errors.As(err, &verrs) {
+			return fmt.Errorf("%w: %w", err, ErrInvalidConfig)
+		}
+		errs = append(errs, c.validationErrors(verrs)...)
 	}
-	return nil
+	for _, name := range slices.Sorted(maps.Keys(c.Workflows)) {
+		if err := c.Workflows[name].validate(); err != nil {
+			node := mappingValue(mappingValue(c.source.document(), "workflows"), name)
+			errs = append(errs, c.source.errorf(node, "workflow %q: %s", name, err))
+		}
+	}
+	defined := make(map[string]*Project, len(c.Projects))
+	for _, project := range c.Projects {
+		if project == nil {
+			continue
+		}
+		id := project.ID()
+		if first, ok := defined[id]; ok {
+			msg := fmt.Sprintf("project %q is already defined", id)
+			if pos := c.source.position(c.source.projectNode(first)).String(); pos != "" {
+				msg += " at " + pos
+			}
+			errs = append(errs, c.source.errorf(c.source.projectNode(project), "%s", msg))
+		} else {
+			defined[id] = project
+		}
+		if project.Workflow != "" && c.Workflows[project.Workflow] == nil {
+			errs = append(errs, c.source.errorf(nodeValue(c.source.projectNode(project), "workflow"), "project %q: workflow %q is not found", id, project.Workflow))
+		}
+		if workflow := c.Workflows[project.Workflow]; workflow != nil && len(project.Hooks.Get(HookPreInit)) > 0 && !workflow.runsInit() {
+			node := nodeValue(nodeValue(c.source.projectNode(project), "hooks"), HookPreInit)
+			errs = append(errs, c.source.errorf(node, "project %q: hooks.pre_init is never run since workflow %q has no init step", id, project.Workflow))
+		}
+		if project.Plan == nil {
+			continue
+		}
+		paths := mappingValue(mappingValue(c.source.projectNode(project), "plan"), "paths")
+		for i, path := range project.Plan.Paths {
+			if _, err := patternmatcher.New([]string{planPattern("", path)}); err != nil {
+				node := paths
+				if paths != nil && paths.Kind == yaml.SequenceNode && i < len(paths.Content) {
+					node = paths.Content[i]
+				}
+				errs = append(errs, c.source.errorf(node, "project %q: plan.paths[%d] %q is invalid: %s", id, i, path, err))
+			}
+		}
+	}
+	errs.sort()
+	return errs.err()
+}
+
+// ValidateDirs checks that the dir of every project exists in the base dir.
+// It is not a part of Validate, which only needs the config file.
+func (c *Config) ValidateDirs() error {
+	baseDir := c.baseDir
+	if baseDir == "" {
+		baseDir = "."
+	}
+	var errs Errors
+	for _, project := range c.Projects {
+		if project == nil {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(baseDir, project.Dir))
+		switch {
+		case err != nil:
+			errs = append(errs, c.source.errorf(nodeValue(c.source.projectNode(project), "dir"), "project %q: dir %q does not exist", project.ID(), project.Dir))
+		case !info.IsDir():
+			errs = append(errs, c.source.errorf(nodeValue(c.source.projectNode(project), "dir"), "project %q: dir %q is not a directory", project.ID(), project.Dir))
+		}
+	}
+	return errs.err()
 }
 
 func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
@@ -48,29 +166,209 @@ func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 	for _, project := range c.Projects {
-		project.Dir = path.Clean(project.Dir)
-		if project.Terraform == nil {
-			project.Terraform = &Terraform{}
-		}
-		if project.Terraform.Version == "" {
-			if c.defaultTerraformVersion != "" {
-				project.Terraform.Version = c.defaultTerraformVersion
-			} else {
-				project.Terraform.Version = "latest"
+		c.setProjectDefaults(project)
+	}
+	return nil
+}
+
+func (c *Config) setProjectDefaults(project *Project) {
+	project.Dir = path.Clean(project.Dir)
+	if project.Terraform == nil {
+		project.Terraform = &Terraform{}
+	}
+	if project.Terraform.Version == "" {
+		if c.defaultTerraformVersion != "" {
+			project.Terraform.Version = c.defaultTerraformVersion
+		} else {
+			project.Terraform.Version = "latest"
+		}
+	}
+	project.workflow = c.Workflows[project.Workflow]
+}
+
+// discoverTerragruntProjects adds the projects of the Terragrunt units found in the directories of Terragrunt.Discover.
+// The units in the dir of a project in the config are skipped.
+func (c *Config) discoverTerragruntProjects(baseDir string) error {
+	if c.Terragrunt == nil {
+		return nil
+	}
+	dirs := make(map[string]bool, len(c.Projects))
+	for _, project := range c.Projects {
+		dirs[project.Dir] = true
+	}
+	discoverNodes := mappingValue(mappingValue(c.source.document(), "terragrunt"), "discover")
+	for i, discover := range c.Terragrunt.Discover {
+		var node *yaml.Node
+		if discoverNodes != nil && discoverNodes.Kind == yaml.SequenceNode && i < len(discoverNodes.Content) {
+			node = discoverNodes.Content[i]
+		}
+		units, err := terragrunt.Discover(filepath.Join(baseDir, discover.Dir))
+		if err != nil {
+			return fmt.Errorf("discover terragrunt units in %s: %w", discover.Dir, err)
+		}
+		for _, unit := range units {
+			dir := path.Join(discover.Dir, unit)
+			if dirs[dir] {
+				continue
 			}
+			dirs[dir] = true
+			project := discover.newProject(dir)
+			c.setProjectDefaults(project)
+			c.source.setProjectNode(project, node)
+			c.Projects = append(c.Projects, project)
 		}
 	}
 	return nil
 }
 
+// orderTerragruntProjects moves the projects of the Terragrunt units after the projects of the units they depend on,
+// so that the projects are planned and applied in the order of the dependencies.
+// The order of the other projects is kept.
+func (c *Config) orderTerragruntProjects(baseDir string) error {
+	byDir := make(map[string][]*Project, len(c.Projects))
+	for _, project := range c.Projects {
+		byDir[project.Dir] = append(byDir[project.Dir], project)
+	}
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := make(map[*Project]int, len(c.Projects))
+	ordered := make([]*Project, 0, len(c.Projects))
+	var visit func(project *Project, path []string) error
+	visit = func(project *Project, path []string) error {
+		path = append(path, project.Dir)
+		switch states[project] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s: %w", strings.Join(path, " -> "), ErrInvalidConfig)
+		}
+		states[project] = visiting
+		if project.Terraform.GetWrapper() == WrapperTerragrunt {
+			deps, err := terragrunt.Dependencies(filepath.Join(baseDir, project.Dir))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			for _, dep := range deps {
+				rel, err := filepath.Rel(baseDir, filepath.FromSlash(dep))
+				if err != nil {
+					return err
+				}
+				for _, depProject := range byDir[filepath.ToSlash(rel)] {
+					if err := visit(depProject, path); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		states[project] = visited
+		ordered = append(ordered, project)
+		return nil
+	}
+	for _, project := range c.Projects {
+		if err := visit(project, nil); err != nil {
+			return err
+		}
+	}
+	c.Projects = ordered
+	return nil
+}
+
 type Project struct {
-	Name           string     `yaml:"name" validate:"required"`
-	Dir            string     `yaml:"dir" validate:"required"`
-	Workspace      string     `yaml:"workspace"`
+	Name string `yaml:"name" validate:"required"`
+	Dir  string `yaml:"dir" validate:"required"`
+	// Extends are the names of Config.Templates merged into the project.
+	Extends   Extends `yaml:"extends"`
+	Workspace string  `yaml:"workspace"`
+	// Workspaces are the workspaces which the project is deployed to. Load expands the project into a project per workspace.
+	Workspaces     Workspaces `yaml:"workspaces"`
 	Terraform      *Terraform `yaml:"terraform"`
 	Plan           *Plan      `yaml:"plan" validate:"required"`
 	Apply          *Apply     `yaml:"apply"`
+	Drift          *Drift     `yaml:"drift"`
 	LockLabelColor string     `yaml:"lock_label_color"`
+	// Permissions restricts who can run the commands on the project, keyed by the command.
+	// The commands without the key can be run by anyone who can comment.
+	Permissions Permissions `yaml:"permissions" validate:"omitempty,dive,keys,oneof=plan apply unlock force_unlock import state output fmt,endkeys,required"`
+	Hooks       *Hooks      `yaml:"hooks"`
+	// Workflow is the name of the workflow in Config.Workflows. The default steps are run if it is empty.
+	Workflow string `yaml:"workflow"`
+	workflow *Workflow
+	// matrix reports whether the project is one of the workspaces of Workspaces.
+	matrix bool
+}
+
+// ID identifies the project in the locks and the commit statuses.
+// It is the name with the workspace for the project expanded from Workspaces, and the name otherwise.
+func (p *Project) ID() string {
+	if !p.matrix {
+		return p.Name
+	}
+	return p.Name + "/" + p.Workspace
+}
+
+// expand returns a copy of the project per workspace of Workspaces, with the overrides of the workspace.
+func (p *Project) expand() ([]*Project, error) {
+	if len(p.Workspaces) == 0 {
+		return []*Project{p}, nil
+	}
+	if p.Workspace != "" {
+		return nil, fmt.Errorf("project %q: workspace and workspaces cannot be used together", p.Name)
+	}
+	projects := make([]*Project, 0, len(p.Workspaces))
+	seen := make(map[string]bool, len(p.Workspaces))
+	for _, workspace := range p.Workspaces {
+		if workspace.Name == "" {
+			return nil, fmt.Errorf("project %q: workspace name is empty", p.Name)
+		}
+		if seen[workspace.Name] {
+			return nil, fmt.Errorf("project %q: workspace %q is duplicated", p.Name, workspace.Name)
+		}
+		seen[workspace.Name] = true
+
+		project := *p
+		project.Workspace = workspace.Name
+		project.Workspaces = nil
+		project.matrix = true
+		tf := *p.Terraform
+		tf.Vars = append(slices.Clip(tf.Vars), workspace.Vars...)
+		tf.VarFiles = append(slices.Clip(tf.VarFiles), workspace.VarFiles...)
+		if len(workspace.BackendConfig) > 0 {
+			tf.BackendConfig = maps.Clone(tf.BackendConfig)
+			if tf.BackendConfig == nil {
+				tf.BackendConfig = make(map[string]string, len(workspace.BackendConfig))
+			}
+			maps.Copy(tf.BackendConfig, workspace.BackendConfig)
+		}
+		project.Terraform = &tf
+		if workspace.Apply != nil {
+			project.Apply = workspace.Apply
+		}
+		projects = append(projects, &project)
+	}
+	return projects, nil
+}
+
+// SetWorkflow sets the workflow referenced by Workflow. Load sets it, so it is only needed for the projects built in code.
+func (p *Project) SetWorkflow(workflow *Workflow) {
+	p.workflow = workflow
+}
+
+// PlanSteps returns the steps of `mu plan`, which are init and plan without the workflow.
+func (p *Project) PlanSteps() []*Step {
+	if p.workflow == nil || len(p.workflow.Plan) == 0 {
+		return []*Step{{Init: &StepArgs{}}, {Plan: &StepArgs{}}}
+	}
+	return p.workflow.Plan
+}
+
+// ApplySteps returns the steps of `mu apply`, which are init and apply without the workflow.
+func (p *Project) ApplySteps() []*Step {
+	if p.workflow == nil || len(p.workflow.Apply) == 0 {
+		return []*Step{{Init: &StepArgs{}}, {Apply: &StepArgs{}}}
+	}
+	return p.workflow.Apply
 }
 
 func (p *Project) HasModifiedFiles(files []string) bool {
@@ -84,6 +382,307 @@ func (p *Project) HasModifiedFiles(files []string) bool {
 
 type Projects []*Project
 
+// Workspaces are the workspaces of a project, which are written as a list of the names,
+// or a map of the name to the overrides of the workspace.
+type Workspaces []*Workspace
+
+// UnmarshalYAML decodes the node instead of a map to keep the order of the workspaces.
+func (w *Workspaces) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := value.Decode(&names); err != nil {
+			return err
+		}
+		*w = make(Workspaces, 0, len(names))
+		for _, name := range names {
+			*w = append(*w, &Workspace{Name: name})
+		}
+		return nil
+	case yaml.MappingNode:
+		*w = make(Workspaces, 0, len(value.Content)/2)
+		for i := 0; i < len(value.Content); i += 2 {
+			workspace := &Workspace{}
+			if err := value.Content[i+1].Decode(workspace); err != nil {
+				return err
+			}
+			workspace.Name = value.Content[i].Value
+			*w = append(*w, workspace)
+		}
+		return nil
+	default:
+		return fmt.Errorf("line %d: workspaces must be a list or a map", value.Line)
+	}
+}
+
+// Workspace overrides the settings of the project in the workspace.
+// Vars and VarFiles are appended to the ones of the project, BackendConfig is merged into the one of the project,
+// and Apply replaces the one of the project.
+type Workspace struct {
+	Name          string            `yaml:"-"`
+	Vars          []string          `yaml:"vars"`
+	VarFiles      []string          `yaml:"var_files"`
+	BackendConfig map[string]string `yaml:"backend_config"`
+	Apply         *Apply            `yaml:"apply"`
+}
+
+// expandWorkspaces replaces the projects with Workspaces with a project per workspace.
+func (c *Config) expandWorkspaces() error {
+	projects := make([]*Project, 0, len(c.Projects))
+	for _, project := range c.Projects {
+		expanded, err := project.expand()
+		if err != nil {
+			return c.source.errorf(nodeValue(c.source.projectNode(project), "workspaces"), "%s", err)
+		}
+		for _, p := range expanded {
+			c.source.setProjectNode(p, c.source.projectNode(project))
+		}
+		projects = append(projects, expanded...)
+	}
+	c.Projects = projects
+	return nil
+}
+
+// The keys of Permissions other than the command types.
+const (
+	// PermissionForceUnlock is the key of `mu unlock --force-unlock`, which is checked in addition to unlock.
+	PermissionForceUnlock = "force_unlock"
+)
+
+type Permissions map[string]*Permission
+
+// Get returns the permission of the command, or nil if the command is not restricted.
+func (p Permissions) Get(command string) *Permission {
+	if p == nil {
+		return nil
+	}
+	return p[command]
+}
+
+// Permission allows the user to run the command if any of the conditions matches.
+type Permission struct {
+	Users []string `yaml:"users"`
+	// Teams are "org/team" or "team" of the owner of the repository on GitHub, and the full path of the group on GitLab.
+	Teams []string `yaml:"teams"`
+	// Role is the minimum permission level of the repository collaborator.
+	Role string `yaml:"role" validate:"omitempty,oneof=read triage write maintain admin"`
+}
+
+// The stages of Hooks.
+const (
+	HookPreInit   = "pre_init"
+	HookPrePlan   = "pre_plan"
+	HookPostPlan  = "post_plan"
+	HookPreApply  = "pre_apply"
+	HookPostApply = "post_apply"
+)
+
+// Hooks are the shell commands run in the project directory around the Terraform steps.
+type Hooks struct {
+	PreInit   []string `yaml:"pre_init" validate:"dive,required"`
+	PrePlan   []string `yaml:"pre_plan" validate:"dive,required"`
+	PostPlan  []string `yaml:"post_plan" validate:"dive,required"`
+	PreApply  []string `yaml:"pre_apply" validate:"dive,required"`
+	PostApply []string `yaml:"post_apply" validate:"dive,required"`
+}
+
+// Get returns the commands of the stage.
+func (h *Hooks) Get(stage string) []string {
+	if h == nil {
+		return nil
+	}
+	switch stage {
+	case HookPreInit:
+		return h.PreInit
+	case HookPrePlan:
+		return h.PrePlan
+	case HookPostPlan:
+		return h.PostPlan
+	case HookPreApply:
+		return h.PreApply
+	case HookPostApply:
+		return h.PostApply
+	default:
+		return nil
+	}
+}
+
+// The types of Step.
+const (
+	StepInit  = "init"
+	StepPlan  = "plan"
+	StepApply = "apply"
+	StepRun   = "run"
+)
+
+// Workflow replaces the default steps of plan and apply.
+type Workflow struct {
+	Plan  []*Step `yaml:"plan" validate:"dive,required"`
+	Apply []*Step `yaml:"apply" validate:"dive,required"`
+}
+
+func (w *Workflow) validate() error {
+	if err := validateSteps(w.Plan, StepPlan); err != nil {
+		return fmt.Errorf("plan: %w", err)
+	}
+	if err := validateSteps(w.Apply, StepApply); err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+	return nil
+}
+
+// runsInit reports whether both plan and apply run the init step, which runs the pre_init hooks.
+func (w *Workflow) runsInit() bool {
+	return stepsRunInit(w.Plan) && stepsRunInit(w.Apply)
+}
+
+// stepsRunInit reports whether the steps have init. The empty steps do since the default steps are run instead.
+func stepsRunInit(steps []*Step) bool {
+	return len(steps) == 0 || slices.ContainsFunc(steps, func(step *Step) bool {
+		return step.Type() == StepInit
+	})
+}
+
+// validateSteps checks that the steps run the main step once, and init at most once before it.
+// Empty steps are valid since the default steps are run instead.
+func validateSteps(steps []*Step, main string) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, 3)
+	for i, step := range steps {
+		typ := step.Type()
+		switch typ {
+		case "":
+			return fmt.Errorf("step %d must have one of init, %s or run", i+1, main)
+		case StepInit:
+			if counts[main] > 0 {
+				return fmt.Errorf("step %d: init must be before %s", i+1, main)
+			}
+		case StepRun:
+		default:
+			if typ != main {
+				return fmt.Errorf("step %d: %s is not allowed", i+1, typ)
+			}
+		}
+		counts[typ]++
+	}
+	if counts[main] != 1 {
+		return fmt.Errorf("%s must be run exactly once", main)
+	}
+	if counts[StepInit] > 1 {
+		return errors.New("init must be run at most once")
+	}
+	return nil
+}
+
+// Step is one of the built-in steps with the extra arguments, or a shell command.
+// The built-in steps without the extra arguments can be written as the name, e.g. `- init`.
+type Step struct {
+	Init  *StepArgs `yaml:"init"`
+	Plan  *StepArgs `yaml:"plan"`
+	Apply *StepArgs `yaml:"apply"`
+	// Run is the shell command run in the project directory. Its output is shown in the comment.
+	Run string `yaml:"run"`
+}
+
+func (s *Step) UnmarshalYAML(unmarshal func(any) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		switch name {
+		case StepInit:
+			s.Init = &StepArgs{}
+		case StepPlan:
+			s.Plan = &StepArgs{}
+		case StepApply:
+			s.Apply = &StepArgs{}
+		default:
+			return fmt.Errorf("unknown step %q", name)
+		}
+		return nil
+	}
+	type step Step
+	return unmarshal((*step)(s))
+}
+
+// Type returns the type of the step, or an empty string unless exactly one of them is set.
+func (s *Step) Type() string {
+	var types []string
+	if s.Init != nil {
+		types = append(types, StepInit)
+	}
+	if s.Plan != nil {
+		types = append(types, StepPlan)
+	}
+	if s.Apply != nil {
+		types = append(types, StepApply)
+	}
+	if s.Run != "" {
+		types = append(types, StepRun)
+	}
+	if len(types) != 1 {
+		return ""
+	}
+	return types[0]
+}
+
+// GetExtraArgs returns the extra arguments of the built-in step.
+func (s *Step) GetExtraArgs() []string {
+	switch {
+	case s.Init != nil:
+		return s.Init.ExtraArgs
+	case s.Plan != nil:
+		return s.Plan.ExtraArgs
+	case s.Apply != nil:
+		return s.Apply.ExtraArgs
+	default:
+		return nil
+	}
+}
+
+type StepArgs struct {
+	ExtraArgs []string `yaml:"extra_args"`
+}
+
+// Terragrunt configures the projects of the Terragrunt units.
+type Terragrunt struct {
+	// Discover adds a project for each unit found in the directories, so that the units need not be listed in projects.
+	Discover []*Discover `yaml:"discover" validate:"dive,required"`
+}
+
+// DefaultTerragruntPlanPaths are plan.paths of the discovered projects without plan in the template.
+var DefaultTerragruntPlanPaths = []string{"*.hcl", "*.tf*"}
+
+type Discover struct {
+	// Dir is searched recursively for terragrunt.hcl, except the one in Dir itself, which is the parent configuration.
+	Dir string `yaml:"dir" validate:"required"`
+	// Project is the template of the discovered projects. Its name and dir are ignored.
+	Project *Project `yaml:"project" validate:"-"`
+}
+
+// newProject returns the project of the unit in dir, which is named after dir, e.g. `live-prod-vpc` of `live/prod/vpc`.
+func (d *Discover) newProject(dir string) *Project {
+	project := &Project{}
+	if d.Project != nil {
+		*project = *d.Project
+	}
+	project.Name = strings.ReplaceAll(dir, "/", "-")
+	project.Dir = dir
+	tf := &Terraform{}
+	if project.Terraform != nil {
+		*tf = *project.Terraform
+	}
+	tf.Wrapper = WrapperTerragrunt
+	project.Terraform = tf
+	if project.Plan == nil {
+		project.Plan = &Plan{Paths: DefaultTerragruntPlanPaths}
+	}
+	return project
+}
+
+// WrapperTerragrunt runs Terraform through Terragrunt.
+const WrapperTerragrunt = "terragrunt"
+
 type Terraform struct {
 	Version           string            `yaml:"version"`
 	ExecPath          string            `yaml:"exec_path"`
@@ -91,6 +690,24 @@ type Terraform struct {
 	VarFiles          []string          `yaml:"var_files"`
 	BackendConfigPath string            `yaml:"backend_config_path"`
 	BackendConfig     map[string]string `yaml:"backend_config"`
+	// Wrapper runs the Terraform commands through the wrapper, which is only `terragrunt`.
+	Wrapper string `yaml:"wrapper" validate:"omitempty,oneof=terragrunt"`
+	// WrapperExecPath is the path of the wrapper. The wrapper is looked up in PATH if it is empty.
+	WrapperExecPath string `yaml:"wrapper_exec_path"`
+}
+
+func (t *Terraform) GetWrapper() string {
+	if t == nil {
+		return ""
+	}
+	return t.Wrapper
+}
+
+func (t *Terraform) GetWrapperExecPath() string {
+	if t == nil {
+		return ""
+	}
+	return t.WrapperExecPath
 }
 
 func (t *Terraform) GetVersion() string {
@@ -136,24 +753,25 @@ func (t *Terraform) GetBackendConfig() map[string]string {
 }
 
 type Plan struct {
-	Paths []string `yaml:"paths" validate:"required,gt=0,dive,required"`
-	Auto  bool     `yaml:"auto"`
+	Paths    []string `yaml:"paths" validate:"required,gt=0,dive,required"`
+	Auto     bool     `yaml:"auto"`
+	Validate bool     `yaml:"validate"`
+	Fmt      bool     `yaml:"fmt"`
+}
+
+// planPattern returns the pattern of the path in Paths relative to baseDir, keeping the exclusion.
+func planPattern(baseDir, path string) string {
+	path = strings.TrimSpace(path)
+	if path != "" && path[0] == '!' {
+		return "!" + filepath.Join(baseDir, path[1:])
+	}
+	return filepath.Join(baseDir, path)
 }
 
 func (p *Plan) HasMatchedPaths(baseDir string, files []string) bool {
 	patterns := make([]string, 0, len(p.Paths))
 	for _, path := range p.Paths {
-		path = strings.TrimSpace(path)
-		var exclusion bool
-		if path != "" && path[0] == '!' {
-			path = path[1:]
-			exclusion = true
-		}
-		pattern := filepath.Join(baseDir, path)
-		if exclusion {
-			pattern = "!" + pattern
-		}
-		patterns = append(patterns, pattern)
+		patterns = append(patterns, planPattern(baseDir, path))
 	}
 	matcher, err := patternmatcher.New(patterns)
 	if err != nil {
@@ -171,8 +789,18 @@ func (p *Plan) HasMatchedPaths(baseDir string, files []string) bool {
 	return false
 }
 
+const (
+	// ApplyModeBeforeMerge applies the plan from a pull request comment before merging (default).
+	ApplyModeBeforeMerge = "before_merge"
+	// ApplyModeAfterMerge applies the plan on the push event of the default branch after merging.
+	ApplyModeAfterMerge = "after_merge"
+)
+
 type Apply struct {
-	RequireApprovals int `yaml:"require_approvals"`
+	RequireApprovals int    `yaml:"require_approvals"`
+	Mode             string `yaml:"mode" validate:"omitempty,oneof=before_merge after_merge"`
+	// AutoOnApproval applies the plan when the pull request is approved, without a `mu apply` comment.
+	AutoOnApproval bool `yaml:"auto_on_approval"`
 }
 
 func (a *Apply) GetRequireApprovals() int {
@@ -182,8 +810,72 @@ func (a *Apply) GetRequireApprovals() int {
 	return a.RequireApprovals
 }
 
+func (a *Apply) GetAutoOnApproval() bool {
+	if a == nil {
+		return false
+	}
+	return a.AutoOnApproval
+}
+
+func (a *Apply) GetMode() string {
+	if a == nil || a.Mode == "" {
+		return ApplyModeBeforeMerge
+	}
+	return a.Mode
+}
+
+func (a *Apply) IsAfterMerge() bool {
+	return a.GetMode() == ApplyModeAfterMerge
+}
+
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// Automerge merges the pull request once every project changed by it has been applied.
+type Automerge struct {
+	Enabled      bool   `yaml:"enabled"`
+	Method       string `yaml:"method" validate:"omitempty,oneof=merge squash rebase"`
+	DeleteBranch bool   `yaml:"delete_branch"`
+}
+
+func (a *Automerge) GetEnabled() bool {
+	if a == nil {
+		return false
+	}
+	return a.Enabled
+}
+
+func (a *Automerge) GetMethod() string {
+	if a == nil || a.Method == "" {
+		return MergeMethodMerge
+	}
+	return a.Method
+}
+
+func (a *Automerge) GetDeleteBranch() bool {
+	if a == nil {
+		return false
+	}
+	return a.DeleteBranch
+}
+
+type Drift struct {
+	RefreshOnly bool `yaml:"refresh_only"`
+}
+
+func (d *Drift) GetRefreshOnly() bool {
+	if d == nil {
+		return false
+	}
+	return d.RefreshOnly
+}
+
 type options struct {
 	defaultTerraformVersion string
+	baseDir                 string
 }
 
 type Option func(o *options)
@@ -194,21 +886,84 @@ func WithDefaultTerraformVersion(version string) Option {
 	}
 }
 
-func Load(filePath string, opts ...Option) (*Config, error) {
-	o := &options{}
-	for i := range opts {
-		opts[i](o)
+// WithBaseDir sets the directory which the dirs of the projects are relative to, which is the current directory by default.
+func WithBaseDir(dir string) Option {
+	return func(o *options) {
+		o.baseDir = dir
 	}
+}
+
+func Load(filePath string, opts ...Option) (*Config, error) {
 	file, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	expanded := os.ExpandEnv(string(file))
+	return load(filePath, file, true, opts)
+}
+
+// Parse parses the config file of another revision, such as the base branch of a pull request, without reading
+// the file system: the fragments are not included, and the Terragrunt units are not discovered.
+func Parse(filePath string, data []byte, opts ...Option) (*Config, error) {
+	return load(filePath, data, false, opts)
+}
+
+// load decodes the config file. The fragments and the Terragrunt units under the base dir are read if readDir is true.
+func load(filePath string, file []byte, readDir bool, opts []Option) (*Config, error) {
+	o := &options{
+		baseDir: ".",
+	}
+	for i := range opts {
+		opts[i](o)
+	}
 	cfg := &Config{
 		defaultTerraformVersion: o.defaultTerraformVersion,
+		baseDir:                 o.baseDir,
 	}
-	if err := yaml.Unmarshal([]byte(expanded), cfg); err != nil {
+	var root yaml.Node
+	if err := yaml.Unmarshal(file, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	src := &source{file: filePath}
+	if len(root.Content) > 0 {
+		src.doc = root.Content[0]
+	}
+	if errs := src.checkFields(src.doc, reflect.TypeOf(Config{})); len(errs) > 0 {
+		return nil, errs
+	}
+	if errs := src.expandEnv(src.doc); len(errs) > 0 {
+		return nil, errs
+	}
+	if readDir {
+		if err := src.includeFragments(o.baseDir); err != nil {
+			return nil, err
+		}
+	}
+	if err := src.mergeProjects(); err != nil {
+		return nil, err
+	}
+	if src.doc != nil {
+		if err := root.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+	}
+	cfg.source = src
+	if projects := mappingValue(src.doc, "projects"); projects != nil && len(projects.Content) == len(cfg.Projects) {
+		for i, project := range cfg.Projects {
+			src.setProjectNode(project, projects.Content[i])
+		}
+	}
+	if readDir {
+		if err := cfg.discoverTerragruntProjects(o.baseDir); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.expandWorkspaces(); err != nil {
 		return nil, err
 	}
+	if readDir {
+		if err := cfg.orderTerragruntProjects(o.baseDir); err != nil {
+			return nil, err
+		}
+	}
 	return cfg, nil
 }
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -34,6 +36,19 @@ func TestConfig_Validate(t *testing.T) {
 						},
 						Apply: &Apply{
 							RequireApprovals: 1,
+							Mode:             ApplyModeAfterMerge,
+						},
+						Permissions: Permissions{
+							"apply": {
+								Users: []string{"alice"},
+								Teams: []string{"org/sre"},
+								Role:  "maintain",
+							},
+							"force_unlock": {Role: "admin"},
+						},
+						Hooks: &Hooks{
+							PreInit:  []string{"./generate-backend.sh"},
+							PostPlan: []string{"checkov -f \"$MU_PLAN_FILE\""},
 						},
 					},
 				},
@@ -149,6 +164,120 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expect: ErrInvalidConfig,
 		},
+		{
+			name: "invalid project.apply.mode",
+			cfg: &Config{
+				Version: 1,
+				Projects: []*Project{
+					{
+						Name:      "test",
+						Dir:       ".",
+						Workspace: "default",
+						Terraform: &Terraform{
+							Version: "latest",
+						},
+						Plan: &Plan{
+							Paths: []string{
+								"*tf*",
+							},
+							Auto: true,
+						},
+						Apply: &Apply{
+							RequireApprovals: 1,
+							Mode:             "unknown",
+						},
+					},
+				},
+			},
+			expect: ErrInvalidConfig,
+		},
+		{
+			name: "invalid automerge.method",
+			cfg: &Config{
+				Version: 1,
+				Projects: []*Project{
+					{
+						Name:      "test",
+						Dir:       ".",
+						Workspace: "default",
+						Plan: &Plan{
+							Paths: []string{
+								"*tf*",
+							},
+						},
+					},
+				},
+				Automerge: &Automerge{
+					Enabled: true,
+					Method:  "fast-forward",
+				},
+			},
+			expect: ErrInvalidConfig,
+		},
+		{
+			name: "invalid permissions command",
+			cfg: &Config{
+				Version: 1,
+				Projects: []*Project{
+					{
+						Name: "test",
+						Dir:  ".",
+						Plan: &Plan{
+							Paths: []string{
+								"*tf*",
+							},
+						},
+						Permissions: Permissions{
+							"destroy": {Users: []string{"alice"}},
+						},
+					},
+				},
+			},
+			expect: ErrInvalidConfig,
+		},
+		{
+			name: "invalid permissions role",
+			cfg: &Config{
+				Version: 1,
+				Projects: []*Project{
+					{
+						Name: "test",
+						Dir:  ".",
+						Plan: &Plan{
+							Paths: []string{
+								"*tf*",
+							},
+						},
+						Permissions: Permissions{
+							"apply":        {Role: "owner"},
+							"force_unlock": {Teams: []string{"sre"}},
+						},
+					},
+				},
+			},
+			expect: ErrInvalidConfig,
+		},
+		{
+			name: "empty hook",
+			cfg: &Config{
+				Version: 1,
+				Projects: []*Project{
+					{
+						Name: "test",
+						Dir:  ".",
+						Plan: &Plan{
+							Paths: []string{
+								"*tf*",
+							},
+						},
+						Hooks: &Hooks{
+							PrePlan: []string{"tflint", ""},
+						},
+					},
+				},
+			},
+			expect: ErrInvalidConfig,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -245,8 +374,20 @@ func TestLoad(t *testing.T) {
 	t.Setenv("TERRAFORM_VERSION", "1.9.3")
 	cfg, err := Load("./testdata/mu.yaml", WithDefaultTerraformVersion("1.9.0"))
 	require.NoError(t, err)
+	workflow := &Workflow{
+		Plan: []*Step{
+			{Init: &StepArgs{ExtraArgs: []string{"-upgrade"}}},
+			{Run: "tflint"},
+			{Plan: &StepArgs{}},
+		},
+	}
 	expect := &Config{
+		Workflows: map[string]*Workflow{
+			"custom": workflow,
+		},
 		defaultTerraformVersion: "1.9.0",
+		baseDir:                 ".",
+		source:                  cfg.source,
 		Version:                 1,
 		Projects: Projects{
 			{
@@ -295,8 +436,411 @@ func TestLoad(t *testing.T) {
 				},
 				Apply:          nil,
 				LockLabelColor: "",
+				Workflow:       "custom",
+				workflow:       workflow,
 			},
 		},
 	}
 	assert.Equal(t, expect, cfg)
 }
+
+func TestProject_Steps(t *testing.T) {
+	t.Parallel()
+	project := &Project{Name: "test"}
+	assert.Equal(t, []*Step{{Init: &StepArgs{}}, {Plan: &StepArgs{}}}, project.PlanSteps())
+	assert.Equal(t, []*Step{{Init: &StepArgs{}}, {Apply: &StepArgs{}}}, project.ApplySteps())
+
+	project.SetWorkflow(&Workflow{
+		Apply: []*Step{{Run: "./pre-apply.sh"}, {Apply: &StepArgs{ExtraArgs: []string{"-parallelism=5"}}}},
+	})
+	assert.Equal(t, []*Step{{Init: &StepArgs{}}, {Plan: &StepArgs{}}}, project.PlanSteps())
+	assert.Equal(t, []*Step{{Run: "./pre-apply.sh"}, {Apply: &StepArgs{ExtraArgs: []string{"-parallelism=5"}}}}, project.ApplySteps())
+}
+
+func TestWorkflow_validate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		workflow *Workflow
+		expect   string
+	}{
+		"success": {
+			workflow: &Workflow{
+				Plan:  []*Step{{Run: "./generate.sh"}, {Init: &StepArgs{}}, {Plan: &StepArgs{}}, {Run: "conftest"}},
+				Apply: []*Step{{Apply: &StepArgs{}}},
+			},
+		},
+		"empty step": {
+			workflow: &Workflow{
+				Plan: []*Step{{}, {Plan: &StepArgs{}}},
+			},
+			expect: "plan: step 1 must have one of init, plan or run",
+		},
+		"multiple types": {
+			workflow: &Workflow{
+				Plan: []*Step{{Init: &StepArgs{}, Run: "echo"}, {Plan: &StepArgs{}}},
+			},
+			expect: "plan: step 1 must have one of init, plan or run",
+		},
+		"apply in plan": {
+			workflow: &Workflow{
+				Plan: []*Step{{Plan: &StepArgs{}}, {Apply: &StepArgs{}}},
+			},
+			expect: "plan: step 2: apply is not allowed",
+		},
+		"no apply": {
+			workflow: &Workflow{
+				Apply: []*Step{{Init: &StepArgs{}}, {Run: "echo"}},
+			},
+			expect: "apply: apply must be run exactly once",
+		},
+		"init after plan": {
+			workflow: &Workflow{
+				Plan: []*Step{{Plan: &StepArgs{}}, {Init: &StepArgs{}}},
+			},
+			expect: "plan: step 2: init must be before plan",
+		},
+		"twice init": {
+			workflow: &Workflow{
+				Plan: []*Step{{Init: &StepArgs{}}, {Init: &StepArgs{}}, {Plan: &StepArgs{}}},
+			},
+			expect: "plan: init must be run at most once",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.workflow.validate()
+			if tt.expect == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expect)
+		})
+	}
+}
+
+func TestConfig_Validate_Workflow(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		Version: 1,
+		Projects: []*Project{
+			{
+				Name:     "test",
+				Dir:      ".",
+				Plan:     &Plan{Paths: []string{"*.tf"}},
+				Workflow: "missing",
+			},
+		},
+		Workflows: map[string]*Workflow{
+			"custom": {Plan: []*Step{{Plan: &StepArgs{}}}},
+		},
+	}
+	err := cfg.Validate()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), `project "test": workflow "missing" is not found`)
+
+	cfg.Projects[0].Workflow = "custom"
+	require.NoError(t, cfg.Validate())
+
+	// pre_init is run by the init step, which the plan steps of the workflow do not have.
+	cfg.Projects[0].Hooks = &Hooks{PreInit: []string{"./setup.sh"}}
+	err = cfg.Validate()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), `project "test": hooks.pre_init is never run since workflow "custom" has no init step`)
+
+	cfg.Workflows["custom"].Plan = []*Step{{Init: &StepArgs{}}, {Plan: &StepArgs{}}}
+	require.NoError(t, cfg.Validate())
+	cfg.Workflows["custom"].Apply = []*Step{{Apply: &StepArgs{}}}
+	require.ErrorIs(t, cfg.Validate(), ErrInvalidConfig)
+}
+
+func TestStep_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	var steps []*Step
+	err := yaml.Unmarshal([]byte(`
+- init
+- apply:
+    extra_args: ["-parallelism=5"]
+- run: ./notify.sh
+`), &steps)
+	require.NoError(t, err)
+	assert.Equal(t, []*Step{
+		{Init: &StepArgs{}},
+		{Apply: &StepArgs{ExtraArgs: []string{"-parallelism=5"}}},
+		{Run: "./notify.sh"},
+	}, steps)
+
+	err = yaml.Unmarshal([]byte(`- destroy`), &steps)
+	assert.EqualError(t, err, `unknown step "destroy"`)
+}
+
+func TestLoad_Terragrunt(t *testing.T) {
+	t.Parallel()
+	cfg, err := Load("./testdata/terragrunt/mu.yaml", WithBaseDir("./testdata/terragrunt"))
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	template := &Terraform{Version: "1.9.0", Wrapper: WrapperTerragrunt}
+	expect := Projects{
+		{
+			Name:      "live-vpc",
+			Dir:       "live/vpc",
+			Terraform: template,
+			Plan:      &Plan{Paths: DefaultTerragruntPlanPaths},
+			Apply:     &Apply{RequireApprovals: 1},
+		},
+		{
+			Name:      "live-db",
+			Dir:       "live/db",
+			Terraform: template,
+			Plan:      &Plan{Paths: DefaultTerragruntPlanPaths},
+			Apply:     &Apply{RequireApprovals: 1},
+		},
+		{
+			Name:      "app",
+			Dir:       "live/app",
+			Terraform: &Terraform{Version: "latest", Wrapper: WrapperTerragrunt},
+			Plan:      &Plan{Paths: []string{"*.hcl", "../modules/app/*.tf"}, Auto: true},
+		},
+	}
+	assert.Equal(t, expect, Projects(cfg.Projects))
+}
+
+func TestLoad_TerragruntCycle(t *testing.T) {
+	t.Parallel()
+	_, err := Load("./testdata/terragrunt/cycle.yaml", WithBaseDir("./testdata/terragrunt"))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), "dependency cycle: cycle/a -> cycle/b -> cycle/a")
+}
+
+func TestLoad_Workspaces(t *testing.T) {
+	t.Parallel()
+	cfg, err := Load("./testdata/workspaces/mu.yaml")
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	expect := Projects{
+		{
+			Name:      "network",
+			Dir:       "network",
+			Workspace: "dev",
+			Terraform: &Terraform{Version: "latest"},
+			Plan:      &Plan{Paths: []string{"*.tf"}, Auto: true},
+			matrix:    true,
+		},
+		{
+			Name:      "network",
+			Dir:       "network",
+			Workspace: "prod",
+			Terraform: &Terraform{Version: "latest"},
+			Plan:      &Plan{Paths: []string{"*.tf"}, Auto: true},
+			matrix:    true,
+		},
+		{
+			Name:      "app",
+			Dir:       "app",
+			Workspace: "stg",
+			Terraform: &Terraform{
+				Version:       "1.9.0",
+				VarFiles:      []string{"common.tfvars", "stg.tfvars"},
+				BackendConfig: map[string]string{"bucket": "tfstate"},
+			},
+			Plan:   &Plan{Paths: []string{"*.tf"}, Auto: true},
+			matrix: true,
+		},
+		{
+			Name:      "app",
+			Dir:       "app",
+			Workspace: "prod",
+			Terraform: &Terraform{
+				Version:       "1.9.0",
+				Vars:          []string{"replicas=3"},
+				VarFiles:      []string{"common.tfvars", "prod.tfvars"},
+				BackendConfig: map[string]string{"bucket": "tfstate-prod"},
+			},
+			Plan:   &Plan{Paths: []string{"*.tf"}, Auto: true},
+			Apply:  &Apply{RequireApprovals: 2},
+			matrix: true,
+		},
+	}
+	assert.Equal(t, expect, Projects(cfg.Projects))
+	assert.Equal(t, "app/prod", cfg.Projects[3].ID())
+	assert.Equal(t, Projects{cfg.Projects[2], cfg.Projects[3]}, cfg.GetProjects("app", ""))
+	assert.Equal(t, Projects{cfg.Projects[3]}, cfg.GetProjects("app", "prod"))
+	assert.Empty(t, cfg.GetProjects("app", "dev"))
+}
+
+func TestLoad_DuplicatedWorkspaces(t *testing.T) {
+	t.Parallel()
+	_, err := Load("./testdata/workspaces/duplicated.yaml")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), `project "app": workspace "dev" is duplicated`)
+}
+
+func TestLoad_Templates(t *testing.T) {
+	t.Parallel()
+	cfg, err := Load("./testdata/templates/mu.yaml")
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	expect := Projects{
+		{
+			Name: "dev",
+			Dir:  "dev",
+			Terraform: &Terraform{
+				Version:       "1.9.0",
+				VarFiles:      []string{"common.tfvars"},
+				BackendConfig: map[string]string{"bucket": "tfstate"},
+			},
+			Plan: &Plan{Paths: []string{"*.tf"}, Auto: true},
+		},
+		{
+			Name:    "prod",
+			Dir:     "prod",
+			Extends: Extends{"prod", "after_merge"},
+			Terraform: &Terraform{
+				Version:       "1.9.0",
+				VarFiles:      []string{"common.tfvars", "prod.tfvars", "app.tfvars"},
+				BackendConfig: map[string]string{"bucket": "tfstate-prod", "region": "us-east-1"},
+			},
+			Plan:  &Plan{Paths: []string{"*.tf", "modules/**"}, Auto: true},
+			Apply: &Apply{RequireApprovals: 2, Mode: ApplyModeAfterMerge},
+		},
+		{
+			Name:    "manual",
+			Dir:     "manual",
+			Extends: Extends{"prod"},
+			Terraform: &Terraform{
+				Version:       "1.9.0",
+				VarFiles:      []string{"common.tfvars", "prod.tfvars"},
+				BackendConfig: map[string]string{"bucket": "tfstate-prod", "region": "us-east-1"},
+			},
+			Plan:  &Plan{Paths: []string{"*.tf"}},
+			Apply: &Apply{RequireApprovals: 2},
+		},
+	}
+	assert.Equal(t, expect, Projects(cfg.Projects))
+}
+
+func TestLoad_TemplateNotFound(t *testing.T) {
+	t.Parallel()
+	_, err := Load("./testdata/templates/not_found.yaml")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err, `./testdata/templates/not_found.yaml:5:14: template "unknown" is not found`)
+}
+
+func TestLoad_Include(t *testing.T) {
+	t.Parallel()
+	const baseDir = "./testdata/include"
+	cfg, err := Load(baseDir+"/.github/mu.yaml", WithBaseDir(baseDir))
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	plan := &Plan{Paths: []string{"*.tf"}, Auto: true}
+	expect := Projects{
+		{Name: "root", Dir: "terraform", Terraform: &Terraform{Version: "latest"}, Plan: plan},
+		{Name: "app", Dir: "terraform/app", Terraform: &Terraform{Version: "latest"}, Plan: plan, Apply: &Apply{RequireApprovals: 1}},
+		{Name: "app-modules", Dir: "terraform/app/modules", Terraform: &Terraform{Version: "latest"}, Plan: plan},
+		{Name: "network", Dir: "terraform/network", Terraform: &Terraform{Version: "latest"}, Plan: plan},
+	}
+	assert.Equal(t, expect, Projects(cfg.Projects))
+}
+
+func TestLoad_IncludeDuplicated(t *testing.T) {
+	t.Parallel()
+	const baseDir = "./testdata/include/dup"
+	_, err := Load(baseDir+"/mu.yaml", WithBaseDir(baseDir))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err,
+		`terraform/app/mu.project.yaml:5:5: project "app" is already defined at ./testdata/include/dup/mu.yaml:5:5`)
+}
+
+func TestLoad_IncludePermissions(t *testing.T) {
+	t.Parallel()
+	const baseDir = "./testdata/include/permissions"
+	_, err := Load(baseDir+"/mu.yaml", WithBaseDir(baseDir))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err, `terraform/prod/mu.project.yaml:7:7: permissions cannot be set in a fragment
+terraform/prod/mu.project.yaml:3:14: template "prod" with permissions cannot be extended in a fragment`)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	const baseDir = "./testdata/include"
+	data, err := os.ReadFile(baseDir + "/.github/mu.yaml")
+	require.NoError(t, err)
+	cfg, err := Parse(".github/mu.yaml", data, WithBaseDir(baseDir))
+	require.NoError(t, err)
+
+	// The fragments are not included.
+	plan := &Plan{Paths: []string{"*.tf"}, Auto: true}
+	expect := Projects{
+		{Name: "root", Dir: "terraform", Terraform: &Terraform{Version: "latest"}, Plan: plan},
+	}
+	assert.Equal(t, expect, Projects(cfg.Projects))
+}
+
+func TestLoad_UnknownFields(t *testing.T) {
+	t.Parallel()
+	_, err := Load("./testdata/validate/unknown.yaml")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err, `./testdata/validate/unknown.yaml:2:1: unknown field "projct" in config
+./testdata/validate/unknown.yaml:12:7: unknown field "owners" in apply`)
+}
+
+func TestConfig_Validate_Positions(t *testing.T) {
+	t.Parallel()
+	const baseDir = "./testdata/validate"
+	cfg, err := Load(baseDir+"/invalid.yaml", WithBaseDir(baseDir))
+	require.NoError(t, err)
+
+	err = cfg.Validate()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 5)
+	assert.EqualError(t, err, `./testdata/validate/invalid.yaml:8:11: project "app": plan.paths[1] "[.tf" is invalid: syntax error in pattern
+./testdata/validate/invalid.yaml:10:13: project "app": apply.mode must be one of before_merge, after_merge
+./testdata/validate/invalid.yaml:11:15: project "app": workflow "custom" is not found
+./testdata/validate/invalid.yaml:12:5: project "network": plan is required
+./testdata/validate/invalid.yaml:21:5: workflow "default": plan: plan must be run exactly once`)
+
+	err = cfg.ValidateDirs()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err, `./testdata/validate/invalid.yaml:15:10: project "missing": dir "terraform/missing" does not exist`)
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+	schema, err := JSONSchema()
+	require.NoError(t, err)
+	committed, err := os.ReadFile("../../mu.schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(schema), "mu.schema.json is outdated, run `go generate ./pkg/config`")
+}
+
+func TestLoad_Env(t *testing.T) {
+	t.Setenv("MU_TEST_REGION", "ap-northeast-1")
+	t.Setenv("MU_TEST_KEY", "unused")
+	t.Setenv("MU_TEST_TOKEN", "s3cr3t")
+	t.Setenv("MU_TEST_APPROVALS", "2")
+	cfg, err := Load("./testdata/env/mu.yaml")
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	project := cfg.GetProject("app")
+	assert.Equal(t, "1.9.0", project.Terraform.Version)
+	assert.Equal(t, []string{"region=ap-northeast-1", "price=$5", "literal=${env:MU_TEST_REGION}"}, project.Terraform.Vars)
+	assert.Equal(t, map[string]string{"${env:MU_TEST_KEY}": "s3cr3t"}, project.Terraform.BackendConfig)
+	assert.Equal(t, 2, project.Apply.RequireApprovals)
+	assert.Equal(t, []string{`checkov -f "$MU_PLAN_FILE"`}, project.Hooks.PostPlan)
+	assert.Equal(t, []string{"s3cr3t"}, cfg.Secrets())
+}
+
+func TestLoad_EnvMissing(t *testing.T) {
+	_, err := Load("./testdata/env/missing.yaml")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.EqualError(t, err, `./testdata/env/missing.yaml:4:10: environment variable "MU_TEST_MISSING_DIR" is not set
+./testdata/env/missing.yaml:9:26: environment variable "MU_TEST_MISSING_APPROVALS": set the approvals
+./testdata/env/missing.yaml:11:16: environment variable "MU_TEST_MISSING_VERSION" is required`)
+}
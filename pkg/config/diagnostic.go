@@ -0,0 +1,336 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// Position is the location in the config files.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	var parts []string
+	if p.File != "" {
+		parts = append(parts, p.File)
+	}
+	if p.Line > 0 {
+		parts = append(parts, strconv.Itoa(p.Line), strconv.Itoa(p.Column))
+	}
+	return strings.Join(parts, ":")
+}
+
+// Error is a problem of the config at the position, which wraps ErrInvalidConfig.
+type Error struct {
+	Position Position
+	Message  string
+}
+
+func (e *Error) Error() string {
+	if pos := e.Position.String(); pos != "" {
+		return pos + ": " + e.Message
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Errors are the problems of the config, one per line.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// err returns the errors as an error, or nil if there is no error.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// sort sorts the errors in the order of the positions.
+func (e Errors) sort() {
+	slices.SortStableFunc(e, func(a, b *Error) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.File, b.Position.File),
+			cmp.Compare(a.Position.Line, b.Position.Line),
+			cmp.Compare(a.Position.Column, b.Position.Column),
+		)
+	})
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// source knows the config file which the nodes come from, to report the position of the problems.
+type source struct {
+	file string
+	doc  *yaml.Node
+	// files are the files of the nodes which do not come from file, i.e. the included fragments.
+	files map[*yaml.Node]string
+	// projects are the merged nodes which the projects are decoded from, since the projects are expanded after decoding.
+	projects map[*Project]*yaml.Node
+	// secrets are the values of ${secret:NAME}, which are masked in the logs.
+	secrets []string
+}
+
+func (s *source) position(node *yaml.Node) Position {
+	if s == nil {
+		return Position{}
+	}
+	if node == nil {
+		return Position{File: s.file}
+	}
+	file, ok := s.files[node]
+	if !ok {
+		file = s.file
+	}
+	return Position{File: file, Line: node.Line, Column: node.Column}
+}
+
+// document returns the root mapping of the config file, or nil if the config is not loaded from a file.
+func (s *source) document() *yaml.Node {
+	if s == nil {
+		return nil
+	}
+	return s.doc
+}
+
+func (s *source) errorf(node *yaml.Node, format string, args ...any) *Error {
+	return &Error{Position: s.position(node), Message: fmt.Sprintf(format, args...)}
+}
+
+func (s *source) projectNode(project *Project) *yaml.Node {
+	if s == nil {
+		return nil
+	}
+	return s.projects[project]
+}
+
+func (s *source) setProjectNode(project *Project, node *yaml.Node) {
+	if s == nil || project == nil || node == nil {
+		return
+	}
+	if s.projects == nil {
+		s.projects = make(map[*Project]*yaml.Node)
+	}
+	s.projects[project] = node
+}
+
+// addFile records that the node and its descendants come from the file.
+func (s *source) addFile(node *yaml.Node, file string) {
+	if s.files == nil {
+		s.files = make(map[*yaml.Node]string)
+	}
+	s.files[node] = file
+	for _, child := range node.Content {
+		s.addFile(child, file)
+	}
+}
+
+// checkFields reports the keys of the mappings which are not the fields of the type, as yaml.Decoder.KnownFields does,
+// since yaml.Node.Decode does not reject unknown fields.
+func (s *source) checkFields(node *yaml.Node, typ reflect.Type) Errors {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	var errs Errors
+	switch typ.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		fields := yamlFields(typ)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			field, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, s.errorf(key, "unknown field %q in %s", key.Value, typeName(typ)))
+				continue
+			}
+			errs = append(errs, s.checkFields(value, field.Type)...)
+		}
+	case reflect.Slice:
+		switch node.Kind {
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				errs = append(errs, s.checkFields(item, typ.Elem())...)
+			}
+		case yaml.MappingNode:
+			// The slices decoded from a map, e.g. Workspaces, have the items as the values.
+			for i := 1; i < len(node.Content); i += 2 {
+				errs = append(errs, s.checkFields(node.Content[i], typ.Elem())...)
+			}
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			errs = append(errs, s.checkFields(node.Content[i], typ.Elem())...)
+		}
+	}
+	return errs
+}
+
+// yamlFields returns the exported fields of the struct keyed by the yaml key.
+func yamlFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if key := yamlKey(field); key != "" {
+			fields[key] = field
+		}
+	}
+	return fields
+}
+
+// yamlKey returns the key of the field in the config file, or an empty string if the field is not decoded.
+func yamlKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	switch key {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(field.Name)
+	default:
+		return key
+	}
+}
+
+// typeName returns the name of the type in the messages, e.g. "apply" for Apply.
+func typeName(typ reflect.Type) string {
+	return strings.ToLower(typ.Name())
+}
+
+var namespaceSegmentRegex = regexp.MustCompile(`^(\w+)(?:\[(.*)\])?$`)
+
+// validationErrors converts the errors of the validator into the errors at the positions of the invalid values.
+func (c *Config) validationErrors(verrs validator.ValidationErrors) Errors {
+	errs := make(Errors, 0, len(verrs))
+	for _, verr := range verrs {
+		errs = append(errs, c.validationError(verr))
+	}
+	return errs
+}
+
+func (c *Config) validationError(verr validator.FieldError) *Error {
+	// The namespace is like "Config.Projects[0].Plan.Paths", whose first segment is the type of the struct.
+	segments := strings.Split(verr.StructNamespace(), ".")[1:]
+	var (
+		typ    = reflect.TypeOf(Config{})
+		node   *yaml.Node
+		prefix string
+		path   []string
+	)
+	node = c.source.document()
+	for i, segment := range segments {
+		m := namespaceSegmentRegex.FindStringSubmatch(segment)
+		if m == nil {
+			break
+		}
+		field, ok := typ.FieldByName(m[1])
+		if !ok {
+			break
+		}
+		typ = field.Type
+		if i == 0 && m[1] == "Projects" && m[2] != "" {
+			// The projects are expanded and discovered after the document is decoded, so their node is kept in the source.
+			index, err := strconv.Atoi(m[2])
+			if err != nil || index >= len(c.Projects) {
+				break
+			}
+			project := c.Projects[index]
+			node = c.source.projectNode(project)
+			prefix = fmt.Sprintf("project %q: ", project.ID())
+			typ = reflect.TypeOf(Project{})
+			continue
+		}
+		key := yamlKey(field)
+		path = append(path, key)
+		node = nodeValue(node, key)
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if m[2] == "" {
+			continue
+		}
+		path[len(path)-1] += "[" + m[2] + "]"
+		switch typ.Kind() {
+		case reflect.Slice:
+			if index, err := strconv.Atoi(m[2]); err == nil && node != nil && node.Kind == yaml.SequenceNode && index < len(node.Content) {
+				node = node.Content[index]
+			}
+		case reflect.Map:
+			if value := mappingValue(node, m[2]); value != nil {
+				node = value
+			}
+		}
+		typ = typ.Elem()
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+	}
+	return &Error{
+		Position: c.source.position(node),
+		Message:  prefix + strings.Join(path, ".") + " " + validationMessage(verr),
+	}
+}
+
+// nodeValue returns the value of the key, or the node itself if the key is not written, e.g. the required field.
+func nodeValue(node *yaml.Node, key string) *yaml.Node {
+	if value := mappingValue(node, key); value != nil {
+		return value
+	}
+	return node
+}
+
+func validationMessage(verr validator.FieldError) string {
+	switch verr.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(verr.Param()), ", "))
+	default:
+		if verr.Param() != "" {
+			return fmt.Sprintf("must satisfy %s=%s", verr.Tag(), verr.Param())
+		}
+		return fmt.Sprintf("must satisfy %s", verr.Tag())
+	}
+}
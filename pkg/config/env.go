@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envRegex matches the references to the environment variables in the values of the config:
+//
+//   - ${env:NAME} is the value of NAME, which must be set.
+//   - ${env:NAME:-default} is default if NAME is unset or empty.
+//   - ${env:NAME:?message} fails with message if NAME is unset or empty.
+//   - ${secret:NAME} is the same as ${env:NAME}, and its value is masked in the logs. The modifiers are also allowed.
+//
+// $${ escapes the reference, and the other $ are kept as they are, e.g. "$MU_PLAN_FILE" in the hooks.
+var envRegex = regexp.MustCompile(`\$?\$\{(env|secret):([A-Za-z_][A-Za-z0-9_]*)(?:(:-|:\?)([^}]*))?\}`)
+
+// expandEnv expands the references to the environment variables in the scalar values under the node.
+// The keys of the mappings are never expanded.
+func (s *source) expandEnv(node *yaml.Node) Errors {
+	if node == nil {
+		return nil
+	}
+	var errs Errors
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			errs = append(errs, s.expandEnv(node.Content[i])...)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			errs = append(errs, s.expandEnv(item)...)
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		value, err := s.expandValue(node.Value)
+		if err != nil {
+			return Errors{s.errorf(node, "%s", err)}
+		}
+		node.Value = value
+		if node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			// The plain value is resolved again, e.g. to an int, as if the expanded value were written.
+			node.Tag = ""
+		}
+	}
+	// The aliases are skipped, since the anchored nodes are expanded where they are written.
+	return errs
+}
+
+// expandValue returns the value with the references expanded, and records the values of the secrets.
+func (s *source) expandValue(value string) (string, error) {
+	var errs []string
+	expanded := envRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		m := envRegex.FindStringSubmatch(ref)
+		kind, name, modifier, arg := m[1], m[2], m[3], m[4]
+		env, ok := os.LookupEnv(name)
+		switch modifier {
+		case ":-":
+			if env == "" {
+				return arg
+			}
+		case ":?":
+			if env == "" {
+				if arg == "" {
+					errs = append(errs, fmt.Sprintf("environment variable %q is required", name))
+				} else {
+					errs = append(errs, fmt.Sprintf("environment variable %q: %s", name, arg))
+				}
+				return ""
+			}
+		default:
+			if !ok {
+				errs = append(errs, fmt.Sprintf("environment variable %q is not set", name))
+				return ""
+			}
+		}
+		if kind == "secret" && env != "" {
+			s.secrets = append(s.secrets, env)
+		}
+		return env
+	})
+	if len(errs) > 0 {
+		return "", errors.New(strings.Join(errs, ", "))
+	}
+	return expanded, nil
+}
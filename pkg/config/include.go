@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"github.com/moby/patternmatcher"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFile is the name of the config fragment put next to the Terraform root, which is included by
+// `include: ["**/mu.project.yaml"]`.
+const ProjectFile = "mu.project.yaml"
+
+// The directories which are never searched for the fragments.
+var skipIncludeDirs = []string{".git", ".terraform", ".terragrunt-cache", "node_modules"}
+
+// includeFragments appends the projects of the fragments matched by the include globs to the projects of the document.
+// The globs and the files are relative to baseDir, and dir of the projects in a fragment is relative to the fragment.
+// The projects with the same name are rejected.
+func (s *source) includeFragments(baseDir string) error {
+	if s.doc == nil || s.doc.Kind != yaml.MappingNode {
+		return nil
+	}
+	defined := make(map[string]*yaml.Node)
+	projects := mappingValue(s.doc, "projects")
+	if projects != nil && projects.Kind == yaml.SequenceNode {
+		if err := s.checkDuplicatedProjects(defined, projects); err != nil {
+			return err
+		}
+	}
+
+	include := mappingValue(s.doc, "include")
+	if include == nil {
+		return nil
+	}
+	var patterns []string
+	if err := include.Decode(&patterns); err != nil {
+		return err
+	}
+	files, err := findFragments(baseDir, patterns)
+	if err != nil {
+		return s.errorf(include, "include: %s", err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	if projects == nil {
+		projects = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		s.doc.Content = append(s.doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "projects"}, projects)
+	}
+	for _, file := range files {
+		fragment, err := s.readFragment(baseDir, file)
+		if err != nil {
+			return err
+		}
+		if err := s.checkDuplicatedProjects(defined, fragment); err != nil {
+			return err
+		}
+		projects.Content = append(projects.Content, fragment.Content...)
+	}
+	return nil
+}
+
+// findFragments returns the files under baseDir matched by the patterns, relative to baseDir in slash-separated paths.
+func findFragments(baseDir string, patterns []string) ([]string, error) {
+	matcher, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	err = filepath.WalkDir(baseDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if slices.Contains(skipIncludeDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(baseDir, p)
+		if err != nil {
+			return err
+		}
+		matched, err := matcher.MatchesOrParentMatches(rel)
+		if err != nil {
+			return err
+		}
+		if matched {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// readFragment returns the projects of the fragment, whose dir is made relative to baseDir.
+// A fragment only has projects, and dir of its projects is the directory of the fragment by default.
+func (s *source) readFragment(baseDir, file string) (*yaml.Node, error) {
+	data, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(file)))
+	if err != nil {
+		return nil, err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(root.Content) == 0 {
+		return &yaml.Node{Kind: yaml.SequenceNode}, nil
+	}
+	doc := root.Content[0]
+	s.addFile(doc, file)
+	if doc.Kind != yaml.MappingNode {
+		return nil, s.errorf(doc, "fragment must be a map of projects")
+	}
+	var errs Errors
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if key := doc.Content[i]; key.Value != "projects" {
+			errs = append(errs, s.errorf(key, "unknown field %q in fragment", key.Value))
+		}
+	}
+	projects := mappingValue(doc, "projects")
+	if projects == nil {
+		return &yaml.Node{Kind: yaml.SequenceNode}, errs.err()
+	}
+	if projects.Kind != yaml.SequenceNode {
+		return nil, append(errs, s.errorf(projects, "projects must be a list"))
+	}
+	errs = append(errs, s.checkFields(projects, reflect.TypeOf([]*Project{}))...)
+	errs = append(errs, s.checkFragmentPermissions(projects)...)
+	errs = append(errs, s.expandEnv(projects)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	fragmentDir := path.Dir(file)
+	for _, project := range projects.Content {
+		if project.Kind != yaml.MappingNode {
+			continue
+		}
+		i := mappingIndex(project, "dir")
+		if i < 0 {
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "dir"}
+			value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fragmentDir, Line: project.Line, Column: project.Column}
+			s.addFile(key, file)
+			s.addFile(value, file)
+			project.Content = append(project.Content, key, value)
+			continue
+		}
+		dir := project.Content[i+1]
+		if dir.Kind == yaml.ScalarNode {
+			dir.Value = path.Join(fragmentDir, dir.Value)
+		}
+	}
+	return projects, nil
+}
+
+// checkFragmentPermissions rejects the permissions of the projects in the fragment, including those of the templates
+// they extend. The permissions are enforced from the config file of the base branch, which is read without the fragments.
+func (s *source) checkFragmentPermissions(projects *yaml.Node) Errors {
+	templates := mappingValue(s.doc, "templates")
+	var errs Errors
+	for _, project := range projects.Content {
+		if permissions := mappingValue(project, "permissions"); permissions != nil {
+			errs = append(errs, s.errorf(permissions, "permissions cannot be set in a fragment"))
+		}
+		extends := mappingValue(project, "extends")
+		if extends == nil {
+			continue
+		}
+		var names Extends
+		if err := extends.Decode(&names); err != nil {
+			continue
+		}
+		for _, name := range names {
+			if mappingValue(mappingValue(templates, name), "permissions") != nil {
+				errs = append(errs, s.errorf(extends, "template %q with permissions cannot be extended in a fragment", name))
+			}
+		}
+	}
+	return errs
+}
+
+// checkDuplicatedProjects records the projects by the name, and rejects the name which is already recorded.
+func (s *source) checkDuplicatedProjects(defined map[string]*yaml.Node, projects *yaml.Node) error {
+	var errs Errors
+	for _, project := range projects.Content {
+		name := mappingValue(project, "name")
+		if name == nil || name.Value == "" {
+			continue
+		}
+		if first, ok := defined[name.Value]; ok {
+			errs = append(errs, s.errorf(project, "project %q is already defined at %s", name.Value, s.position(first)))
+			continue
+		}
+		defined[name.Value] = project
+	}
+	return errs.err()
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// appendedKeys are the lists which are appended to the inherited ones instead of replacing them.
+var appendedKeys = map[string]bool{
+	"vars":      true,
+	"var_files": true,
+}
+
+// Extends are the names of the templates which the project extends, written as a name or a list of the names.
+type Extends []string
+
+func (e *Extends) UnmarshalYAML(unmarshal func(any) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*e = Extends{name}
+		return nil
+	}
+	var names []string
+	if err := unmarshal(&names); err != nil {
+		return err
+	}
+	*e = names
+	return nil
+}
+
+// mergeProjects merges the defaults and the templates which the projects extend into the projects,
+// and into the project templates of terragrunt.discover, before the document is decoded.
+// The defaults are merged first, then the templates in the order of extends, then the project itself.
+func (s *source) mergeProjects() error {
+	doc := s.doc
+	if doc == nil || doc.Kind != yaml.MappingNode {
+		return nil
+	}
+	defaults := mappingValue(doc, "defaults")
+	templates := mappingValue(doc, "templates")
+	if templates != nil {
+		var errs Errors
+		for i := 0; i+1 < len(templates.Content); i += 2 {
+			if extends := mappingValue(templates.Content[i+1], "extends"); extends != nil {
+				errs = append(errs, s.errorf(extends, "template %q cannot extend templates", templates.Content[i].Value))
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+
+	merge := func(project *yaml.Node) (*yaml.Node, error) {
+		merged := defaults
+		var names Extends
+		if extends := mappingValue(project, "extends"); extends != nil {
+			if err := extends.Decode(&names); err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				template := mappingValue(templates, name)
+				if template == nil {
+					return nil, s.errorf(extends, "template %q is not found", name)
+				}
+				merged = s.mergeNode(merged, template)
+			}
+		}
+		return s.mergeNode(merged, project), nil
+	}
+
+	if projects := mappingValue(doc, "projects"); projects != nil && projects.Kind == yaml.SequenceNode {
+		for i, project := range projects.Content {
+			merged, err := merge(project)
+			if err != nil {
+				return err
+			}
+			projects.Content[i] = merged
+		}
+	}
+	discovers := mappingValue(mappingValue(doc, "terragrunt"), "discover")
+	if discovers == nil || discovers.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, discover := range discovers.Content {
+		if discover.Kind != yaml.MappingNode {
+			continue
+		}
+		project := mappingValue(discover, "project")
+		if project == nil {
+			if defaults == nil {
+				continue
+			}
+			discover.Content = append(discover.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "project"}, defaults)
+			continue
+		}
+		merged, err := merge(project)
+		if err != nil {
+			return err
+		}
+		discover.Content[mappingIndex(discover, "project")+1] = merged
+	}
+	return nil
+}
+
+// mergeNode returns src merged into dst without modifying them.
+// The mappings are merged by the key, the lists of appendedKeys are appended,
+// and the other values, including the other lists, are replaced by src.
+// The merged nodes are at the position of src, so that the problems are reported where the project is written.
+func (s *source) mergeNode(dst, src *yaml.Node) *yaml.Node {
+	if dst == nil {
+		return src
+	}
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return src
+	}
+	merged := *dst
+	merged.Content = slices.Clone(dst.Content)
+	merged.Line, merged.Column = src.Line, src.Column
+	if file, ok := s.files[src]; ok {
+		s.files[&merged] = file
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		j := mappingIndex(&merged, key.Value)
+		if j < 0 {
+			merged.Content = append(merged.Content, key, value)
+			continue
+		}
+		current := merged.Content[j+1]
+		if appendedKeys[key.Value] && current.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode {
+			list := *value
+			list.Content = append(slices.Clone(current.Content), value.Content...)
+			if file, ok := s.files[value]; ok {
+				s.files[&list] = file
+			}
+			merged.Content[j+1] = &list
+			continue
+		}
+		merged.Content[j+1] = s.mergeNode(current, value)
+	}
+	return &merged
+}
+
+// mappingIndex returns the index of the key in the content of the mapping node, or -1 if the node has no key.
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value of the key in the mapping node, or nil if the node is not a mapping or has no key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	i := mappingIndex(node, key)
+	if i < 0 {
+		return nil
+	}
+	return node.Content[i+1]
+}
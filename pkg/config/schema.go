@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ../.. config schema -o ../../mu.schema.json
+
+// JSONSchema returns the JSON Schema of the config file, which editors use to complete and check mu.yaml.
+// It is generated from the yaml and validate tags of Config.
+// The required fields are not in the schema, since they may be given by the defaults, the templates or the fragments.
+func JSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: make(map[string]any)}
+	root := g.object(reflect.TypeOf(Config{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "mu"
+	root["$defs"] = g.defs
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// schema returns the schema of the type, whose structs are referenced from $defs.
+// The tag is the validate tag of the field, whose oneof becomes the enum.
+func (g *schemaGenerator) schema(typ reflect.Type, tag string) map[string]any {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	// The types with UnmarshalYAML accept the other forms than their Go types.
+	switch typ {
+	case reflect.TypeOf(Extends{}):
+		return map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+		}
+	case reflect.TypeOf(Workspaces{}):
+		workspace := g.ref(reflect.TypeOf(Workspace{}))
+		return map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				map[string]any{
+					"type":                 "object",
+					"additionalProperties": map[string]any{"anyOf": []any{workspace, map[string]any{"type": "null"}}},
+				},
+			},
+		}
+	case reflect.TypeOf(Step{}):
+		return map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string", "enum": []any{StepInit, StepPlan, StepApply}},
+				g.ref(typ),
+			},
+		}
+	}
+
+	var schema map[string]any
+	switch typ.Kind() {
+	case reflect.Struct:
+		return g.ref(typ)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": g.schema(typ.Elem(), diveTag(tag))}
+	case reflect.Map:
+		schema = map[string]any{"type": "object", "additionalProperties": g.schema(typ.Elem(), diveTag(tag))}
+		if keys := tagOneOf(keysTag(tag)); len(keys) > 0 {
+			schema["propertyNames"] = map[string]any{"enum": keys}
+		}
+		return schema
+	case reflect.String:
+		schema = map[string]any{"type": "string"}
+	case reflect.Bool:
+		schema = map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema = map[string]any{"type": "integer"}
+	default:
+		schema = map[string]any{}
+	}
+	if values := tagOneOf(tag); len(values) > 0 {
+		enum := make([]any, 0, len(values))
+		for _, value := range values {
+			if n, err := strconv.Atoi(value); err == nil && schema["type"] == "integer" {
+				enum = append(enum, n)
+				continue
+			}
+			enum = append(enum, value)
+		}
+		schema["enum"] = enum
+	}
+	return schema
+}
+
+// ref adds the schema of the struct to $defs, and returns the reference to it.
+func (g *schemaGenerator) ref(typ reflect.Type) map[string]any {
+	name := typ.Name()
+	if _, ok := g.defs[name]; !ok {
+		// The placeholder stops the recursion of the types which refer to themselves.
+		g.defs[name] = nil
+		g.defs[name] = g.object(typ)
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// object returns the schema of the struct, which rejects the unknown fields as Load does.
+func (g *schemaGenerator) object(typ reflect.Type) map[string]any {
+	fields := yamlFields(typ)
+	properties := make(map[string]any, len(fields))
+	for key, field := range fields {
+		properties[key] = g.schema(field.Type, field.Tag.Get("validate"))
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// tagOneOf returns the values of oneof in the validate tag before dive, which applies to the field itself.
+func tagOneOf(tag string) []string {
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			return nil
+		}
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			return strings.Fields(values)
+		}
+	}
+	return nil
+}
+
+// diveTag returns the rules after dive in the validate tag, which apply to the items, without the rules of the keys.
+func diveTag(tag string) string {
+	_, rules, ok := strings.Cut(tag, "dive")
+	if !ok {
+		return ""
+	}
+	if _, after, ok := strings.Cut(rules, "endkeys"); ok {
+		rules = after
+	}
+	return strings.Trim(rules, ",")
+}
+
+// keysTag returns the rules of the keys of the map in the validate tag.
+func keysTag(tag string) string {
+	_, rules, ok := strings.Cut(tag, "keys,")
+	if !ok {
+		return ""
+	}
+	rules, _, _ = strings.Cut(rules, ",endkeys")
+	return rules
+}
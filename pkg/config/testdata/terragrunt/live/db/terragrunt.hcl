@@ -0,0 +1,3 @@
+dependencies {
+  paths = ["../vpc"]
+}
@@ -0,0 +1,105 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"os"
+)
+
+const (
+	saltSize   = 16
+	keySize    = 32
+	iterations = 600_000
+)
+
+// aesGCM encrypts the files with AES-256-GCM.
+// The key is derived from the passphrase with PBKDF2-HMAC-SHA256 and a random salt per file.
+// The encrypted file is laid out as magic | salt | nonce | ciphertext.
+type aesGCM struct {
+	passphrase string
+}
+
+func NewAESGCM(passphrase string) Encrypter {
+	return &aesGCM{
+		passphrase: passphrase,
+	}
+}
+
+func (a *aesGCM) Encrypt(path string) error {
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := a.aead(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(magic)+len(salt)+len(nonce)+len(plaintext)+aead.Overhead()))
+	buf.Write(magic)
+	buf.Write(salt)
+	buf.Write(nonce)
+	buf.Write(aead.Seal(nil, nonce, plaintext, magic))
+	return a.writeFile(path, buf.Bytes())
+}
+
+func (a *aesGCM) Decrypt(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(data, magic) {
+		return ErrNotEncrypted
+	}
+	data = data[len(magic):]
+	if len(data) < saltSize {
+		return fmt.Errorf("%w: file is too short", ErrDecrypt)
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+	aead, err := a.aead(salt)
+	if err != nil {
+		return err
+	}
+	if len(data) < aead.NonceSize() {
+		return fmt.Errorf("%w: file is too short", ErrDecrypt)
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, magic)
+	if err != nil {
+		return fmt.Errorf("%w: wrong key or corrupted file", ErrDecrypt)
+	}
+	return a.writeFile(path, plaintext)
+}
+
+func (a *aesGCM) aead(salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, a.passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (a *aesGCM) writeFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, info.Mode().Perm())
+}
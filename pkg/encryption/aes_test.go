@@ -0,0 +1,82 @@
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.tfplan")
+	require.NoError(t, os.WriteFile(path, []byte("plan data"), 0600))
+
+	enc := NewAESGCM("passphrase")
+	require.NoError(t, enc.Encrypt(path))
+
+	encrypted, err := IsEncrypted(path)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "plan data")
+
+	require.NoError(t, enc.Decrypt(path))
+	encrypted, err = IsEncrypted(path)
+	require.NoError(t, err)
+	assert.False(t, encrypted)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "plan data", string(data))
+}
+
+func TestAESGCM_Decrypt(t *testing.T) {
+	tests := []struct {
+		name      string
+		prepare   func(t *testing.T, path string)
+		expectErr error
+	}{
+		{
+			name: "wrong passphrase",
+			prepare: func(t *testing.T, path string) {
+				require.NoError(t, NewAESGCM("other").Encrypt(path))
+			},
+			expectErr: ErrDecrypt,
+		},
+		{
+			name: "tampered",
+			prepare: func(t *testing.T, path string) {
+				require.NoError(t, NewAESGCM("passphrase").Encrypt(path))
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				data[len(data)-1] ^= 0xff
+				require.NoError(t, os.WriteFile(path, data, 0600))
+			},
+			expectErr: ErrDecrypt,
+		},
+		{
+			name: "too short",
+			prepare: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, append([]byte("MUENC1"), 0x00), 0600))
+			},
+			expectErr: ErrDecrypt,
+		},
+		{
+			name:      "not encrypted",
+			prepare:   func(t *testing.T, path string) {},
+			expectErr: ErrNotEncrypted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.tfplan")
+			require.NoError(t, os.WriteFile(path, []byte("plan data"), 0600))
+			tt.prepare(t, path)
+
+			err := NewAESGCM("passphrase").Decrypt(path)
+			assert.ErrorIs(t, err, tt.expectErr)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package encryption
+
+//go:generate mkdir -p mock
+//go:generate mockgen -source=encryption.go -package=mock -destination=mock/mock.go Encrypter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	ErrNotEncrypted = errors.New("file is not encrypted")
+	ErrDecrypt      = errors.New("failed to decrypt")
+)
+
+// magic is the header of the encrypted files.
+var magic = []byte("MUENC1")
+
+type Encrypter interface {
+	// Encrypt encrypts the file at path in place.
+	Encrypt(path string) error
+	// Decrypt decrypts the file at path in place.
+	Decrypt(path string) error
+}
+
+// IsEncrypted reports whether the file at path is encrypted by mu.
+func IsEncrypted(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(header, magic), nil
+}
@@ -0,0 +1,213 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const defaultAPIURL = "https://api.github.com"
+
+var errInvalidPrivateKey = errors.New("invalid private key")
+
+type AppParams struct {
+	AppID int64
+	// PrivateKey is the PEM encoded private key of the GitHub App.
+	PrivateKey []byte
+	// InstallationID is looked up from the repository if it is 0.
+	InstallationID int64
+	Owner          string
+	Repo           string
+	// APIURL is the URL of the REST API. https://api.github.com is used if it is empty.
+	APIURL     string
+	HTTPClient *http.Client
+}
+
+// AppTokenSource mints the installation access tokens of a GitHub App.
+// The tokens expire in an hour, so the token source mints a new one a few minutes before the expiry,
+// which keeps a long terraform apply authenticated.
+type AppTokenSource struct {
+	appID          int64
+	key            *rsa.PrivateKey
+	installationID int64
+	owner, repo    string
+	apiURL         string
+	cli            *http.Client
+	now            func() time.Time
+	locker         sync.Mutex
+}
+
+func NewAppTokenSource(params *AppParams) (*AppTokenSource, error) {
+	key, err := parsePrivateKey(params.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	apiURL := params.APIURL
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	cli := params.HTTPClient
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	return &AppTokenSource{
+		appID:          params.AppID,
+		key:            key,
+		installationID: params.InstallationID,
+		owner:          params.Owner,
+		repo:           params.Repo,
+		apiURL:         strings.TrimSuffix(apiURL, "/"),
+		cli:            cli,
+		now:            time.Now,
+	}, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errInvalidPrivateKey
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPrivateKey, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not RSA", errInvalidPrivateKey)
+	}
+	return rsaKey, nil
+}
+
+// TokenSource returns the token source which reuses the installation access token until 5 minutes before the expiry.
+func (s *AppTokenSource) TokenSource(ctx context.Context) oauth2.TokenSource {
+	const earlyExpiry = 5 * time.Minute
+	return oauth2.ReuseTokenSourceWithExpiry(nil, &appTokenSource{ctx: ctx, src: s}, earlyExpiry)
+}
+
+type appTokenSource struct {
+	ctx context.Context
+	src *AppTokenSource
+}
+
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	return s.src.Token(s.ctx)
+}
+
+// Token mints a new installation access token.
+func (s *AppTokenSource) Token(ctx context.Context) (*oauth2.Token, error) {
+	installationID, err := s.getInstallationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ret struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	path := fmt.Sprintf("/app/installations/%d/access_tokens", installationID)
+	if err := s.do(ctx, http.MethodPost, path, http.StatusCreated, &ret); err != nil {
+		return nil, fmt.Errorf("failed to create installation access token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: ret.Token,
+		Expiry:      ret.ExpiresAt,
+	}, nil
+}
+
+// BotName returns the login of the bot user which posts the comments as the GitHub App.
+func (s *AppTokenSource) BotName(ctx context.Context) (string, error) {
+	var ret struct {
+		Slug string `json:"slug"`
+	}
+	if err := s.do(ctx, http.MethodGet, "/app", http.StatusOK, &ret); err != nil {
+		return "", fmt.Errorf("failed to get app: %w", err)
+	}
+	return ret.Slug + "[bot]", nil
+}
+
+func (s *AppTokenSource) getInstallationID(ctx context.Context) (int64, error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if s.installationID != 0 {
+		return s.installationID, nil
+	}
+	var ret struct {
+		ID int64 `json:"id"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/installation", s.owner, s.repo)
+	if err := s.do(ctx, http.MethodGet, path, http.StatusOK, &ret); err != nil {
+		return 0, fmt.Errorf("failed to get installation: %w", err)
+	}
+	s.installationID = ret.ID
+	return s.installationID, nil
+}
+
+func (s *AppTokenSource) do(ctx context.Context, method, path string, expectedStatus int, v any) error {
+	jwt, err := s.jwt()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != expectedStatus {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %s %s", errUnexpectedStatus, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwt returns the JSON Web Token to authenticate as the GitHub App.
+// See: https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func (s *AppTokenSource) jwt() (string, error) {
+	now := s.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		// Issued 60 seconds in the past to allow for clock drift.
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + encoding.EncodeToString(sig), nil
+}
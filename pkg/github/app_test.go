@@ -0,0 +1,153 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAppServer(t *testing.T, key *rsa.PrivateKey, expiresIn time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var minted atomic.Int32
+	verify := func(w http.ResponseWriter, r *http.Request) bool {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(token, ".")
+		if !ok || len(parts) != 3 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(claims), `"iss":"123"`)
+		return true
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+		if verify(w, r) {
+			_, _ = w.Write([]byte(`{"slug":"mu-app"}`))
+		}
+	})
+	mux.HandleFunc("GET /repos/owner/repo/installation", func(w http.ResponseWriter, r *http.Request) {
+		if verify(w, r) {
+			_, _ = w.Write([]byte(`{"id":456}`))
+		}
+	})
+	mux.HandleFunc("POST /app/installations/456/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		if !verify(w, r) {
+			return
+		}
+		n := minted.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      fmt.Sprintf("ghs_%d", n),
+			"expires_at": time.Now().Add(expiresIn).UTC().Format(time.RFC3339),
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &minted
+}
+
+func newTestPrivateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	return key, data
+}
+
+func TestAppTokenSource(t *testing.T) {
+	ctx := context.Background()
+	key, pemKey := newTestPrivateKey(t)
+
+	t.Run("reuse token until expiry", func(t *testing.T) {
+		srv, minted := newTestAppServer(t, key, time.Hour)
+		src, err := NewAppTokenSource(&AppParams{
+			AppID:      123,
+			PrivateKey: pemKey,
+			Owner:      "owner",
+			Repo:       "repo",
+			APIURL:     srv.URL,
+		})
+		require.NoError(t, err)
+		ts := src.TokenSource(ctx)
+		for range 3 {
+			token, err := ts.Token()
+			require.NoError(t, err)
+			assert.Equal(t, "ghs_1", token.AccessToken)
+		}
+		assert.Equal(t, int32(1), minted.Load())
+
+		botName, err := src.BotName(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "mu-app[bot]", botName)
+	})
+
+	t.Run("refresh token before expiry", func(t *testing.T) {
+		srv, minted := newTestAppServer(t, key, 3*time.Minute)
+		src, err := NewAppTokenSource(&AppParams{
+			AppID:      123,
+			PrivateKey: pemKey,
+			Owner:      "owner",
+			Repo:       "repo",
+			APIURL:     srv.URL,
+		})
+		require.NoError(t, err)
+		ts := src.TokenSource(ctx)
+		first, err := ts.Token()
+		require.NoError(t, err)
+		second, err := ts.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "ghs_1", first.AccessToken)
+		assert.Equal(t, "ghs_2", second.AccessToken)
+		assert.Equal(t, int32(2), minted.Load())
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		otherKey, _ := newTestPrivateKey(t)
+		srv, _ := newTestAppServer(t, otherKey, time.Hour)
+		src, err := NewAppTokenSource(&AppParams{
+			AppID:          123,
+			PrivateKey:     pemKey,
+			InstallationID: 456,
+			APIURL:         srv.URL,
+		})
+		require.NoError(t, err)
+		_, err = src.Token(ctx)
+		require.ErrorIs(t, err, errUnexpectedStatus)
+	})
+
+	t.Run("invalid private key", func(t *testing.T) {
+		_, err := NewAppTokenSource(&AppParams{
+			AppID:      123,
+			PrivateKey: []byte("invalid"),
+		})
+		require.ErrorIs(t, err, errInvalidPrivateKey)
+	})
+}
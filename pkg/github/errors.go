@@ -5,12 +5,16 @@ import (
 	"net/http"
 
 	githubv3 "github.com/google/go-github/v69/github"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 var (
-	ErrNotFound             = errors.New("not found")
+	ErrNotFound             = vcs.ErrNotFound
+	ErrOutdated             = vcs.ErrOutdated
 	errUnexpectedStatus     = errors.New("unexpected status")
-	errUnsupportedEventType = errors.New("unsupported event type")
+	errNotMerged            = errors.New("not merged")
+	ErrUnsupportedEventType = errors.New("unsupported event type")
 )
 
 func IsErrAlreadyExists(err error) bool {
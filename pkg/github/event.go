@@ -2,9 +2,13 @@ package github
 
 import (
 	"encoding/json"
+	"io"
 	"os"
+	"strings"
 
 	githubv3 "github.com/google/go-github/v69/github"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 const (
@@ -15,6 +19,11 @@ const (
 	Created     = "created"
 )
 
+// Event is the payload of the event of GitHub. Use ConvertEvent to handle it in mu.
+type Event interface {
+	Number() int
+}
+
 type IssueCommentEvent struct {
 	githubv3.IssueCommentEvent
 }
@@ -23,6 +32,42 @@ func (e *IssueCommentEvent) Number() int {
 	return e.GetIssue().GetNumber()
 }
 
+// ScheduleEvent is triggered by a scheduled workflow.
+// It is not related to any pull request, so Number always returns 0.
+type ScheduleEvent struct {
+	Schedule string `json:"schedule"`
+}
+
+func (e *ScheduleEvent) Number() int {
+	return 0
+}
+
+// WorkflowDispatchEvent is triggered by a manually run workflow.
+// It is not related to any pull request, so Number always returns 0.
+type WorkflowDispatchEvent struct {
+	githubv3.WorkflowDispatchEvent
+}
+
+func (e *WorkflowDispatchEvent) Number() int {
+	return 0
+}
+
+// PushEvent is triggered when commits are pushed to a branch.
+// It is not related to any pull request, so Number always returns 0.
+// Use FindMergedPullRequest to look up the pull request that was merged.
+type PushEvent struct {
+	githubv3.PushEvent
+}
+
+func (e *PushEvent) Number() int {
+	return 0
+}
+
+// IsDefaultBranch reports whether the push targets the default branch of the repository.
+func (e *PushEvent) IsDefaultBranch() bool {
+	return e.GetRef() == "refs/heads/"+e.GetRepo().GetDefaultBranch()
+}
+
 type PullRequestEvent struct {
 	githubv3.PullRequestEvent
 }
@@ -31,25 +76,42 @@ func (e *PullRequestEvent) Number() int {
 	return e.GetNumber()
 }
 
-func (g *github) Event() (Event, error) {
+// PullRequestReviewEvent is triggered when a review of a pull request is submitted, edited or dismissed.
+type PullRequestReviewEvent struct {
+	githubv3.PullRequestReviewEvent
+}
+
+func (e *PullRequestReviewEvent) Number() int {
+	return e.GetPullRequest().GetNumber()
+}
+
+const (
+	EventIssueComment      = "issue_comment"
+	EventPullRequest       = "pull_request"
+	EventPullRequestReview = "pull_request_review"
+	EventSchedule          = "schedule"
+	EventDispatch          = "workflow_dispatch"
+	EventPush              = "push"
+)
+
+func (g *github) Event() (vcs.Event, error) {
+	event, err := ReadEvent()
+	if err != nil {
+		return nil, err
+	}
+	return ConvertEvent(event), nil
+}
+
+// ReadEvent reads the event which triggered the workflow from GITHUB_EVENT_NAME and GITHUB_EVENT_PATH.
+func ReadEvent() (Event, error) {
 	const (
-		githubEventName   = "GITHUB_EVENT_NAME"
-		githubEventPath   = "GITHUB_EVENT_PATH"
-		eventIssueComment = "issue_comment"
-		eventPullRequest  = "pull_request"
+		githubEventName = "GITHUB_EVENT_NAME"
+		githubEventPath = "GITHUB_EVENT_PATH"
 	)
 	eventName := os.Getenv(githubEventName)
 	path := os.Getenv(githubEventPath)
-
-	var event Event
-	switch eventName {
-	case eventIssueComment:
-		event = &IssueCommentEvent{}
-	case eventPullRequest:
-		event = &PullRequestEvent{}
-	}
-	if event == nil {
-		return nil, errUnsupportedEventType
+	if !isSupportedEvent(eventName) {
+		return nil, ErrUnsupportedEventType
 	}
 
 	file, err := os.Open(path)
@@ -59,9 +121,78 @@ func (g *github) Event() (Event, error) {
 	defer func() {
 		_ = file.Close()
 	}()
+	return DecodeEvent(eventName, file)
+}
 
-	if err := json.NewDecoder(file).Decode(event); err != nil {
+// DecodeEvent decodes the payload of the event named name,
+// which is the GITHUB_EVENT_NAME of the workflow or the X-GitHub-Event header of the webhook.
+func DecodeEvent(name string, payload io.Reader) (Event, error) {
+	event := newEvent(name)
+	if event == nil {
+		return nil, ErrUnsupportedEventType
+	}
+	if err := json.NewDecoder(payload).Decode(event); err != nil {
 		return nil, err
 	}
 	return event, nil
 }
+
+func isSupportedEvent(name string) bool {
+	return newEvent(name) != nil
+}
+
+func newEvent(name string) Event {
+	switch name {
+	case EventIssueComment:
+		return &IssueCommentEvent{}
+	case EventPullRequest:
+		return &PullRequestEvent{}
+	case EventPullRequestReview:
+		return &PullRequestReviewEvent{}
+	case EventSchedule:
+		return &ScheduleEvent{}
+	case EventDispatch:
+		return &WorkflowDispatchEvent{}
+	case EventPush:
+		return &PushEvent{}
+	default:
+		return nil
+	}
+}
+
+// ConvertEvent converts the event of GitHub into the event of VCS. It returns nil for the unsupported events.
+func ConvertEvent(event Event) vcs.Event {
+	switch e := event.(type) {
+	case *PullRequestEvent:
+		return &vcs.PullRequestEvent{
+			Action:            e.GetAction(),
+			PullRequestNumber: e.Number(),
+			User:              e.GetSender().GetLogin(),
+		}
+	case *PullRequestReviewEvent:
+		return &vcs.ReviewEvent{
+			Action:            e.GetAction(),
+			PullRequestNumber: e.Number(),
+			State:             strings.ToLower(e.GetReview().GetState()),
+			User:              e.GetReview().GetUser().GetLogin(),
+		}
+	case *IssueCommentEvent:
+		return &vcs.CommentEvent{
+			Action:            e.GetAction(),
+			PullRequestNumber: e.Number(),
+			CommentID:         e.GetComment().GetID(),
+			Body:              e.GetComment().GetBody(),
+			User:              e.GetComment().GetUser().GetLogin(),
+		}
+	case *ScheduleEvent, *WorkflowDispatchEvent:
+		return &vcs.DriftEvent{}
+	case *PushEvent:
+		return &vcs.PushEvent{
+			SHA:           e.GetAfter(),
+			DefaultBranch: e.IsDefaultBranch(),
+			User:          e.GetSender().GetLogin(),
+		}
+	default:
+		return nil
+	}
+}
@@ -1,19 +1,22 @@
 package github
 
 import (
+	"os"
+	"strings"
 	"testing"
 
 	githubv3 "github.com/google/go-github/v69/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
 func TestGithub_Event_IssueCommentEvent(t *testing.T) {
 	t.Setenv("GITHUB_EVENT_NAME", "issue_comment")
 	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_issue_comment.json")
 
-	gh := &github{}
-	event, err := gh.Event()
+	event, err := ReadEvent()
 	require.NoError(t, err)
 	issueCommentEvent, ok := event.(*IssueCommentEvent)
 	require.True(t, ok)
@@ -39,8 +42,7 @@ func TestGithub_Event_PullRequestEvent(t *testing.T) {
 	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
 	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_pull_request.json")
 
-	gh := &github{}
-	event, err := gh.Event()
+	event, err := ReadEvent()
 	require.NoError(t, err)
 	pullRequestEvent, ok := event.(*PullRequestEvent)
 	require.True(t, ok)
@@ -58,12 +60,171 @@ func TestGithub_Event_PullRequestEvent(t *testing.T) {
 	assert.Equal(t, 1, pullRequestEvent.Number())
 }
 
+func TestGithub_Event_PullRequestReviewEvent(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request_review")
+	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_pull_request_review.json")
+
+	event, err := ReadEvent()
+	require.NoError(t, err)
+	reviewEvent, ok := event.(*PullRequestReviewEvent)
+	require.True(t, ok)
+	assert.Equal(t, "submitted", reviewEvent.GetAction())
+	assert.Equal(t, "approved", reviewEvent.GetReview().GetState())
+	assert.Equal(t, 1, reviewEvent.Number())
+}
+
+func TestGithub_Event_ScheduleEvent(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "schedule")
+	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_schedule.json")
+
+	event, err := ReadEvent()
+	require.NoError(t, err)
+	scheduleEvent, ok := event.(*ScheduleEvent)
+	require.True(t, ok)
+	expect := &ScheduleEvent{
+		Schedule: "0 0 * * *",
+	}
+	assert.Equal(t, expect, scheduleEvent)
+	assert.Equal(t, 0, scheduleEvent.Number())
+}
+
+func TestGithub_Event_WorkflowDispatchEvent(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "workflow_dispatch")
+	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_workflow_dispatch.json")
+
+	event, err := ReadEvent()
+	require.NoError(t, err)
+	workflowDispatchEvent, ok := event.(*WorkflowDispatchEvent)
+	require.True(t, ok)
+	assert.Equal(t, "refs/heads/main", workflowDispatchEvent.GetRef())
+	assert.Equal(t, ".github/workflows/mu.yaml", workflowDispatchEvent.GetWorkflow())
+	assert.JSONEq(t, `{"projects": "test"}`, string(workflowDispatchEvent.Inputs))
+	assert.Equal(t, 0, workflowDispatchEvent.Number())
+}
+
+func TestGithub_Event_PushEvent(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "push")
+	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_push.json")
+
+	event, err := ReadEvent()
+	require.NoError(t, err)
+	pushEvent, ok := event.(*PushEvent)
+	require.True(t, ok)
+	assert.Equal(t, "refs/heads/main", pushEvent.GetRef())
+	assert.Equal(t, "0000000000000000000000000000000000000001", pushEvent.GetAfter())
+	assert.True(t, pushEvent.IsDefaultBranch())
+	assert.Equal(t, 0, pushEvent.Number())
+}
+
 func TestGithub_Event_UnknownEvent(t *testing.T) {
 	t.Setenv("GITHUB_EVENT_NAME", "unknown_event")
 	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_unknown.json")
 
+	event, err := ReadEvent()
+	require.ErrorIs(t, err, ErrUnsupportedEventType)
+	assert.Nil(t, event)
+}
+
+func TestDecodeEvent(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/event_pull_request.json")
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	event, err := DecodeEvent(EventPullRequest, file)
+	require.NoError(t, err)
+	prEvent, ok := event.(*PullRequestEvent)
+	require.True(t, ok)
+	assert.Positive(t, prEvent.Number())
+
+	_, err = DecodeEvent("unknown_event", strings.NewReader("{}"))
+	require.ErrorIs(t, err, ErrUnsupportedEventType)
+}
+
+func TestGithub_Event(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "issue_comment")
+	t.Setenv("GITHUB_EVENT_PATH", "./testdata/event_issue_comment.json")
+
 	gh := &github{}
 	event, err := gh.Event()
-	require.ErrorIs(t, err, errUnsupportedEventType)
-	assert.Nil(t, event)
+	require.NoError(t, err)
+	expect := &vcs.CommentEvent{
+		Action:            "created",
+		PullRequestNumber: 1,
+		CommentID:         1,
+		Body:              "mu plan",
+	}
+	assert.Equal(t, expect, event)
+}
+
+func TestConvertEvent(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		event  Event
+		expect vcs.Event
+	}{
+		"pull_request": {
+			event: &PullRequestEvent{
+				PullRequestEvent: githubv3.PullRequestEvent{
+					Action: githubv3.Ptr("synchronize"),
+					Number: githubv3.Ptr(1),
+					Sender: &githubv3.User{Login: githubv3.Ptr("alice")},
+				},
+			},
+			expect: &vcs.PullRequestEvent{
+				Action:            "synchronize",
+				PullRequestNumber: 1,
+				User:              "alice",
+			},
+		},
+		"pull_request_review": {
+			event: &PullRequestReviewEvent{
+				PullRequestReviewEvent: githubv3.PullRequestReviewEvent{
+					Action: githubv3.Ptr("submitted"),
+					Review: &githubv3.PullRequestReview{
+						State: githubv3.Ptr("APPROVED"),
+						User:  &githubv3.User{Login: githubv3.Ptr("alice")},
+					},
+					PullRequest: &githubv3.PullRequest{Number: githubv3.Ptr(1)},
+				},
+			},
+			expect: &vcs.ReviewEvent{
+				Action:            "submitted",
+				PullRequestNumber: 1,
+				State:             "approved",
+				User:              "alice",
+			},
+		},
+		"schedule": {
+			event:  &ScheduleEvent{Schedule: "0 0 * * *"},
+			expect: &vcs.DriftEvent{},
+		},
+		"workflow_dispatch": {
+			event:  &WorkflowDispatchEvent{},
+			expect: &vcs.DriftEvent{},
+		},
+		"push": {
+			event: &PushEvent{
+				PushEvent: githubv3.PushEvent{
+					Ref:   githubv3.Ptr("refs/heads/main"),
+					After: githubv3.Ptr("sha"),
+					Repo: &githubv3.PushEventRepository{
+						DefaultBranch: githubv3.Ptr("main"),
+					},
+				},
+			},
+			expect: &vcs.PushEvent{
+				SHA:           "sha",
+				DefaultBranch: true,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expect, ConvertEvent(tt.event))
+		})
+	}
 }
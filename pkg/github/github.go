@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
 	"slices"
 	"strings"
 	"time"
@@ -16,75 +19,43 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/yu-icchi/mu/pkg/github/sdk"
+	"github.com/yu-icchi/mu/pkg/vcs"
 )
 
-const (
-	MaxCommentLen = 65536
-	ActionBotName = "github-actions"
-)
+// ActionBotName is the login of the bot which posts the comments with the token of GitHub Actions.
+const ActionBotName = "github-actions"
 
 //go:generate mkdir -p mock
 //go:generate mockgen -source=github.go -package=mock -destination=mock/mock.go Github
 
+// Github is the VCS of GitHub, which also keeps the plan files as the artifacts of GitHub Actions.
 type Github interface {
-	CreateIssueComment(ctx context.Context, number int, body string) error
-	HideIssueComment(ctx context.Context, nodeID string) error
-	CreateIssueCommentReaction(ctx context.Context, commentID int64, content string) error
-	CreateLabel(ctx context.Context, name, description, color string) error
-	DeleteLabel(ctx context.Context, label string) error
-	GetLabel(ctx context.Context, label string) (*Label, error)
-	ListReviews(ctx context.Context, number int) (Reviews, error)
-	ListPullRequestComments(ctx context.Context, number int) ([]*Comment, error)
-	ListPullRequestsByLabel(ctx context.Context, label string, limit int) ([]*PullRequest, error)
-	FindPullRequestByLabel(ctx context.Context, label string) (*PullRequest, error)
-	AddPullRequestLabels(ctx context.Context, number int, labels []string) error
-	ListFiles(ctx context.Context, number int) ([]string, error)
-	CreateCommitStatus(ctx context.Context, commitStatus *CommitStatus) error
-	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+	vcs.VCS
 	MultiGetArtifactsByNames(ctx context.Context, names []string) (Artifacts, error)
+	ListArtifactNames(ctx context.Context, prefix string) ([]string, error)
 	DownloadArtifact(ctx context.Context, id int64, file io.Writer) error
 	DeleteArtifactsByNames(ctx context.Context, names []string) error
-	Event() (Event, error)
-}
-
-type Event interface {
-	Number() int
 }
 
-type PullRequest struct {
-	ID             int64
-	Number         int
-	Title          string
-	CreatedAt      time.Time
-	HeadSHA        string
-	MergeableState string
-	Labels         []*Label
-}
-
-// IsMergeable
-// See: https://github.com/octokit/octokit.net/issues/1763
-func (p *PullRequest) IsMergeable() bool {
-	switch p.MergeableState {
-	case "clean", "unstable", "has_hooks":
-		return true
-	default:
-		return false
-	}
-}
-
-func (p *PullRequest) HasLabel(labelName string) bool {
-	for _, label := range p.Labels {
-		if label.Name == labelName {
-			return true
-		}
-	}
-	return false
-}
+type (
+	PullRequest            = vcs.PullRequest
+	Label                  = vcs.Label
+	Issue                  = vcs.Issue
+	Comment                = vcs.Comment
+	Review                 = vcs.Review
+	Reviews                = vcs.Reviews
+	CommitStatus           = vcs.CommitStatus
+	CommitFilesParams      = vcs.CommitFilesParams
+	MergePullRequestParams = vcs.MergePullRequestParams
+	Status                 = vcs.Status
+)
 
-type Label struct {
-	Name        string
-	Description string
-}
+const (
+	ErrorStatus   = vcs.ErrorStatus
+	FailureStatus = vcs.FailureStatus
+	PendingStatus = vcs.PendingStatus
+	SuccessStatus = vcs.SuccessStatus
+)
 
 type IssueComment struct {
 	NodeID string
@@ -95,31 +66,6 @@ type File struct {
 	Status string
 }
 
-type Review struct {
-	UserLogin string
-	State     string
-}
-
-type Reviews []*Review
-
-func (rs Reviews) Approves() int {
-	var num int
-	for _, r := range rs {
-		if strings.ToLower(r.State) == "approve" {
-			num++
-		}
-	}
-	return num
-}
-
-type CommitStatus struct {
-	Sha       string
-	Status    Status
-	TargetURL string
-	Desc      string
-	Context   string
-}
-
 type Artifact struct {
 	ID        int64
 	Name      string
@@ -136,68 +82,137 @@ func (a Artifacts) Get(name string) *Artifact {
 	return artifact
 }
 
-type Status int
-
-const (
-	ErrorStatus = iota
-	FailureStatus
-	PendingStatus
-	SuccessStatus
-)
-
-func (s Status) String() string {
-	switch s {
-	case ErrorStatus:
-		return "error"
-	case FailureStatus:
-		return "failure"
-	case PendingStatus:
-		return "pending"
-	case SuccessStatus:
-		return "success"
-	default:
-		return ""
-	}
-}
-
 type github struct {
 	cli          *http.Client
 	actions      sdk.Actions
 	issues       sdk.Issues
 	pullRequests sdk.PullRequests
 	repositories sdk.Repositories
+	teams        sdk.Teams
 	reactions    sdk.Reactions
+	git          sdk.Git
 	graphQL      sdk.GraphQL
 	owner, repo  string
 }
 
-func New(ctx context.Context, token, owner, repo string) (Github, error) {
-	ratelimitCli, err := githubRatelimit.NewRateLimitWaiterClient(http.DefaultTransport)
+// URLs are the endpoints of GitHub Enterprise Server. The endpoints of github.com are used for the empty fields.
+type URLs struct {
+	// API is the URL of the REST API, e.g. https://ghe.example.com/api/v3.
+	API string
+	// GraphQL is the URL of the GraphQL API, e.g. https://ghe.example.com/api/graphql.
+	GraphQL string
+	// Upload is the URL of the upload API, e.g. https://ghe.example.com/api/uploads.
+	Upload string
+}
+
+// URLsFromEnv returns the endpoints from GITHUB_API_URL, GITHUB_GRAPHQL_URL and GITHUB_SERVER_URL,
+// which are set on the runners.
+func URLsFromEnv() *URLs {
+	urls := &URLs{
+		API:     os.Getenv("GITHUB_API_URL"),
+		GraphQL: os.Getenv("GITHUB_GRAPHQL_URL"),
+	}
+	if serverURL := strings.TrimSuffix(os.Getenv("GITHUB_SERVER_URL"), "/"); serverURL != "" && serverURL != "https://github.com" {
+		urls.Upload = serverURL + "/api/uploads/"
+	}
+	return urls
+}
+
+func (u *URLs) isEnterprise() bool {
+	return u.API != "" && strings.TrimSuffix(u.API, "/") != defaultAPIURL
+}
+
+type options struct {
+	urls *URLs
+}
+
+type Option func(*options)
+
+// WithURLs sets the endpoints of GitHub Enterprise Server.
+func WithURLs(urls *URLs) Option {
+	return func(opts *options) {
+		opts.urls = urls
+	}
+}
+
+// Login returns the login of the user of the token, which posts the comments with it.
+// The installation access tokens of GitHub Apps and GitHub Actions cannot get the user, so their bot names are not resolved by it.
+func Login(ctx context.Context, token string, urls *URLs) (string, error) {
+	cli := githubv3.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	if urls != nil && urls.API != "" {
+		baseURL, err := url.Parse(strings.TrimSuffix(urls.API, "/") + "/")
+		if err != nil {
+			return "", err
+		}
+		cli.BaseURL = baseURL
+	}
+	user, _, err := cli.Users.Get(ctx, "")
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	v3 := githubv3.NewClient(ratelimitCli).WithAuthToken(token)
+	return user.GetLogin(), nil
+}
+
+func New(ctx context.Context, token, owner, repo string, opts ...Option) (Github, error) {
 	src := oauth2.StaticTokenSource(
 		&oauth2.Token{
 			AccessToken: token,
 		},
 	)
+	return NewWithTokenSource(ctx, src, owner, repo, opts...)
+}
+
+// NewWithTokenSource is like New, but the token is taken from src on every request,
+// e.g. the installation access token of a GitHub App which is refreshed before the expiry.
+func NewWithTokenSource(ctx context.Context, src oauth2.TokenSource, owner, repo string, opts ...Option) (Github, error) {
+	o := &options{
+		urls: &URLs{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	ratelimitCli, err := githubRatelimit.NewRateLimitWaiterClient(http.DefaultTransport)
+	if err != nil {
+		return nil, err
+	}
+	v3 := githubv3.NewClient(&http.Client{
+		Transport: &oauth2.Transport{
+			Source: src,
+			Base:   ratelimitCli.Transport,
+		},
+	})
+	if o.urls.isEnterprise() {
+		uploadURL := o.urls.Upload
+		if uploadURL == "" {
+			// go-github appends /api/uploads/ to the host of GitHub Enterprise Server.
+			uploadURL = strings.TrimSuffix(strings.TrimSuffix(o.urls.API, "/"), "/api/v3")
+		}
+		v3, err = v3.WithEnterpriseURLs(o.urls.API, uploadURL)
+		if err != nil {
+			return nil, err
+		}
+	}
 	cli := oauth2.NewClient(ctx, src)
 	v4 := githubv4.NewClient(cli)
+	if o.urls.GraphQL != "" {
+		v4 = githubv4.NewEnterpriseClient(o.urls.GraphQL, cli)
+	}
 	return &github{
 		cli:          new(http.Client),
 		actions:      sdk.NewActions(v3),
 		issues:       sdk.NewIssues(v3),
 		pullRequests: sdk.NewPullRequests(v3),
 		repositories: sdk.NewRepositories(v3),
+		teams:        sdk.NewTeams(v3),
 		reactions:    sdk.NewReactions(v3),
+		git:          sdk.NewGit(v3),
 		graphQL:      sdk.NewGraphQL(v4),
 		owner:        owner,
 		repo:         repo,
 	}, nil
 }
 
-func (g *github) CreateIssueComment(ctx context.Context, number int, body string) error {
+func (g *github) CreateComment(ctx context.Context, number int, body string) error {
 	comment := &githubv3.IssueComment{
 		Body: githubv3.Ptr(body),
 	}
@@ -205,7 +220,7 @@ func (g *github) CreateIssueComment(ctx context.Context, number int, body string
 	return err
 }
 
-func (g *github) HideIssueComment(ctx context.Context, nodeID string) error {
+func (g *github) HideComment(ctx context.Context, nodeID string) error {
 	var mutate struct {
 		MinimizeComment struct {
 			MinimizedComment struct {
@@ -222,7 +237,7 @@ func (g *github) HideIssueComment(ctx context.Context, nodeID string) error {
 	return g.graphQL.Mutate(ctx, &mutate, input, nil)
 }
 
-func (g *github) CreateIssueCommentReaction(ctx context.Context, commentID int64, content string) error {
+func (g *github) CreateCommentReaction(ctx context.Context, commentID int64, content string) error {
 	_, _, err := g.reactions.CreateIssueCommentReaction(ctx, g.owner, g.repo, commentID, content)
 	return err
 }
@@ -236,11 +251,17 @@ func (g *github) CreateLabel(ctx context.Context, name, description, color strin
 		label.Color = githubv3.Ptr(color)
 	}
 	_, _, err := g.issues.CreateLabel(ctx, g.owner, g.repo, label)
+	if IsErrAlreadyExists(err) {
+		return fmt.Errorf("%w: %w", vcs.ErrAlreadyExists, err)
+	}
 	return err
 }
 
 func (g *github) DeleteLabel(ctx context.Context, label string) error {
 	_, err := g.issues.DeleteLabel(ctx, g.owner, g.repo, label)
+	if IsErrNotFound(err) {
+		return fmt.Errorf("%w: %w", vcs.ErrNotFound, err)
+	}
 	return err
 }
 
@@ -255,6 +276,37 @@ func (g *github) GetLabel(ctx context.Context, label string) (*Label, error) {
 	}, nil
 }
 
+func (g *github) GetPermissionLevel(ctx context.Context, user string) (string, error) {
+	level, _, err := g.repositories.GetPermissionLevel(ctx, g.owner, g.repo, user)
+	if err != nil {
+		if IsErrNotFound(err) {
+			return vcs.PermissionNone, nil
+		}
+		return "", err
+	}
+	// role_name is "maintain" or "triage" for the roles which are not in permission, or the name of a custom role.
+	switch role := level.GetRoleName(); role {
+	case vcs.PermissionMaintain, vcs.PermissionTriage:
+		return role, nil
+	}
+	return level.GetPermission(), nil
+}
+
+func (g *github) IsTeamMember(ctx context.Context, team, user string) (bool, error) {
+	org, slug, ok := strings.Cut(team, "/")
+	if !ok {
+		org, slug = g.owner, team
+	}
+	membership, _, err := g.teams.GetTeamMembershipBySlug(ctx, org, slug, user)
+	if err != nil {
+		if IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return membership.GetState() == "active", nil
+}
+
 func (g *github) ListReviews(ctx context.Context, number int) (Reviews, error) {
 	var page int
 	reviews := make(Reviews, 0, 10)
@@ -290,19 +342,7 @@ func (g *github) listReviews(ctx context.Context, number, page int) ([]*githubv3
 	return pullRequestReviews, resp.NextPage, nil
 }
 
-type Comment struct {
-	ID         string
-	DatabaseID int64
-	Body       string
-	Author     struct {
-		Login string
-	}
-	CreatedAt         string
-	IsMinimized       bool
-	ViewerCanMinimize bool
-}
-
-func (g *github) ListPullRequestComments(ctx context.Context, number int) ([]*Comment, error) {
+func (g *github) ListComments(ctx context.Context, number int) ([]*Comment, error) {
 	var q struct {
 		Repository struct {
 			PullRequest struct {
@@ -364,6 +404,10 @@ func (g *github) ListPullRequestsByLabel(ctx context.Context, label string, limi
 					Title:          pullRequest.GetTitle(),
 					CreatedAt:      pullRequest.GetCreatedAt().Time,
 					HeadSHA:        pullRequest.GetHead().GetSHA(),
+					HeadRef:        pullRequest.GetHead().GetRef(),
+					BaseSHA:        pullRequest.GetBase().GetSHA(),
+					BaseRef:        pullRequest.GetBase().GetRef(),
+					Fork:           isFork(pullRequest),
 					MergeableState: pullRequest.GetMergeableState(),
 					Labels:         labels,
 				}
@@ -407,6 +451,10 @@ func (g *github) FindPullRequestByLabel(ctx context.Context, label string) (*Pul
 					Title:          pullRequest.GetTitle(),
 					CreatedAt:      pullRequest.GetCreatedAt().Time,
 					HeadSHA:        pullRequest.GetHead().GetSHA(),
+					HeadRef:        pullRequest.GetHead().GetRef(),
+					BaseSHA:        pullRequest.GetBase().GetSHA(),
+					BaseRef:        pullRequest.GetBase().GetRef(),
+					Fork:           isFork(pullRequest),
 					MergeableState: pullRequest.GetMergeableState(),
 					Labels:         labels,
 				}
@@ -508,6 +556,46 @@ func (g *github) CreateCommitStatus(ctx context.Context, commitStatus *CommitSta
 	return err
 }
 
+func (g *github) ListCommitStatuses(ctx context.Context, sha string) ([]*CommitStatus, error) {
+	opts := &githubv3.ListOptions{
+		PerPage: 100,
+	}
+	var statuses []*CommitStatus
+	for {
+		combined, resp, err := g.repositories.GetCombinedStatus(ctx, g.owner, g.repo, sha, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range combined.Statuses {
+			statuses = append(statuses, &CommitStatus{
+				Sha:       sha,
+				Status:    parseStatus(status.GetState()),
+				TargetURL: status.GetTargetURL(),
+				Desc:      status.GetDescription(),
+				Context:   status.GetContext(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return statuses, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// parseStatus converts the state of the commit status into Status.
+func parseStatus(state string) Status {
+	switch state {
+	case "success":
+		return SuccessStatus
+	case "pending":
+		return PendingStatus
+	case "failure":
+		return FailureStatus
+	default:
+		return ErrorStatus
+	}
+}
+
 func (g *github) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
 	pr, err := g.getPullRequest(ctx, number)
 	if err != nil {
@@ -526,12 +614,69 @@ func (g *github) GetPullRequest(ctx context.Context, number int) (*PullRequest,
 		Title:          pr.GetTitle(),
 		CreatedAt:      pr.GetCreatedAt().Time,
 		HeadSHA:        pr.GetHead().GetSHA(),
+		HeadRef:        pr.GetHead().GetRef(),
+		BaseSHA:        pr.GetBase().GetSHA(),
+		BaseRef:        pr.GetBase().GetRef(),
+		Fork:           isFork(pr),
 		MergeableState: pr.GetMergeableState(),
 		Labels:         labels,
 	}
 	return pullRequest, nil
 }
 
+// isFork reports whether the head of the pull request is in another repository than the base.
+// The head repository is missing when the fork is deleted, which is regarded as a fork.
+func isFork(pr *githubv3.PullRequest) bool {
+	return pr.GetHead().GetRepo().GetID() != pr.GetBase().GetRepo().GetID()
+}
+
+// FindMergedPullRequest returns the merged pull request associated with the commit.
+// It returns ErrNotFound when the commit was pushed directly without a pull request.
+func (g *github) FindMergedPullRequest(ctx context.Context, sha string) (*PullRequest, error) {
+	var page int
+	for {
+		opts := &githubv3.ListOptions{
+			Page:    page,
+			PerPage: 100,
+		}
+		pullRequests, resp, err := g.pullRequests.ListPullRequestsWithCommit(ctx, g.owner, g.repo, sha, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, pullRequest := range pullRequests {
+			if pullRequest.MergedAt == nil {
+				continue
+			}
+			labels := make([]*Label, len(pullRequest.Labels))
+			for i, l := range pullRequest.Labels {
+				labels[i] = &Label{
+					Name:        l.GetName(),
+					Description: l.GetDescription(),
+				}
+			}
+			pr := &PullRequest{
+				ID:             pullRequest.GetID(),
+				Number:         pullRequest.GetNumber(),
+				Title:          pullRequest.GetTitle(),
+				CreatedAt:      pullRequest.GetCreatedAt().Time,
+				HeadSHA:        pullRequest.GetHead().GetSHA(),
+				HeadRef:        pullRequest.GetHead().GetRef(),
+				BaseSHA:        pullRequest.GetBase().GetSHA(),
+				BaseRef:        pullRequest.GetBase().GetRef(),
+				Fork:           isFork(pullRequest),
+				MergeableState: pullRequest.GetMergeableState(),
+				Labels:         labels,
+			}
+			return pr, nil
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return nil, ErrNotFound
+}
+
 func (g *github) getPullRequest(ctx context.Context, number int) (*githubv3.PullRequest, error) {
 	operation := func() (*githubv3.PullRequest, error) {
 		pr, _, err := g.pullRequests.Get(ctx, g.owner, g.repo, number)
@@ -589,6 +734,30 @@ func (g *github) MultiGetArtifactsByNames(ctx context.Context, names []string) (
 	return ret, nil
 }
 
+// ListArtifactNames returns the unique names of the artifacts which start with prefix.
+func (g *github) ListArtifactNames(ctx context.Context, prefix string) ([]string, error) {
+	var page int
+	names := make([]string, 0)
+	for {
+		artifacts, nextPage, err := g.listArtifacts(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, artifact := range artifacts {
+			name := artifact.GetName()
+			if !strings.HasPrefix(name, prefix) || slices.Contains(names, name) {
+				continue
+			}
+			names = append(names, name)
+		}
+		if nextPage == 0 {
+			break
+		}
+		page = nextPage
+	}
+	return names, nil
+}
+
 func (g *github) listArtifacts(ctx context.Context, page int) ([]*githubv3.Artifact, int, error) {
 	opts := &githubv3.ListArtifactsOptions{
 		ListOptions: githubv3.ListOptions{
@@ -650,3 +819,188 @@ func (g *github) DeleteArtifactsByNames(ctx context.Context, names []string) err
 	}
 	return nil
 }
+
+func (g *github) CommitFiles(ctx context.Context, params *CommitFilesParams) (string, error) {
+	ref, _, err := g.git.GetRef(ctx, g.owner, g.repo, "heads/"+params.Branch)
+	if err != nil {
+		return "", err
+	}
+	parentSHA := ref.GetObject().GetSHA()
+	if params.HeadSHA != "" && parentSHA != params.HeadSHA {
+		return "", fmt.Errorf("%w: %s is at %s, not %s", ErrOutdated, params.Branch, parentSHA, params.HeadSHA)
+	}
+	parent, _, err := g.git.GetCommit(ctx, g.owner, g.repo, parentSHA)
+	if err != nil {
+		return "", err
+	}
+
+	paths := make([]string, 0, len(params.Files))
+	for path := range params.Files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	entries := make([]*githubv3.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, &githubv3.TreeEntry{
+			Path:    githubv3.Ptr(path),
+			Mode:    githubv3.Ptr("100644"),
+			Type:    githubv3.Ptr("blob"),
+			Content: githubv3.Ptr(params.Files[path]),
+		})
+	}
+	tree, _, err := g.git.CreateTree(ctx, g.owner, g.repo, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return "", err
+	}
+
+	commit := &githubv3.Commit{
+		Message: githubv3.Ptr(params.Message),
+		Tree:    tree,
+		Parents: []*githubv3.Commit{
+			{SHA: githubv3.Ptr(parentSHA)},
+		},
+	}
+	newCommit, _, err := g.git.CreateCommit(ctx, g.owner, g.repo, commit, nil)
+	if err != nil {
+		return "", err
+	}
+
+	newRef := &githubv3.Reference{
+		Ref: githubv3.Ptr("refs/heads/" + params.Branch),
+		Object: &githubv3.GitObject{
+			SHA: newCommit.SHA,
+		},
+	}
+	if _, _, err := g.git.UpdateRef(ctx, g.owner, g.repo, newRef, false); err != nil {
+		return "", err
+	}
+	return newCommit.GetSHA(), nil
+}
+
+func (g *github) GetFileContent(ctx context.Context, path, ref string) ([]byte, error) {
+	opts := &githubv3.RepositoryContentGetOptions{Ref: ref}
+	file, _, _, err := g.repositories.GetContents(ctx, g.owner, g.repo, path, opts)
+	if err != nil {
+		if IsErrNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%w: %s is not a file", ErrNotFound, path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+func (g *github) ListRepositoryFiles(ctx context.Context, dir, ref string) ([]string, error) {
+	tree, _, err := g.git.GetTree(ctx, g.owner, g.repo, ref, true)
+	if err != nil {
+		return nil, err
+	}
+	if tree.GetTruncated() {
+		return nil, fmt.Errorf("the tree of %s is too large to list", ref)
+	}
+	prefix := path.Clean(dir) + "/"
+	var files []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "blob" {
+			continue
+		}
+		if prefix != "./" && !strings.HasPrefix(entry.GetPath(), prefix) {
+			continue
+		}
+		files = append(files, entry.GetPath())
+	}
+	return files, nil
+}
+
+func (g *github) MergePullRequest(ctx context.Context, params *MergePullRequestParams) error {
+	opts := &githubv3.PullRequestOptions{
+		SHA:         params.SHA,
+		MergeMethod: params.Method,
+	}
+	ret, _, err := g.pullRequests.Merge(ctx, g.owner, g.repo, params.Number, "", opts)
+	if err != nil {
+		return err
+	}
+	if !ret.GetMerged() {
+		return fmt.Errorf("%w: %s", errNotMerged, ret.GetMessage())
+	}
+	if !params.DeleteBranch || params.Branch == "" {
+		return nil
+	}
+	if _, err := g.git.DeleteRef(ctx, g.owner, g.repo, "heads/"+params.Branch); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *github) FindIssueByLabel(ctx context.Context, label string) (*Issue, error) {
+	opts := &githubv3.IssueListByRepoOptions{
+		State:  "open",
+		Labels: []string{label},
+		ListOptions: githubv3.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		issues, resp, err := g.issues.ListByRepo(ctx, g.owner, g.repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			// The issues API also returns pull requests.
+			if issue.IsPullRequest() {
+				continue
+			}
+			return &Issue{
+				Number: issue.GetNumber(),
+				Title:  issue.GetTitle(),
+				Body:   issue.GetBody(),
+			}, nil
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return nil, ErrNotFound
+}
+
+func (g *github) CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error) {
+	req := &githubv3.IssueRequest{
+		Title:  githubv3.Ptr(title),
+		Body:   githubv3.Ptr(body),
+		Labels: &labels,
+	}
+	issue, _, err := g.issues.Create(ctx, g.owner, g.repo, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Issue{
+		Number: issue.GetNumber(),
+		Title:  issue.GetTitle(),
+		Body:   issue.GetBody(),
+	}, nil
+}
+
+func (g *github) UpdateIssueBody(ctx context.Context, number int, body string) error {
+	req := &githubv3.IssueRequest{
+		Body: githubv3.Ptr(body),
+	}
+	_, _, err := g.issues.Edit(ctx, g.owner, g.repo, number, req)
+	return err
+}
+
+func (g *github) CloseIssue(ctx context.Context, number int) error {
+	req := &githubv3.IssueRequest{
+		State:       githubv3.Ptr("closed"),
+		StateReason: githubv3.Ptr("completed"),
+	}
+	_, _, err := g.issues.Edit(ctx, g.owner, g.repo, number, req)
+	return err
+}
@@ -24,7 +24,9 @@ type mock struct {
 	issues            *sdkmock.MockIssues
 	pullRequest       *sdkmock.MockPullRequests
 	repositories      *sdkmock.MockRepositories
+	teams             *sdkmock.MockTeams
 	reactions         *sdkmock.MockReactions
+	git               *sdkmock.MockGit
 	graphQL           *sdkmock.MockGraphQL
 	artifactServerURL string
 }
@@ -35,7 +37,9 @@ func newMock(ctrl *gomock.Controller) *mock {
 		issues:       sdkmock.NewMockIssues(ctrl),
 		pullRequest:  sdkmock.NewMockPullRequests(ctrl),
 		repositories: sdkmock.NewMockRepositories(ctrl),
+		teams:        sdkmock.NewMockTeams(ctrl),
 		reactions:    sdkmock.NewMockReactions(ctrl),
+		git:          sdkmock.NewMockGit(ctrl),
 		graphQL:      sdkmock.NewMockGraphQL(ctrl),
 	}
 }
@@ -47,7 +51,9 @@ func newTestGithub(mock *mock) Github {
 		issues:       mock.issues,
 		pullRequests: mock.pullRequest,
 		repositories: mock.repositories,
+		teams:        mock.teams,
 		reactions:    mock.reactions,
+		git:          mock.git,
 		graphQL:      mock.graphQL,
 		owner:        "test-owner",
 		repo:         "test-repo",
@@ -67,7 +73,7 @@ func TestNew(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestGithub_CreateIssueComment(t *testing.T) {
+func TestGithub_CreateComment(t *testing.T) {
 	t.Parallel()
 	type args struct {
 		number int
@@ -115,13 +121,13 @@ func TestGithub_CreateIssueComment(t *testing.T) {
 			ctx := context.Background()
 			tt.prepare(ctx, m, t)
 			gh := newTestGithub(m)
-			err := gh.CreateIssueComment(ctx, tt.args.number, tt.args.body)
+			err := gh.CreateComment(ctx, tt.args.number, tt.args.body)
 			require.ErrorIs(t, err, tt.expect)
 		})
 	}
 }
 
-func TestGithub_HideIssueComment(t *testing.T) {
+func TestGithub_HideComment(t *testing.T) {
 	t.Parallel()
 	type args struct {
 		nodeID string
@@ -184,13 +190,13 @@ func TestGithub_HideIssueComment(t *testing.T) {
 			ctx := context.Background()
 			tt.prepare(ctx, m, t)
 			gh := newTestGithub(m)
-			err := gh.HideIssueComment(ctx, tt.args.nodeID)
+			err := gh.HideComment(ctx, tt.args.nodeID)
 			require.ErrorIs(t, err, tt.expect)
 		})
 	}
 }
 
-func TestGithub_CreateIssueCommentReaction(t *testing.T) {
+func TestGithub_CreateCommentReaction(t *testing.T) {
 	t.Parallel()
 	type args struct {
 		commentID int64
@@ -234,7 +240,7 @@ func TestGithub_CreateIssueCommentReaction(t *testing.T) {
 			ctx := context.Background()
 			tt.prepare(ctx, m, t)
 			gh := newTestGithub(m)
-			err := gh.CreateIssueCommentReaction(ctx, tt.args.commentID, tt.args.content)
+			err := gh.CreateCommentReaction(ctx, tt.args.commentID, tt.args.content)
 			require.ErrorIs(t, err, tt.expect)
 		})
 	}
@@ -405,6 +411,210 @@ func TestGithub_GetLabel(t *testing.T) {
 	}
 }
 
+func TestGithub_GetPermissionLevel(t *testing.T) {
+	t.Parallel()
+	notFound := &githubv3.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+		},
+	}
+	tests := []struct {
+		name      string
+		prepare   prepare
+		expect    string
+		expectErr error
+	}{
+		{
+			name: "permission",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetPermissionLevel(ctx, "test-owner", "test-repo", "alice").
+					Return(&githubv3.RepositoryPermissionLevel{
+						Permission: githubv3.Ptr("write"),
+						RoleName:   githubv3.Ptr("write"),
+					}, &githubv3.Response{}, nil)
+			},
+			expect: "write",
+		},
+		{
+			name: "maintain role",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetPermissionLevel(ctx, "test-owner", "test-repo", "alice").
+					Return(&githubv3.RepositoryPermissionLevel{
+						Permission: githubv3.Ptr("write"),
+						RoleName:   githubv3.Ptr("maintain"),
+					}, &githubv3.Response{}, nil)
+			},
+			expect: "maintain",
+		},
+		{
+			name: "custom role",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetPermissionLevel(ctx, "test-owner", "test-repo", "alice").
+					Return(&githubv3.RepositoryPermissionLevel{
+						Permission: githubv3.Ptr("read"),
+						RoleName:   githubv3.Ptr("auditor"),
+					}, &githubv3.Response{}, nil)
+			},
+			expect: "read",
+		},
+		{
+			name: "not found",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetPermissionLevel(ctx, "test-owner", "test-repo", "alice").
+					Return(nil, nil, notFound)
+			},
+			expect: "none",
+		},
+		{
+			name: "failure",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetPermissionLevel(ctx, "test-owner", "test-repo", "alice").
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			level, err := gh.GetPermissionLevel(ctx, "alice")
+			require.ErrorIs(t, err, tt.expectErr)
+			assert.Equal(t, tt.expect, level)
+		})
+	}
+}
+
+func TestGithub_ListCommitStatuses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		prepare   prepare
+		expect    []*CommitStatus
+		expectErr error
+	}{
+		{
+			name: "paginated",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetCombinedStatus(ctx, "test-owner", "test-repo", "sha", &githubv3.ListOptions{PerPage: 100}).
+					Return(&githubv3.CombinedStatus{
+						Statuses: []*githubv3.RepoStatus{
+							{
+								State:       githubv3.Ptr("success"),
+								Description: githubv3.Ptr("Apply succeeded."),
+								Context:     githubv3.Ptr("mu/apply: test"),
+							},
+						},
+					}, &githubv3.Response{NextPage: 2}, nil)
+				m.repositories.EXPECT().GetCombinedStatus(ctx, "test-owner", "test-repo", "sha", &githubv3.ListOptions{Page: 2, PerPage: 100}).
+					Return(&githubv3.CombinedStatus{
+						Statuses: []*githubv3.RepoStatus{
+							{
+								State:     githubv3.Ptr("failure"),
+								TargetURL: githubv3.Ptr("https://example.com"),
+								Context:   githubv3.Ptr("mu/plan: test"),
+							},
+						},
+					}, &githubv3.Response{}, nil)
+			},
+			expect: []*CommitStatus{
+				{Sha: "sha", Status: SuccessStatus, Desc: "Apply succeeded.", Context: "mu/apply: test"},
+				{Sha: "sha", Status: FailureStatus, TargetURL: "https://example.com", Context: "mu/plan: test"},
+			},
+		},
+		{
+			name: "failure",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetCombinedStatus(ctx, "test-owner", "test-repo", "sha", gomock.Any()).
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			statuses, err := gh.ListCommitStatuses(ctx, "sha")
+			require.ErrorIs(t, err, tt.expectErr)
+			assert.Equal(t, tt.expect, statuses)
+		})
+	}
+}
+
+func TestGithub_IsTeamMember(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		team      string
+		prepare   prepare
+		expect    bool
+		expectErr error
+	}{
+		{
+			name: "active member",
+			team: "sre",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.teams.EXPECT().GetTeamMembershipBySlug(ctx, "test-owner", "sre", "alice").
+					Return(&githubv3.Membership{State: githubv3.Ptr("active")}, &githubv3.Response{}, nil)
+			},
+			expect: true,
+		},
+		{
+			name: "pending member of other organization",
+			team: "other-org/sre",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.teams.EXPECT().GetTeamMembershipBySlug(ctx, "other-org", "sre", "alice").
+					Return(&githubv3.Membership{State: githubv3.Ptr("pending")}, &githubv3.Response{}, nil)
+			},
+			expect: false,
+		},
+		{
+			name: "not member",
+			team: "sre",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.teams.EXPECT().GetTeamMembershipBySlug(ctx, "test-owner", "sre", "alice").
+					Return(nil, nil, &githubv3.ErrorResponse{
+						Response: &http.Response{
+							StatusCode: http.StatusNotFound,
+						},
+					})
+			},
+			expect: false,
+		},
+		{
+			name: "failure",
+			team: "sre",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.teams.EXPECT().GetTeamMembershipBySlug(ctx, "test-owner", "sre", "alice").
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			ok, err := gh.IsTeamMember(ctx, tt.team, "alice")
+			require.ErrorIs(t, err, tt.expectErr)
+			assert.Equal(t, tt.expect, ok)
+		})
+	}
+}
+
 func TestGithub_ListReviews(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -521,7 +731,7 @@ func TestGithub_ListReviews(t *testing.T) {
 	}
 }
 
-func TestGithub_ListPullRequestComments(t *testing.T) {
+func TestGithub_ListComments(t *testing.T) {
 	t.Parallel()
 	type args struct {
 		number int
@@ -573,7 +783,7 @@ func TestGithub_ListPullRequestComments(t *testing.T) {
 			ctx := context.Background()
 			tt.prepare(ctx, m, t)
 			gh := newTestGithub(m)
-			reviews, err := gh.ListPullRequestComments(ctx, tt.args.number)
+			reviews, err := gh.ListComments(ctx, tt.args.number)
 			require.ErrorIs(t, err, tt.expectErr)
 			require.Equal(t, tt.expect, reviews)
 		})
@@ -1012,6 +1222,110 @@ func TestGithub_FindPullRequestByLabel(t *testing.T) {
 	}
 }
 
+func TestGithub_FindMergedPullRequest(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		sha string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		prepare   prepare
+		expect    *PullRequest
+		expectErr error
+	}{
+		{
+			name: "success",
+			args: args{
+				sha: "test-merge-sha",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().ListPullRequestsWithCommit(ctx, "test-owner", "test-repo", "test-merge-sha", &githubv3.ListOptions{
+					Page:    0,
+					PerPage: 100,
+				}).Return([]*githubv3.PullRequest{
+					{
+						ID:     githubv3.Ptr(int64(10)),
+						Number: githubv3.Ptr(10),
+						Title:  githubv3.Ptr("open-title"),
+					},
+					{
+						ID:     githubv3.Ptr(int64(100)),
+						Number: githubv3.Ptr(100),
+						Title:  githubv3.Ptr("test-title"),
+						CreatedAt: &githubv3.Timestamp{
+							Time: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+						},
+						MergedAt: &githubv3.Timestamp{
+							Time: time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC),
+						},
+						Head: &githubv3.PullRequestBranch{
+							SHA: githubv3.Ptr("test-head-sha"),
+							Ref: githubv3.Ptr("test-branch"),
+						},
+					},
+				}, &githubv3.Response{
+					NextPage: 0,
+				}, nil)
+			},
+			expect: &PullRequest{
+				ID:        100,
+				Number:    100,
+				Title:     "test-title",
+				CreatedAt: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+				HeadSHA:   "test-head-sha",
+				HeadRef:   "test-branch",
+				Labels:    []*Label{},
+			},
+			expectErr: nil,
+		},
+		{
+			name: "not found",
+			args: args{
+				sha: "test-merge-sha",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().ListPullRequestsWithCommit(ctx, "test-owner", "test-repo", "test-merge-sha", &githubv3.ListOptions{
+					Page:    0,
+					PerPage: 100,
+				}).Return([]*githubv3.PullRequest{}, &githubv3.Response{
+					NextPage: 0,
+				}, nil)
+			},
+			expect:    nil,
+			expectErr: ErrNotFound,
+		},
+		{
+			name: "failure",
+			args: args{
+				sha: "test-merge-sha",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().ListPullRequestsWithCommit(ctx, "test-owner", "test-repo", "test-merge-sha", &githubv3.ListOptions{
+					Page:    0,
+					PerPage: 100,
+				}).Return(nil, nil, assert.AnError)
+			},
+			expect:    nil,
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			pullRequest, err := gh.FindMergedPullRequest(ctx, tt.args.sha)
+			require.ErrorIs(t, err, tt.expectErr)
+			require.Equal(t, tt.expect, pullRequest)
+		})
+	}
+}
+
 func TestGithub_AddPullRequestLabels(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -1243,6 +1557,10 @@ func TestGithub_GetPullRequest(t *testing.T) {
 					Head: &githubv3.PullRequestBranch{
 						SHA: githubv3.Ptr("sha"),
 					},
+					Base: &githubv3.PullRequestBranch{
+						SHA: githubv3.Ptr("base-sha"),
+						Ref: githubv3.Ptr("main"),
+					},
 					MergeableState: githubv3.Ptr("unstable"),
 					Labels: []*githubv3.Label{
 						{
@@ -1260,6 +1578,8 @@ func TestGithub_GetPullRequest(t *testing.T) {
 				Title:          "title",
 				CreatedAt:      time.Date(2025, 2, 15, 12, 0, 0, 0, time.UTC),
 				HeadSHA:        "sha",
+				BaseSHA:        "base-sha",
+				BaseRef:        "main",
 				MergeableState: "unstable",
 				Labels: []*Label{
 					{
@@ -1270,6 +1590,34 @@ func TestGithub_GetPullRequest(t *testing.T) {
 			},
 			expectErr: nil,
 		},
+		{
+			name: "fork",
+			args: args{
+				number: 1,
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().Get(ctx, "test-owner", "test-repo", 1).Return(&githubv3.PullRequest{
+					Number: githubv3.Ptr(1),
+					Head: &githubv3.PullRequestBranch{
+						SHA:  githubv3.Ptr("sha"),
+						Repo: &githubv3.Repository{ID: githubv3.Ptr(int64(2))},
+					},
+					Base: &githubv3.PullRequestBranch{
+						SHA:  githubv3.Ptr("base-sha"),
+						Ref:  githubv3.Ptr("main"),
+						Repo: &githubv3.Repository{ID: githubv3.Ptr(int64(1))},
+					},
+				}, &githubv3.Response{}, nil)
+			},
+			expect: &PullRequest{
+				Number:  1,
+				HeadSHA: "sha",
+				BaseSHA: "base-sha",
+				BaseRef: "main",
+				Fork:    true,
+				Labels:  []*Label{},
+			},
+		},
 		{
 			name: "not found",
 			args: args{
@@ -1316,6 +1664,69 @@ func TestGithub_GetPullRequest(t *testing.T) {
 	}
 }
 
+func TestGithub_GetFileContent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		prepare   prepare
+		expect    []byte
+		expectErr error
+	}{
+		{
+			name: "success",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetContents(ctx, "test-owner", "test-repo", ".github/mu.yaml", &githubv3.RepositoryContentGetOptions{Ref: "main"}).
+					Return(&githubv3.RepositoryContent{
+						Encoding: githubv3.Ptr("base64"),
+						Content:  githubv3.Ptr("dmVyc2lvbjogMQo="),
+					}, nil, &githubv3.Response{}, nil)
+			},
+			expect: []byte("version: 1\n"),
+		},
+		{
+			name: "not found",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetContents(ctx, "test-owner", "test-repo", ".github/mu.yaml", &githubv3.RepositoryContentGetOptions{Ref: "main"}).
+					Return(nil, nil, nil, &githubv3.ErrorResponse{
+						Response: &http.Response{
+							StatusCode: http.StatusNotFound,
+						},
+					})
+			},
+			expectErr: ErrNotFound,
+		},
+		{
+			name: "directory",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetContents(ctx, "test-owner", "test-repo", ".github/mu.yaml", &githubv3.RepositoryContentGetOptions{Ref: "main"}).
+					Return(nil, []*githubv3.RepositoryContent{}, &githubv3.Response{}, nil)
+			},
+			expectErr: ErrNotFound,
+		},
+		{
+			name: "failure",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.repositories.EXPECT().GetContents(ctx, "test-owner", "test-repo", ".github/mu.yaml", &githubv3.RepositoryContentGetOptions{Ref: "main"}).
+					Return(nil, nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			content, err := gh.GetFileContent(ctx, ".github/mu.yaml", "main")
+			require.ErrorIs(t, err, tt.expectErr)
+			assert.Equal(t, tt.expect, content)
+		})
+	}
+}
+
 func TestGithub_MultiGetArtifactsByNames(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -1670,3 +2081,491 @@ func TestGithub_DeleteArtifactsByNames(t *testing.T) {
 		})
 	}
 }
+
+func TestGithub_CommitFiles(t *testing.T) {
+	t.Parallel()
+	params := &CommitFilesParams{
+		Branch:  "feature",
+		Message: "mu fmt",
+		Files: map[string]string{
+			"test/main.tf": "content",
+		},
+	}
+	tests := []struct {
+		name      string
+		prepare   prepare
+		expect    string
+		expectErr error
+	}{
+		{
+			name: "success",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.git.EXPECT().GetRef(ctx, "test-owner", "test-repo", "heads/feature").
+					Return(&githubv3.Reference{
+						Object: &githubv3.GitObject{SHA: githubv3.Ptr("parent-sha")},
+					}, &githubv3.Response{}, nil)
+				m.git.EXPECT().GetCommit(ctx, "test-owner", "test-repo", "parent-sha").
+					Return(&githubv3.Commit{
+						Tree: &githubv3.Tree{SHA: githubv3.Ptr("base-tree-sha")},
+					}, &githubv3.Response{}, nil)
+				m.git.EXPECT().CreateTree(ctx, "test-owner", "test-repo", "base-tree-sha", []*githubv3.TreeEntry{
+					{
+						Path:    githubv3.Ptr("test/main.tf"),
+						Mode:    githubv3.Ptr("100644"),
+						Type:    githubv3.Ptr("blob"),
+						Content: githubv3.Ptr("content"),
+					},
+				}).Return(&githubv3.Tree{SHA: githubv3.Ptr("tree-sha")}, &githubv3.Response{}, nil)
+				m.git.EXPECT().CreateCommit(ctx, "test-owner", "test-repo", &githubv3.Commit{
+					Message: githubv3.Ptr("mu fmt"),
+					Tree:    &githubv3.Tree{SHA: githubv3.Ptr("tree-sha")},
+					Parents: []*githubv3.Commit{
+						{SHA: githubv3.Ptr("parent-sha")},
+					},
+				}, nil).Return(&githubv3.Commit{SHA: githubv3.Ptr("commit-sha")}, &githubv3.Response{}, nil)
+				m.git.EXPECT().UpdateRef(ctx, "test-owner", "test-repo", &githubv3.Reference{
+					Ref:    githubv3.Ptr("refs/heads/feature"),
+					Object: &githubv3.GitObject{SHA: githubv3.Ptr("commit-sha")},
+				}, false).Return(&githubv3.Reference{}, &githubv3.Response{}, nil)
+			},
+			expect: "commit-sha",
+		},
+		{
+			name: "failed to get ref",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.git.EXPECT().GetRef(ctx, "test-owner", "test-repo", "heads/feature").
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "failed to update ref",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.git.EXPECT().GetRef(ctx, "test-owner", "test-repo", "heads/feature").
+					Return(&githubv3.Reference{
+						Object: &githubv3.GitObject{SHA: githubv3.Ptr("parent-sha")},
+					}, &githubv3.Response{}, nil)
+				m.git.EXPECT().GetCommit(ctx, "test-owner", "test-repo", "parent-sha").
+					Return(&githubv3.Commit{
+						Tree: &githubv3.Tree{SHA: githubv3.Ptr("base-tree-sha")},
+					}, &githubv3.Response{}, nil)
+				m.git.EXPECT().CreateTree(ctx, "test-owner", "test-repo", "base-tree-sha", gomock.Any()).
+					Return(&githubv3.Tree{SHA: githubv3.Ptr("tree-sha")}, &githubv3.Response{}, nil)
+				m.git.EXPECT().CreateCommit(ctx, "test-owner", "test-repo", gomock.Any(), nil).
+					Return(&githubv3.Commit{SHA: githubv3.Ptr("commit-sha")}, &githubv3.Response{}, nil)
+				m.git.EXPECT().UpdateRef(ctx, "test-owner", "test-repo", gomock.Any(), false).
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			sha, err := gh.CommitFiles(ctx, params)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, sha)
+		})
+	}
+}
+
+func TestGithub_CommitFiles_Outdated(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newMock(ctrl)
+	ctx := context.Background()
+	m.git.EXPECT().GetRef(ctx, "test-owner", "test-repo", "heads/feature").
+		Return(&githubv3.Reference{
+			Object: &githubv3.GitObject{SHA: githubv3.Ptr("new-sha")},
+		}, &githubv3.Response{}, nil)
+	gh := newTestGithub(m)
+	_, err := gh.CommitFiles(ctx, &CommitFilesParams{
+		Branch:  "feature",
+		HeadSHA: "head-sha",
+		Message: "mu fmt",
+		Files: map[string]string{
+			"test/main.tf": "content",
+		},
+	})
+	require.ErrorIs(t, err, ErrOutdated)
+}
+
+func TestGithub_ListRepositoryFiles(t *testing.T) {
+	t.Parallel()
+	tree := &githubv3.Tree{
+		Entries: []*githubv3.TreeEntry{
+			{Path: githubv3.Ptr("main.tf"), Type: githubv3.Ptr("blob")},
+			{Path: githubv3.Ptr("test"), Type: githubv3.Ptr("tree")},
+			{Path: githubv3.Ptr("test/main.tf"), Type: githubv3.Ptr("blob")},
+			{Path: githubv3.Ptr("testdata/main.tf"), Type: githubv3.Ptr("blob")},
+		},
+	}
+	tests := []struct {
+		name      string
+		dir       string
+		prepare   prepare
+		expect    []string
+		expectErr error
+	}{
+		{
+			name: "project directory",
+			dir:  "./test",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.git.EXPECT().GetTree(ctx, "test-owner", "test-repo", "sha", true).Return(tree, &githubv3.Response{}, nil)
+			},
+			expect: []string{"test/main.tf"},
+		},
+		{
+			name: "root directory",
+			dir:  ".",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.git.EXPECT().GetTree(ctx, "test-owner", "test-repo", "sha", true).Return(tree, &githubv3.Response{}, nil)
+			},
+			expect: []string{"main.tf", "test/main.tf", "testdata/main.tf"},
+		},
+		{
+			name: "failure",
+			dir:  "test",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.git.EXPECT().GetTree(ctx, "test-owner", "test-repo", "sha", true).Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			files, err := gh.ListRepositoryFiles(ctx, tt.dir, "sha")
+			require.ErrorIs(t, err, tt.expectErr)
+			assert.Equal(t, tt.expect, files)
+		})
+	}
+}
+
+func TestGithub_FindIssueByLabel(t *testing.T) {
+	t.Parallel()
+	opts := &githubv3.IssueListByRepoOptions{
+		State:  "open",
+		Labels: []string{"mu_drift_test"},
+		ListOptions: githubv3.ListOptions{
+			PerPage: 100,
+		},
+	}
+	tests := []struct {
+		name      string
+		prepare   prepare
+		expect    *Issue
+		expectErr error
+	}{
+		{
+			name: "success",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.issues.EXPECT().ListByRepo(ctx, "test-owner", "test-repo", opts).Return([]*githubv3.Issue{
+					{
+						Number:           githubv3.Ptr(1),
+						Title:            githubv3.Ptr("pull request"),
+						PullRequestLinks: &githubv3.PullRequestLinks{},
+					},
+					{
+						Number: githubv3.Ptr(2),
+						Title:  githubv3.Ptr("drift"),
+						Body:   githubv3.Ptr("body"),
+					},
+				}, &githubv3.Response{}, nil)
+			},
+			expect: &Issue{
+				Number: 2,
+				Title:  "drift",
+				Body:   "body",
+			},
+		},
+		{
+			name: "not found",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.issues.EXPECT().ListByRepo(ctx, "test-owner", "test-repo", opts).
+					Return([]*githubv3.Issue{}, &githubv3.Response{}, nil)
+			},
+			expectErr: ErrNotFound,
+		},
+		{
+			name: "failure",
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.issues.EXPECT().ListByRepo(ctx, "test-owner", "test-repo", opts).
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			issue, err := gh.FindIssueByLabel(ctx, "mu_drift_test")
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, issue)
+		})
+	}
+}
+
+func TestGithub_CreateIssue(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newMock(ctrl)
+	ctx := context.Background()
+	labels := []string{"mu_drift_test"}
+	m.issues.EXPECT().Create(ctx, "test-owner", "test-repo", &githubv3.IssueRequest{
+		Title:  githubv3.Ptr("title"),
+		Body:   githubv3.Ptr("body"),
+		Labels: &labels,
+	}).Return(&githubv3.Issue{
+		Number: githubv3.Ptr(1),
+		Title:  githubv3.Ptr("title"),
+		Body:   githubv3.Ptr("body"),
+	}, &githubv3.Response{}, nil)
+	gh := newTestGithub(m)
+	issue, err := gh.CreateIssue(ctx, "title", "body", labels)
+	require.NoError(t, err)
+	assert.Equal(t, &Issue{Number: 1, Title: "title", Body: "body"}, issue)
+}
+
+func TestGithub_CloseIssue(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newMock(ctrl)
+	ctx := context.Background()
+	m.issues.EXPECT().Edit(ctx, "test-owner", "test-repo", 1, &githubv3.IssueRequest{
+		State:       githubv3.Ptr("closed"),
+		StateReason: githubv3.Ptr("completed"),
+	}).Return(&githubv3.Issue{}, &githubv3.Response{}, nil)
+	gh := newTestGithub(m)
+	err := gh.CloseIssue(ctx, 1)
+	require.NoError(t, err)
+}
+
+func TestGithub_MergePullRequest(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		params    *MergePullRequestParams
+		prepare   prepare
+		expectErr error
+	}{
+		{
+			name: "success",
+			params: &MergePullRequestParams{
+				Number:       1,
+				SHA:          "test-sha",
+				Method:       "squash",
+				DeleteBranch: true,
+				Branch:       "test-branch",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().Merge(ctx, "test-owner", "test-repo", 1, "", &githubv3.PullRequestOptions{
+					SHA:         "test-sha",
+					MergeMethod: "squash",
+				}).Return(&githubv3.PullRequestMergeResult{Merged: githubv3.Ptr(true)}, &githubv3.Response{}, nil)
+				m.git.EXPECT().DeleteRef(ctx, "test-owner", "test-repo", "heads/test-branch").Return(&githubv3.Response{}, nil)
+			},
+		},
+		{
+			name: "success: keep branch",
+			params: &MergePullRequestParams{
+				Number: 1,
+				SHA:    "test-sha",
+				Method: "merge",
+				Branch: "test-branch",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().Merge(ctx, "test-owner", "test-repo", 1, "", &githubv3.PullRequestOptions{
+					SHA:         "test-sha",
+					MergeMethod: "merge",
+				}).Return(&githubv3.PullRequestMergeResult{Merged: githubv3.Ptr(true)}, &githubv3.Response{}, nil)
+			},
+		},
+		{
+			name: "not merged",
+			params: &MergePullRequestParams{
+				Number: 1,
+				SHA:    "test-sha",
+				Method: "merge",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().Merge(ctx, "test-owner", "test-repo", 1, "", gomock.Any()).
+					Return(&githubv3.PullRequestMergeResult{
+						Merged:  githubv3.Ptr(false),
+						Message: githubv3.Ptr("Head branch was modified."),
+					}, &githubv3.Response{}, nil)
+			},
+			expectErr: errNotMerged,
+		},
+		{
+			name: "failure",
+			params: &MergePullRequestParams{
+				Number: 1,
+				SHA:    "test-sha",
+				Method: "merge",
+			},
+			prepare: func(ctx context.Context, m *mock, t *testing.T) {
+				m.pullRequest.EXPECT().Merge(ctx, "test-owner", "test-repo", 1, "", gomock.Any()).
+					Return(nil, nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMock(ctrl)
+			ctx := context.Background()
+			tt.prepare(ctx, m, t)
+			gh := newTestGithub(m)
+			err := gh.MergePullRequest(ctx, tt.params)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGithub_ListArtifactNames(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newMock(ctrl)
+	ctx := context.Background()
+	m.actions.EXPECT().ListArtifacts(ctx, "test-owner", "test-repo", &githubv3.ListArtifactsOptions{
+		ListOptions: githubv3.ListOptions{
+			Page:    0,
+			PerPage: 100,
+		},
+	}).Return(&githubv3.ArtifactList{
+		Artifacts: []*githubv3.Artifact{
+			{ID: githubv3.Ptr(int64(1)), Name: githubv3.Ptr("mu_test_default_1")},
+			{ID: githubv3.Ptr(int64(2)), Name: githubv3.Ptr("other")},
+		},
+	}, &githubv3.Response{NextPage: 100}, nil)
+	m.actions.EXPECT().ListArtifacts(ctx, "test-owner", "test-repo", &githubv3.ListArtifactsOptions{
+		ListOptions: githubv3.ListOptions{
+			Page:    100,
+			PerPage: 100,
+		},
+	}).Return(&githubv3.ArtifactList{
+		Artifacts: []*githubv3.Artifact{
+			{ID: githubv3.Ptr(int64(3)), Name: githubv3.Ptr("mu_test_default_1")},
+			{ID: githubv3.Ptr(int64(4)), Name: githubv3.Ptr("mu_sample_default_2")},
+		},
+	}, &githubv3.Response{NextPage: 0}, nil)
+	gh := newTestGithub(m)
+	names, err := gh.ListArtifactNames(ctx, "mu_")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mu_test_default_1", "mu_sample_default_2"}, names)
+}
+
+func TestNew_EnterpriseURLs(t *testing.T) {
+	ctx := context.Background()
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v3/repos/owner/repo/labels/mu_lock_test":
+			_, _ = w.Write([]byte(`{"name":"mu_lock_test","description":"desc"}`))
+		case "/api/graphql":
+			_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"comments":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	gh, err := New(ctx, "token", "owner", "repo", WithURLs(&URLs{
+		API:     srv.URL + "/api/v3",
+		GraphQL: srv.URL + "/api/graphql",
+	}))
+	require.NoError(t, err)
+
+	label, err := gh.GetLabel(ctx, "mu_lock_test")
+	require.NoError(t, err)
+	assert.Equal(t, &Label{Name: "mu_lock_test", Description: "desc"}, label)
+	comments, err := gh.ListComments(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+	assert.Equal(t, []string{"/api/v3/repos/owner/repo/labels/mu_lock_test", "/api/graphql"}, paths)
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.URL.Path != "/api/v3/user" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"login":"mu-bot"}`))
+	}))
+	defer srv.Close()
+
+	login, err := Login(ctx, "token", &URLs{API: srv.URL + "/api/v3"})
+	require.NoError(t, err)
+	assert.Equal(t, "mu-bot", login)
+
+	_, err = Login(ctx, "token", &URLs{API: srv.URL})
+	require.Error(t, err)
+}
+
+func TestURLsFromEnv(t *testing.T) {
+	t.Run("github.com", func(t *testing.T) {
+		t.Setenv("GITHUB_API_URL", "https://api.github.com")
+		t.Setenv("GITHUB_GRAPHQL_URL", "https://api.github.com/graphql")
+		t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+		urls := URLsFromEnv()
+		assert.False(t, urls.isEnterprise())
+		assert.Empty(t, urls.Upload)
+	})
+	t.Run("github enterprise server", func(t *testing.T) {
+		t.Setenv("GITHUB_API_URL", "https://ghe.example.com/api/v3")
+		t.Setenv("GITHUB_GRAPHQL_URL", "https://ghe.example.com/api/graphql")
+		t.Setenv("GITHUB_SERVER_URL", "https://ghe.example.com")
+		expected := &URLs{
+			API:     "https://ghe.example.com/api/v3",
+			GraphQL: "https://ghe.example.com/api/graphql",
+			Upload:  "https://ghe.example.com/api/uploads/",
+		}
+		urls := URLsFromEnv()
+		assert.Equal(t, expected, urls)
+		assert.True(t, urls.isEnterprise())
+	})
+}
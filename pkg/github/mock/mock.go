@@ -15,6 +15,7 @@ import (
 	reflect "reflect"
 
 	github "github.com/yu-icchi/mu/pkg/github"
+	vcs "github.com/yu-icchi/mu/pkg/vcs"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -56,46 +57,90 @@ func (mr *MockGithubMockRecorder) AddPullRequestLabels(ctx, number, labels any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPullRequestLabels", reflect.TypeOf((*MockGithub)(nil).AddPullRequestLabels), ctx, number, labels)
 }
 
-// CreateCommitStatus mocks base method.
-func (m *MockGithub) CreateCommitStatus(ctx context.Context, commitStatus *github.CommitStatus) error {
+// CloseIssue mocks base method.
+func (m *MockGithub) CloseIssue(ctx context.Context, number int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCommitStatus", ctx, commitStatus)
+	ret := m.ctrl.Call(m, "CloseIssue", ctx, number)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateCommitStatus indicates an expected call of CreateCommitStatus.
-func (mr *MockGithubMockRecorder) CreateCommitStatus(ctx, commitStatus any) *gomock.Call {
+// CloseIssue indicates an expected call of CloseIssue.
+func (mr *MockGithubMockRecorder) CloseIssue(ctx, number any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommitStatus", reflect.TypeOf((*MockGithub)(nil).CreateCommitStatus), ctx, commitStatus)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseIssue", reflect.TypeOf((*MockGithub)(nil).CloseIssue), ctx, number)
 }
 
-// CreateIssueComment mocks base method.
-func (m *MockGithub) CreateIssueComment(ctx context.Context, number int, body string) error {
+// CommitFiles mocks base method.
+func (m *MockGithub) CommitFiles(ctx context.Context, params *vcs.CommitFilesParams) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateIssueComment", ctx, number, body)
+	ret := m.ctrl.Call(m, "CommitFiles", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitFiles indicates an expected call of CommitFiles.
+func (mr *MockGithubMockRecorder) CommitFiles(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitFiles", reflect.TypeOf((*MockGithub)(nil).CommitFiles), ctx, params)
+}
+
+// CreateComment mocks base method.
+func (m *MockGithub) CreateComment(ctx context.Context, number int, body string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, number, body)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateIssueComment indicates an expected call of CreateIssueComment.
-func (mr *MockGithubMockRecorder) CreateIssueComment(ctx, number, body any) *gomock.Call {
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockGithubMockRecorder) CreateComment(ctx, number, body any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssueComment", reflect.TypeOf((*MockGithub)(nil).CreateIssueComment), ctx, number, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockGithub)(nil).CreateComment), ctx, number, body)
 }
 
-// CreateIssueCommentReaction mocks base method.
-func (m *MockGithub) CreateIssueCommentReaction(ctx context.Context, commentID int64, content string) error {
+// CreateCommentReaction mocks base method.
+func (m *MockGithub) CreateCommentReaction(ctx context.Context, commentID int64, content string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateIssueCommentReaction", ctx, commentID, content)
+	ret := m.ctrl.Call(m, "CreateCommentReaction", ctx, commentID, content)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateIssueCommentReaction indicates an expected call of CreateIssueCommentReaction.
-func (mr *MockGithubMockRecorder) CreateIssueCommentReaction(ctx, commentID, content any) *gomock.Call {
+// CreateCommentReaction indicates an expected call of CreateCommentReaction.
+func (mr *MockGithubMockRecorder) CreateCommentReaction(ctx, commentID, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommentReaction", reflect.TypeOf((*MockGithub)(nil).CreateCommentReaction), ctx, commentID, content)
+}
+
+// CreateCommitStatus mocks base method.
+func (m *MockGithub) CreateCommitStatus(ctx context.Context, commitStatus *vcs.CommitStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCommitStatus", ctx, commitStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCommitStatus indicates an expected call of CreateCommitStatus.
+func (mr *MockGithubMockRecorder) CreateCommitStatus(ctx, commitStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommitStatus", reflect.TypeOf((*MockGithub)(nil).CreateCommitStatus), ctx, commitStatus)
+}
+
+// CreateIssue mocks base method.
+func (m *MockGithub) CreateIssue(ctx context.Context, title, body string, labels []string) (*vcs.Issue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIssue", ctx, title, body, labels)
+	ret0, _ := ret[0].(*vcs.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIssue indicates an expected call of CreateIssue.
+func (mr *MockGithubMockRecorder) CreateIssue(ctx, title, body, labels any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssueCommentReaction", reflect.TypeOf((*MockGithub)(nil).CreateIssueCommentReaction), ctx, commentID, content)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssue", reflect.TypeOf((*MockGithub)(nil).CreateIssue), ctx, title, body, labels)
 }
 
 // CreateLabel mocks base method.
@@ -155,10 +200,10 @@ func (mr *MockGithubMockRecorder) DownloadArtifact(ctx, id, file any) *gomock.Ca
 }
 
 // Event mocks base method.
-func (m *MockGithub) Event() (github.Event, error) {
+func (m *MockGithub) Event() (vcs.Event, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Event")
-	ret0, _ := ret[0].(github.Event)
+	ret0, _ := ret[0].(vcs.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -169,11 +214,41 @@ func (mr *MockGithubMockRecorder) Event() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Event", reflect.TypeOf((*MockGithub)(nil).Event))
 }
 
+// FindIssueByLabel mocks base method.
+func (m *MockGithub) FindIssueByLabel(ctx context.Context, label string) (*vcs.Issue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIssueByLabel", ctx, label)
+	ret0, _ := ret[0].(*vcs.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIssueByLabel indicates an expected call of FindIssueByLabel.
+func (mr *MockGithubMockRecorder) FindIssueByLabel(ctx, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIssueByLabel", reflect.TypeOf((*MockGithub)(nil).FindIssueByLabel), ctx, label)
+}
+
+// FindMergedPullRequest mocks base method.
+func (m *MockGithub) FindMergedPullRequest(ctx context.Context, sha string) (*vcs.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMergedPullRequest", ctx, sha)
+	ret0, _ := ret[0].(*vcs.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMergedPullRequest indicates an expected call of FindMergedPullRequest.
+func (mr *MockGithubMockRecorder) FindMergedPullRequest(ctx, sha any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMergedPullRequest", reflect.TypeOf((*MockGithub)(nil).FindMergedPullRequest), ctx, sha)
+}
+
 // FindPullRequestByLabel mocks base method.
-func (m *MockGithub) FindPullRequestByLabel(ctx context.Context, label string) (*github.PullRequest, error) {
+func (m *MockGithub) FindPullRequestByLabel(ctx context.Context, label string) (*vcs.PullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FindPullRequestByLabel", ctx, label)
-	ret0, _ := ret[0].(*github.PullRequest)
+	ret0, _ := ret[0].(*vcs.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -184,11 +259,26 @@ func (mr *MockGithubMockRecorder) FindPullRequestByLabel(ctx, label any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPullRequestByLabel", reflect.TypeOf((*MockGithub)(nil).FindPullRequestByLabel), ctx, label)
 }
 
+// GetFileContent mocks base method.
+func (m *MockGithub) GetFileContent(ctx context.Context, path, ref string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileContent", ctx, path, ref)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileContent indicates an expected call of GetFileContent.
+func (mr *MockGithubMockRecorder) GetFileContent(ctx, path, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileContent", reflect.TypeOf((*MockGithub)(nil).GetFileContent), ctx, path, ref)
+}
+
 // GetLabel mocks base method.
-func (m *MockGithub) GetLabel(ctx context.Context, label string) (*github.Label, error) {
+func (m *MockGithub) GetLabel(ctx context.Context, label string) (*vcs.Label, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetLabel", ctx, label)
-	ret0, _ := ret[0].(*github.Label)
+	ret0, _ := ret[0].(*vcs.Label)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -199,11 +289,26 @@ func (mr *MockGithubMockRecorder) GetLabel(ctx, label any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabel", reflect.TypeOf((*MockGithub)(nil).GetLabel), ctx, label)
 }
 
+// GetPermissionLevel mocks base method.
+func (m *MockGithub) GetPermissionLevel(ctx context.Context, user string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissionLevel", ctx, user)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissionLevel indicates an expected call of GetPermissionLevel.
+func (mr *MockGithubMockRecorder) GetPermissionLevel(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionLevel", reflect.TypeOf((*MockGithub)(nil).GetPermissionLevel), ctx, user)
+}
+
 // GetPullRequest mocks base method.
-func (m *MockGithub) GetPullRequest(ctx context.Context, number int) (*github.PullRequest, error) {
+func (m *MockGithub) GetPullRequest(ctx context.Context, number int) (*vcs.PullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPullRequest", ctx, number)
-	ret0, _ := ret[0].(*github.PullRequest)
+	ret0, _ := ret[0].(*vcs.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -214,18 +319,78 @@ func (mr *MockGithubMockRecorder) GetPullRequest(ctx, number any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullRequest", reflect.TypeOf((*MockGithub)(nil).GetPullRequest), ctx, number)
 }
 
-// HideIssueComment mocks base method.
-func (m *MockGithub) HideIssueComment(ctx context.Context, nodeID string) error {
+// HideComment mocks base method.
+func (m *MockGithub) HideComment(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HideIssueComment", ctx, nodeID)
+	ret := m.ctrl.Call(m, "HideComment", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// HideIssueComment indicates an expected call of HideIssueComment.
-func (mr *MockGithubMockRecorder) HideIssueComment(ctx, nodeID any) *gomock.Call {
+// HideComment indicates an expected call of HideComment.
+func (mr *MockGithubMockRecorder) HideComment(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideIssueComment", reflect.TypeOf((*MockGithub)(nil).HideIssueComment), ctx, nodeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideComment", reflect.TypeOf((*MockGithub)(nil).HideComment), ctx, id)
+}
+
+// IsTeamMember mocks base method.
+func (m *MockGithub) IsTeamMember(ctx context.Context, team, user string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTeamMember", ctx, team, user)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTeamMember indicates an expected call of IsTeamMember.
+func (mr *MockGithubMockRecorder) IsTeamMember(ctx, team, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTeamMember", reflect.TypeOf((*MockGithub)(nil).IsTeamMember), ctx, team, user)
+}
+
+// ListArtifactNames mocks base method.
+func (m *MockGithub) ListArtifactNames(ctx context.Context, prefix string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArtifactNames", ctx, prefix)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArtifactNames indicates an expected call of ListArtifactNames.
+func (mr *MockGithubMockRecorder) ListArtifactNames(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArtifactNames", reflect.TypeOf((*MockGithub)(nil).ListArtifactNames), ctx, prefix)
+}
+
+// ListComments mocks base method.
+func (m *MockGithub) ListComments(ctx context.Context, number int) ([]*vcs.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", ctx, number)
+	ret0, _ := ret[0].([]*vcs.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockGithubMockRecorder) ListComments(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockGithub)(nil).ListComments), ctx, number)
+}
+
+// ListCommitStatuses mocks base method.
+func (m *MockGithub) ListCommitStatuses(ctx context.Context, sha string) ([]*vcs.CommitStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommitStatuses", ctx, sha)
+	ret0, _ := ret[0].([]*vcs.CommitStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommitStatuses indicates an expected call of ListCommitStatuses.
+func (mr *MockGithubMockRecorder) ListCommitStatuses(ctx, sha any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommitStatuses", reflect.TypeOf((*MockGithub)(nil).ListCommitStatuses), ctx, sha)
 }
 
 // ListFiles mocks base method.
@@ -243,41 +408,41 @@ func (mr *MockGithubMockRecorder) ListFiles(ctx, number any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockGithub)(nil).ListFiles), ctx, number)
 }
 
-// ListPullRequestComments mocks base method.
-func (m *MockGithub) ListPullRequestComments(ctx context.Context, number int) ([]*github.Comment, error) {
+// ListPullRequestsByLabel mocks base method.
+func (m *MockGithub) ListPullRequestsByLabel(ctx context.Context, label string, limit int) ([]*vcs.PullRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPullRequestComments", ctx, number)
-	ret0, _ := ret[0].([]*github.Comment)
+	ret := m.ctrl.Call(m, "ListPullRequestsByLabel", ctx, label, limit)
+	ret0, _ := ret[0].([]*vcs.PullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListPullRequestComments indicates an expected call of ListPullRequestComments.
-func (mr *MockGithubMockRecorder) ListPullRequestComments(ctx, number any) *gomock.Call {
+// ListPullRequestsByLabel indicates an expected call of ListPullRequestsByLabel.
+func (mr *MockGithubMockRecorder) ListPullRequestsByLabel(ctx, label, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPullRequestComments", reflect.TypeOf((*MockGithub)(nil).ListPullRequestComments), ctx, number)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPullRequestsByLabel", reflect.TypeOf((*MockGithub)(nil).ListPullRequestsByLabel), ctx, label, limit)
 }
 
-// ListPullRequestsByLabel mocks base method.
-func (m *MockGithub) ListPullRequestsByLabel(ctx context.Context, label string, limit int) ([]*github.PullRequest, error) {
+// ListRepositoryFiles mocks base method.
+func (m *MockGithub) ListRepositoryFiles(ctx context.Context, dir, ref string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPullRequestsByLabel", ctx, label, limit)
-	ret0, _ := ret[0].([]*github.PullRequest)
+	ret := m.ctrl.Call(m, "ListRepositoryFiles", ctx, dir, ref)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListPullRequestsByLabel indicates an expected call of ListPullRequestsByLabel.
-func (mr *MockGithubMockRecorder) ListPullRequestsByLabel(ctx, label, limit any) *gomock.Call {
+// ListRepositoryFiles indicates an expected call of ListRepositoryFiles.
+func (mr *MockGithubMockRecorder) ListRepositoryFiles(ctx, dir, ref any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPullRequestsByLabel", reflect.TypeOf((*MockGithub)(nil).ListPullRequestsByLabel), ctx, label, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepositoryFiles", reflect.TypeOf((*MockGithub)(nil).ListRepositoryFiles), ctx, dir, ref)
 }
 
 // ListReviews mocks base method.
-func (m *MockGithub) ListReviews(ctx context.Context, number int) (github.Reviews, error) {
+func (m *MockGithub) ListReviews(ctx context.Context, number int) (vcs.Reviews, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListReviews", ctx, number)
-	ret0, _ := ret[0].(github.Reviews)
+	ret0, _ := ret[0].(vcs.Reviews)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -288,6 +453,20 @@ func (mr *MockGithubMockRecorder) ListReviews(ctx, number any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviews", reflect.TypeOf((*MockGithub)(nil).ListReviews), ctx, number)
 }
 
+// MergePullRequest mocks base method.
+func (m *MockGithub) MergePullRequest(ctx context.Context, params *vcs.MergePullRequestParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergePullRequest", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergePullRequest indicates an expected call of MergePullRequest.
+func (mr *MockGithubMockRecorder) MergePullRequest(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePullRequest", reflect.TypeOf((*MockGithub)(nil).MergePullRequest), ctx, params)
+}
+
 // MultiGetArtifactsByNames mocks base method.
 func (m *MockGithub) MultiGetArtifactsByNames(ctx context.Context, names []string) (github.Artifacts, error) {
 	m.ctrl.T.Helper()
@@ -303,40 +482,16 @@ func (mr *MockGithubMockRecorder) MultiGetArtifactsByNames(ctx, names any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiGetArtifactsByNames", reflect.TypeOf((*MockGithub)(nil).MultiGetArtifactsByNames), ctx, names)
 }
 
-// MockEvent is a mock of Event interface.
-type MockEvent struct {
-	ctrl     *gomock.Controller
-	recorder *MockEventMockRecorder
-	isgomock struct{}
-}
-
-// MockEventMockRecorder is the mock recorder for MockEvent.
-type MockEventMockRecorder struct {
-	mock *MockEvent
-}
-
-// NewMockEvent creates a new mock instance.
-func NewMockEvent(ctrl *gomock.Controller) *MockEvent {
-	mock := &MockEvent{ctrl: ctrl}
-	mock.recorder = &MockEventMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockEvent) EXPECT() *MockEventMockRecorder {
-	return m.recorder
-}
-
-// Number mocks base method.
-func (m *MockEvent) Number() int {
+// UpdateIssueBody mocks base method.
+func (m *MockGithub) UpdateIssueBody(ctx context.Context, number int, body string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Number")
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "UpdateIssueBody", ctx, number, body)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Number indicates an expected call of Number.
-func (mr *MockEventMockRecorder) Number() *gomock.Call {
+// UpdateIssueBody indicates an expected call of UpdateIssueBody.
+func (mr *MockGithubMockRecorder) UpdateIssueBody(ctx, number, body any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Number", reflect.TypeOf((*MockEvent)(nil).Number))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIssueBody", reflect.TypeOf((*MockGithub)(nil).UpdateIssueBody), ctx, number, body)
 }
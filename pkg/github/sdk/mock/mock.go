@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=sdk.go -package=mock -destination=mock/mock.go Actions Issues PullRequests Repositories Reactions GraphQL
+//	mockgen -source=sdk.go -package=mock -destination=mock/mock.go Actions Issues PullRequests Repositories Teams Reactions Git GraphQL
 //
 
 // Package mock is a generated GoMock package.
@@ -130,6 +130,22 @@ func (mr *MockIssuesMockRecorder) AddLabelsToIssue(ctx, owner, repo, number, lab
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLabelsToIssue", reflect.TypeOf((*MockIssues)(nil).AddLabelsToIssue), ctx, owner, repo, number, labels)
 }
 
+// Create mocks base method.
+func (m *MockIssues) Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, owner, repo, issue)
+	ret0, _ := ret[0].(*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIssuesMockRecorder) Create(ctx, owner, repo, issue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIssues)(nil).Create), ctx, owner, repo, issue)
+}
+
 // CreateComment mocks base method.
 func (m *MockIssues) CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -177,6 +193,22 @@ func (mr *MockIssuesMockRecorder) DeleteLabel(ctx, owner, repo, name any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockIssues)(nil).DeleteLabel), ctx, owner, repo, name)
 }
 
+// Edit mocks base method.
+func (m *MockIssues) Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, owner, repo, number, issue)
+	ret0, _ := ret[0].(*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockIssuesMockRecorder) Edit(ctx, owner, repo, number, issue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockIssues)(nil).Edit), ctx, owner, repo, number, issue)
+}
+
 // GetLabel mocks base method.
 func (m *MockIssues) GetLabel(ctx context.Context, owner, repo, name string) (*github.Label, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -193,6 +225,22 @@ func (mr *MockIssuesMockRecorder) GetLabel(ctx, owner, repo, name any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabel", reflect.TypeOf((*MockIssues)(nil).GetLabel), ctx, owner, repo, name)
 }
 
+// ListByRepo mocks base method.
+func (m *MockIssues) ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRepo", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByRepo indicates an expected call of ListByRepo.
+func (mr *MockIssuesMockRecorder) ListByRepo(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRepo", reflect.TypeOf((*MockIssues)(nil).ListByRepo), ctx, owner, repo, opts)
+}
+
 // MockPullRequests is a mock of PullRequests interface.
 type MockPullRequests struct {
 	ctrl     *gomock.Controller
@@ -265,6 +313,22 @@ func (mr *MockPullRequestsMockRecorder) ListFiles(ctx, owner, repo, number, opts
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockPullRequests)(nil).ListFiles), ctx, owner, repo, number, opts)
 }
 
+// ListPullRequestsWithCommit mocks base method.
+func (m *MockPullRequests) ListPullRequestsWithCommit(ctx context.Context, owner, repo, sha string, opts *github.ListOptions) ([]*github.PullRequest, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPullRequestsWithCommit", ctx, owner, repo, sha, opts)
+	ret0, _ := ret[0].([]*github.PullRequest)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPullRequestsWithCommit indicates an expected call of ListPullRequestsWithCommit.
+func (mr *MockPullRequestsMockRecorder) ListPullRequestsWithCommit(ctx, owner, repo, sha, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPullRequestsWithCommit", reflect.TypeOf((*MockPullRequests)(nil).ListPullRequestsWithCommit), ctx, owner, repo, sha, opts)
+}
+
 // ListReviews mocks base method.
 func (m *MockPullRequests) ListReviews(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -281,6 +345,22 @@ func (mr *MockPullRequestsMockRecorder) ListReviews(ctx, owner, repo, number, op
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviews", reflect.TypeOf((*MockPullRequests)(nil).ListReviews), ctx, owner, repo, number, opts)
 }
 
+// Merge mocks base method.
+func (m *MockPullRequests) Merge(ctx context.Context, owner, repo string, number int, commitMessage string, opts *github.PullRequestOptions) (*github.PullRequestMergeResult, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, owner, repo, number, commitMessage, opts)
+	ret0, _ := ret[0].(*github.PullRequestMergeResult)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockPullRequestsMockRecorder) Merge(ctx, owner, repo, number, commitMessage, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequests)(nil).Merge), ctx, owner, repo, number, commitMessage, opts)
+}
+
 // MockRepositories is a mock of Repositories interface.
 type MockRepositories struct {
 	ctrl     *gomock.Controller
@@ -321,6 +401,95 @@ func (mr *MockRepositoriesMockRecorder) CreateStatus(ctx, owner, repo, ref, stat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatus", reflect.TypeOf((*MockRepositories)(nil).CreateStatus), ctx, owner, repo, ref, status)
 }
 
+// GetCombinedStatus mocks base method.
+func (m *MockRepositories) GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) (*github.CombinedStatus, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCombinedStatus", ctx, owner, repo, ref, opts)
+	ret0, _ := ret[0].(*github.CombinedStatus)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCombinedStatus indicates an expected call of GetCombinedStatus.
+func (mr *MockRepositoriesMockRecorder) GetCombinedStatus(ctx, owner, repo, ref, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCombinedStatus", reflect.TypeOf((*MockRepositories)(nil).GetCombinedStatus), ctx, owner, repo, ref, opts)
+}
+
+// GetContents mocks base method.
+func (m *MockRepositories) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContents", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContent)
+	ret1, _ := ret[1].([]*github.RepositoryContent)
+	ret2, _ := ret[2].(*github.Response)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetContents indicates an expected call of GetContents.
+func (mr *MockRepositoriesMockRecorder) GetContents(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContents", reflect.TypeOf((*MockRepositories)(nil).GetContents), ctx, owner, repo, path, opts)
+}
+
+// GetPermissionLevel mocks base method.
+func (m *MockRepositories) GetPermissionLevel(ctx context.Context, owner, repo, user string) (*github.RepositoryPermissionLevel, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissionLevel", ctx, owner, repo, user)
+	ret0, _ := ret[0].(*github.RepositoryPermissionLevel)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPermissionLevel indicates an expected call of GetPermissionLevel.
+func (mr *MockRepositoriesMockRecorder) GetPermissionLevel(ctx, owner, repo, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionLevel", reflect.TypeOf((*MockRepositories)(nil).GetPermissionLevel), ctx, owner, repo, user)
+}
+
+// MockTeams is a mock of Teams interface.
+type MockTeams struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamsMockRecorder
+	isgomock struct{}
+}
+
+// MockTeamsMockRecorder is the mock recorder for MockTeams.
+type MockTeamsMockRecorder struct {
+	mock *MockTeams
+}
+
+// NewMockTeams creates a new mock instance.
+func NewMockTeams(ctrl *gomock.Controller) *MockTeams {
+	mock := &MockTeams{ctrl: ctrl}
+	mock.recorder = &MockTeamsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeams) EXPECT() *MockTeamsMockRecorder {
+	return m.recorder
+}
+
+// GetTeamMembershipBySlug mocks base method.
+func (m *MockTeams) GetTeamMembershipBySlug(ctx context.Context, org, slug, user string) (*github.Membership, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMembershipBySlug", ctx, org, slug, user)
+	ret0, _ := ret[0].(*github.Membership)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamMembershipBySlug indicates an expected call of GetTeamMembershipBySlug.
+func (mr *MockTeamsMockRecorder) GetTeamMembershipBySlug(ctx, org, slug, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMembershipBySlug", reflect.TypeOf((*MockTeams)(nil).GetTeamMembershipBySlug), ctx, org, slug, user)
+}
+
 // MockReactions is a mock of Reactions interface.
 type MockReactions struct {
 	ctrl     *gomock.Controller
@@ -361,6 +530,141 @@ func (mr *MockReactionsMockRecorder) CreateIssueCommentReaction(ctx, owner, repo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssueCommentReaction", reflect.TypeOf((*MockReactions)(nil).CreateIssueCommentReaction), ctx, owner, repo, commentID, content)
 }
 
+// MockGit is a mock of Git interface.
+type MockGit struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitMockRecorder
+	isgomock struct{}
+}
+
+// MockGitMockRecorder is the mock recorder for MockGit.
+type MockGitMockRecorder struct {
+	mock *MockGit
+}
+
+// NewMockGit creates a new mock instance.
+func NewMockGit(ctrl *gomock.Controller) *MockGit {
+	mock := &MockGit{ctrl: ctrl}
+	mock.recorder = &MockGitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGit) EXPECT() *MockGitMockRecorder {
+	return m.recorder
+}
+
+// CreateCommit mocks base method.
+func (m *MockGit) CreateCommit(ctx context.Context, owner, repo string, commit *github.Commit, opts *github.CreateCommitOptions) (*github.Commit, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCommit", ctx, owner, repo, commit, opts)
+	ret0, _ := ret[0].(*github.Commit)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateCommit indicates an expected call of CreateCommit.
+func (mr *MockGitMockRecorder) CreateCommit(ctx, owner, repo, commit, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommit", reflect.TypeOf((*MockGit)(nil).CreateCommit), ctx, owner, repo, commit, opts)
+}
+
+// CreateTree mocks base method.
+func (m *MockGit) CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTree", ctx, owner, repo, baseTree, entries)
+	ret0, _ := ret[0].(*github.Tree)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateTree indicates an expected call of CreateTree.
+func (mr *MockGitMockRecorder) CreateTree(ctx, owner, repo, baseTree, entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTree", reflect.TypeOf((*MockGit)(nil).CreateTree), ctx, owner, repo, baseTree, entries)
+}
+
+// DeleteRef mocks base method.
+func (m *MockGit) DeleteRef(ctx context.Context, owner, repo, ref string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRef", ctx, owner, repo, ref)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRef indicates an expected call of DeleteRef.
+func (mr *MockGitMockRecorder) DeleteRef(ctx, owner, repo, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRef", reflect.TypeOf((*MockGit)(nil).DeleteRef), ctx, owner, repo, ref)
+}
+
+// GetCommit mocks base method.
+func (m *MockGit) GetCommit(ctx context.Context, owner, repo, sha string) (*github.Commit, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommit", ctx, owner, repo, sha)
+	ret0, _ := ret[0].(*github.Commit)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCommit indicates an expected call of GetCommit.
+func (mr *MockGitMockRecorder) GetCommit(ctx, owner, repo, sha any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommit", reflect.TypeOf((*MockGit)(nil).GetCommit), ctx, owner, repo, sha)
+}
+
+// GetRef mocks base method.
+func (m *MockGit) GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRef", ctx, owner, repo, ref)
+	ret0, _ := ret[0].(*github.Reference)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRef indicates an expected call of GetRef.
+func (mr *MockGitMockRecorder) GetRef(ctx, owner, repo, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRef", reflect.TypeOf((*MockGit)(nil).GetRef), ctx, owner, repo, ref)
+}
+
+// GetTree mocks base method.
+func (m *MockGit) GetTree(ctx context.Context, owner, repo, sha string, recursive bool) (*github.Tree, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTree", ctx, owner, repo, sha, recursive)
+	ret0, _ := ret[0].(*github.Tree)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTree indicates an expected call of GetTree.
+func (mr *MockGitMockRecorder) GetTree(ctx, owner, repo, sha, recursive any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTree", reflect.TypeOf((*MockGit)(nil).GetTree), ctx, owner, repo, sha, recursive)
+}
+
+// UpdateRef mocks base method.
+func (m *MockGit) UpdateRef(ctx context.Context, owner, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRef", ctx, owner, repo, ref, force)
+	ret0, _ := ret[0].(*github.Reference)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateRef indicates an expected call of UpdateRef.
+func (mr *MockGitMockRecorder) UpdateRef(ctx, owner, repo, ref, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRef", reflect.TypeOf((*MockGit)(nil).UpdateRef), ctx, owner, repo, ref, force)
+}
+
 // MockGraphQL is a mock of GraphQL interface.
 type MockGraphQL struct {
 	ctrl     *gomock.Controller
@@ -9,7 +9,7 @@ import (
 )
 
 //go:generate mkdir -p mock
-//go:generate mockgen -source=sdk.go -package=mock -destination=mock/mock.go Actions Issues PullRequests Repositories Reactions GraphQL
+//go:generate mockgen -source=sdk.go -package=mock -destination=mock/mock.go Actions Issues PullRequests Repositories Teams Reactions Git GraphQL
 
 type Actions interface {
 	ListArtifacts(ctx context.Context, owner, repo string, opts *githubv3.ListArtifactsOptions) (*githubv3.ArtifactList, *githubv3.Response, error)
@@ -23,6 +23,9 @@ type Issues interface {
 	DeleteLabel(ctx context.Context, owner, repo, name string) (*githubv3.Response, error)
 	GetLabel(ctx context.Context, owner, repo, name string) (*githubv3.Label, *githubv3.Response, error)
 	AddLabelsToIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*githubv3.Label, *githubv3.Response, error)
+	ListByRepo(ctx context.Context, owner, repo string, opts *githubv3.IssueListByRepoOptions) ([]*githubv3.Issue, *githubv3.Response, error)
+	Create(ctx context.Context, owner, repo string, issue *githubv3.IssueRequest) (*githubv3.Issue, *githubv3.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, issue *githubv3.IssueRequest) (*githubv3.Issue, *githubv3.Response, error)
 }
 
 type PullRequests interface {
@@ -30,16 +33,35 @@ type PullRequests interface {
 	List(ctx context.Context, owner, repo string, opts *githubv3.PullRequestListOptions) ([]*githubv3.PullRequest, *githubv3.Response, error)
 	ListFiles(ctx context.Context, owner, repo string, number int, opts *githubv3.ListOptions) ([]*githubv3.CommitFile, *githubv3.Response, error)
 	Get(ctx context.Context, owner, repo string, number int) (*githubv3.PullRequest, *githubv3.Response, error)
+	ListPullRequestsWithCommit(ctx context.Context, owner, repo, sha string, opts *githubv3.ListOptions) ([]*githubv3.PullRequest, *githubv3.Response, error)
+	Merge(ctx context.Context, owner, repo string, number int, commitMessage string, opts *githubv3.PullRequestOptions) (*githubv3.PullRequestMergeResult, *githubv3.Response, error)
 }
 
 type Repositories interface {
 	CreateStatus(ctx context.Context, owner, repo, ref string, status *githubv3.RepoStatus) (*githubv3.RepoStatus, *githubv3.Response, error)
+	GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *githubv3.ListOptions) (*githubv3.CombinedStatus, *githubv3.Response, error)
+	GetPermissionLevel(ctx context.Context, owner, repo, user string) (*githubv3.RepositoryPermissionLevel, *githubv3.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *githubv3.RepositoryContentGetOptions) (*githubv3.RepositoryContent, []*githubv3.RepositoryContent, *githubv3.Response, error)
+}
+
+type Teams interface {
+	GetTeamMembershipBySlug(ctx context.Context, org, slug, user string) (*githubv3.Membership, *githubv3.Response, error)
 }
 
 type Reactions interface {
 	CreateIssueCommentReaction(ctx context.Context, owner, repo string, commentID int64, content string) (*githubv3.Reaction, *githubv3.Response, error)
 }
 
+type Git interface {
+	GetRef(ctx context.Context, owner, repo, ref string) (*githubv3.Reference, *githubv3.Response, error)
+	GetCommit(ctx context.Context, owner, repo, sha string) (*githubv3.Commit, *githubv3.Response, error)
+	GetTree(ctx context.Context, owner, repo, sha string, recursive bool) (*githubv3.Tree, *githubv3.Response, error)
+	CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*githubv3.TreeEntry) (*githubv3.Tree, *githubv3.Response, error)
+	CreateCommit(ctx context.Context, owner, repo string, commit *githubv3.Commit, opts *githubv3.CreateCommitOptions) (*githubv3.Commit, *githubv3.Response, error)
+	UpdateRef(ctx context.Context, owner, repo string, ref *githubv3.Reference, force bool) (*githubv3.Reference, *githubv3.Response, error)
+	DeleteRef(ctx context.Context, owner, repo, ref string) (*githubv3.Response, error)
+}
+
 type GraphQL interface {
 	Query(ctx context.Context, query any, variables map[string]any) error
 	Mutate(ctx context.Context, mutate any, input githubv4.Input, variables map[string]any) error
@@ -61,10 +83,18 @@ func NewRepositories(cli *githubv3.Client) Repositories {
 	return cli.Repositories
 }
 
+func NewTeams(cli *githubv3.Client) Teams {
+	return cli.Teams
+}
+
 func NewReactions(cli *githubv3.Client) Reactions {
 	return cli.Reactions
 }
 
+func NewGit(cli *githubv3.Client) Git {
+	return cli.Git
+}
+
 func NewGraphQL(cli *githubv4.Client) GraphQL {
 	return cli
 }
@@ -29,6 +29,13 @@ func TestNewPullRequests(t *testing.T) {
 	assert.NotNil(t, pullRequests)
 }
 
+func TestNewTeams(t *testing.T) {
+	t.Parallel()
+	cli := githubv3.NewClient(nil)
+	teams := NewTeams(cli)
+	assert.NotNil(t, teams)
+}
+
 func TestNewRepositories(t *testing.T) {
 	t.Parallel()
 	cli := githubv3.NewClient(nil)
@@ -0,0 +1,78 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+var (
+	ErrUnsupportedEvent       = errors.New("unsupported pipeline")
+	errInvalidMergeRequestIID = errors.New("invalid merge request iid")
+)
+
+// Event returns the event from the predefined variables of GitLab CI.
+//
+//   - A merge request pipeline plans the changed projects, like a pull_request event of GitHub.
+//   - A pipeline with MU_COMMAND runs the mu command on the merge request, like an issue_comment event of GitHub.
+//     GitLab cannot run a pipeline on a note, so a webhook of the note events has to trigger the pipeline with
+//     MU_COMMAND (the body of the note), MU_MERGE_REQUEST_IID and optionally MU_NOTE_ID and MU_NOTE_AUTHOR.
+//     The user who started the pipeline is the author of the note if MU_NOTE_AUTHOR is not set.
+//   - A scheduled pipeline detects the drift.
+//   - A push pipeline on the default branch applies the projects with `apply.mode: after_merge`.
+func (g *gitlab) Event() (vcs.Event, error) {
+	if command := g.getenv("MU_COMMAND"); command != "" {
+		number, err := strconv.Atoi(g.mergeRequestIID())
+		if err != nil {
+			return nil, fmt.Errorf("%w: MU_MERGE_REQUEST_IID", errInvalidMergeRequestIID)
+		}
+		noteID, _ := strconv.ParseInt(g.getenv("MU_NOTE_ID"), 10, 64)
+		return &vcs.CommentEvent{
+			Action:            vcs.Created,
+			PullRequestNumber: number,
+			CommentID:         noteID,
+			Body:              command,
+			User:              g.noteAuthor(),
+		}, nil
+	}
+
+	switch source := g.getenv("CI_PIPELINE_SOURCE"); source {
+	case "merge_request_event":
+		number, err := strconv.Atoi(g.getenv("CI_MERGE_REQUEST_IID"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: CI_MERGE_REQUEST_IID", errInvalidMergeRequestIID)
+		}
+		return &vcs.PullRequestEvent{
+			Action:            vcs.Synchronize,
+			PullRequestNumber: number,
+			User:              g.getenv("GITLAB_USER_LOGIN"),
+		}, nil
+	case "schedule", "web":
+		return &vcs.DriftEvent{}, nil
+	case "push":
+		branch := g.getenv("CI_COMMIT_BRANCH")
+		return &vcs.PushEvent{
+			SHA:           g.getenv("CI_COMMIT_SHA"),
+			DefaultBranch: branch != "" && branch == g.getenv("CI_DEFAULT_BRANCH"),
+			User:          g.getenv("GITLAB_USER_LOGIN"),
+		}, nil
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+}
+
+func (g *gitlab) mergeRequestIID() string {
+	if iid := g.getenv("MU_MERGE_REQUEST_IID"); iid != "" {
+		return iid
+	}
+	return g.getenv("CI_MERGE_REQUEST_IID")
+}
+
+func (g *gitlab) noteAuthor() string {
+	if author := g.getenv("MU_NOTE_AUTHOR"); author != "" {
+		return author
+	}
+	return g.getenv("GITLAB_USER_LOGIN")
+}
@@ -0,0 +1,117 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+func TestGitlab_Event(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		env       map[string]string
+		expect    vcs.Event
+		expectErr error
+	}{
+		"merge request pipeline": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":   "merge_request_event",
+				"CI_MERGE_REQUEST_IID": "1",
+			},
+			expect: &vcs.PullRequestEvent{
+				Action:            vcs.Synchronize,
+				PullRequestNumber: 1,
+			},
+		},
+		"command triggered by note": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":   "trigger",
+				"MU_COMMAND":           "mu apply -p test",
+				"MU_MERGE_REQUEST_IID": "2",
+				"MU_NOTE_ID":           "10",
+				"MU_NOTE_AUTHOR":       "alice",
+				"GITLAB_USER_LOGIN":    "trigger-bot",
+			},
+			expect: &vcs.CommentEvent{
+				Action:            vcs.Created,
+				PullRequestNumber: 2,
+				CommentID:         10,
+				Body:              "mu apply -p test",
+				User:              "alice",
+			},
+		},
+		"command run by pipeline user": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":   "web",
+				"MU_COMMAND":           "mu plan",
+				"MU_MERGE_REQUEST_IID": "2",
+				"GITLAB_USER_LOGIN":    "bob",
+			},
+			expect: &vcs.CommentEvent{
+				Action:            vcs.Created,
+				PullRequestNumber: 2,
+				Body:              "mu plan",
+				User:              "bob",
+			},
+		},
+		"schedule": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE": "schedule",
+			},
+			expect: &vcs.DriftEvent{},
+		},
+		"push to default branch": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE": "push",
+				"CI_COMMIT_BRANCH":   "main",
+				"CI_DEFAULT_BRANCH":  "main",
+				"CI_COMMIT_SHA":      "sha",
+				"GITLAB_USER_LOGIN":  "alice",
+			},
+			expect: &vcs.PushEvent{
+				SHA:           "sha",
+				DefaultBranch: true,
+				User:          "alice",
+			},
+		},
+		"push to tag": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE": "push",
+				"CI_DEFAULT_BRANCH":  "main",
+				"CI_COMMIT_SHA":      "sha",
+			},
+			expect: &vcs.PushEvent{
+				SHA:           "sha",
+				DefaultBranch: false,
+			},
+		},
+		"command without merge request": {
+			env: map[string]string{
+				"MU_COMMAND": "mu plan",
+			},
+			expectErr: errInvalidMergeRequestIID,
+		},
+		"unsupported pipeline": {
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE": "pipeline",
+			},
+			expectErr: ErrUnsupportedEvent,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := &gitlab{getenv: func(key string) string { return tt.env[key] }}
+			event, err := g.Event()
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, event)
+		})
+	}
+}
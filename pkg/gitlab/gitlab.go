@@ -0,0 +1,709 @@
+// Package gitlab implements vcs.VCS with the REST API v4 of GitLab.
+// The merge requests are the pull requests, and the notes are the comments.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+type Params struct {
+	// APIURL is the URL of the REST API v4, e.g. https://gitlab.com/api/v4.
+	APIURL string
+	// Token is the personal, group or project access token with the api scope.
+	// The CI job token cannot post notes.
+	Token string
+	// ProjectID is the ID or the path of the project, e.g. 123 or group/project.
+	ProjectID  string
+	HTTPClient *http.Client
+}
+
+// ParamsFromEnv returns the parameters from the predefined variables of GitLab CI,
+// and the token from MU_GITLAB_TOKEN or GITLAB_TOKEN.
+func ParamsFromEnv() *Params {
+	token := os.Getenv("MU_GITLAB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GITLAB_TOKEN")
+	}
+	return &Params{
+		APIURL:    os.Getenv("CI_API_V4_URL"),
+		Token:     token,
+		ProjectID: os.Getenv("CI_PROJECT_ID"),
+	}
+}
+
+type gitlab struct {
+	apiURL    string
+	token     string
+	projectID string
+	cli       *http.Client
+	getenv    func(string) string
+}
+
+func New(params *Params) vcs.VCS {
+	cli := params.HTTPClient
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	return &gitlab{
+		apiURL:    strings.TrimSuffix(params.APIURL, "/"),
+		token:     params.Token,
+		projectID: params.ProjectID,
+		cli:       cli,
+		getenv:    os.Getenv,
+	}
+}
+
+func (g *gitlab) projectPath(format string, args ...any) string {
+	return "/projects/" + url.PathEscape(g.projectID) + fmt.Sprintf(format, args...)
+}
+
+// do sends the request to the path, and decodes the response into out unless it is nil.
+// It returns vcs.ErrNotFound on 404 and vcs.ErrAlreadyExists on 409.
+func (g *gitlab) do(ctx context.Context, method, path string, query url.Values, in, out any) (*http.Response, error) {
+	u := g.apiURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s %s", vcs.ErrNotFound, method, path)
+	case resp.StatusCode == http.StatusConflict:
+		return nil, fmt.Errorf("%w: %s %s", vcs.ErrAlreadyExists, method, path)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%w: %s %s %s", errUnexpectedStatus, method, path, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// listAll follows the X-Next-Page header until the last page.
+func listAll[T any](ctx context.Context, g *gitlab, path string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", "100")
+	var list []T
+	for {
+		var items []T
+		resp, err := g.do(ctx, http.MethodGet, path, query, nil, &items)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, items...)
+		next := resp.Header.Get("X-Next-Page")
+		if next == "" {
+			return list, nil
+		}
+		query.Set("page", next)
+	}
+}
+
+type user struct {
+	Username string `json:"username"`
+}
+
+type note struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	Author    user      `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	System    bool      `json:"system"`
+}
+
+func (g *gitlab) CreateComment(ctx context.Context, number int, body string) error {
+	in := map[string]string{"body": body}
+	_, err := g.do(ctx, http.MethodPost, g.projectPath("/merge_requests/%d/notes", number), nil, in, nil)
+	return err
+}
+
+// HideComment does nothing, since GitLab cannot collapse notes.
+func (g *gitlab) HideComment(_ context.Context, _ string) error {
+	return nil
+}
+
+// CreateCommentReaction awards the emoji to the note of the merge request of the current pipeline.
+func (g *gitlab) CreateCommentReaction(ctx context.Context, commentID int64, content string) error {
+	number, _ := strconv.Atoi(g.mergeRequestIID())
+	if number == 0 {
+		return nil
+	}
+	in := map[string]string{"name": emojiName(content)}
+	_, err := g.do(ctx, http.MethodPost, g.projectPath("/merge_requests/%d/notes/%d/award_emoji", number, commentID), nil, in, nil)
+	return err
+}
+
+// emojiName converts the reaction of GitHub into the name of the emoji of GitLab.
+func emojiName(content string) string {
+	switch content {
+	case "+1":
+		return "thumbsup"
+	case "-1":
+		return "thumbsdown"
+	case "laugh":
+		return "laughing"
+	case "hooray":
+		return "tada"
+	default:
+		return content
+	}
+}
+
+func (g *gitlab) ListComments(ctx context.Context, number int) ([]*vcs.Comment, error) {
+	query := url.Values{
+		"sort":     []string{"asc"},
+		"order_by": []string{"created_at"},
+	}
+	notes, err := listAll[*note](ctx, g, g.projectPath("/merge_requests/%d/notes", number), query)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]*vcs.Comment, 0, len(notes))
+	for _, n := range notes {
+		if n.System {
+			continue
+		}
+		comment := &vcs.Comment{
+			ID:         strconv.FormatInt(n.ID, 10),
+			DatabaseID: n.ID,
+			Body:       n.Body,
+			CreatedAt:  n.CreatedAt.Format(time.RFC3339),
+		}
+		comment.Author.Login = n.Author.Username
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+func (g *gitlab) CreateCommitStatus(ctx context.Context, commitStatus *vcs.CommitStatus) error {
+	in := map[string]string{
+		"state":       commitState(commitStatus.Status),
+		"name":        commitStatus.Context,
+		"description": commitStatus.Desc,
+	}
+	if commitStatus.TargetURL != "" {
+		in["target_url"] = commitStatus.TargetURL
+	}
+	_, err := g.do(ctx, http.MethodPost, g.projectPath("/statuses/%s", commitStatus.Sha), nil, in, nil)
+	return err
+}
+
+type commitStatus struct {
+	Status      string `json:"status"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+}
+
+// ListCommitStatuses returns the latest status of each name on the commit, which GitLab returns unless all is set.
+func (g *gitlab) ListCommitStatuses(ctx context.Context, sha string) ([]*vcs.CommitStatus, error) {
+	list, err := listAll[*commitStatus](ctx, g, g.projectPath("/repository/commits/%s/statuses", sha), nil)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*vcs.CommitStatus, len(list))
+	for i, status := range list {
+		statuses[i] = &vcs.CommitStatus{
+			Sha:       sha,
+			Status:    parseCommitState(status.Status),
+			TargetURL: status.TargetURL,
+			Desc:      status.Description,
+			Context:   status.Name,
+		}
+	}
+	return statuses, nil
+}
+
+// parseCommitState converts the status of the commit into vcs.Status, which is the inverse of commitState.
+func parseCommitState(state string) vcs.Status {
+	switch state {
+	case "success":
+		return vcs.SuccessStatus
+	case "pending", "running", "created":
+		return vcs.PendingStatus
+	case "failed":
+		return vcs.FailureStatus
+	default:
+		return vcs.ErrorStatus
+	}
+}
+
+func commitState(status vcs.Status) string {
+	switch status {
+	case vcs.PendingStatus:
+		return "pending"
+	case vcs.SuccessStatus:
+		return "success"
+	default:
+		return "failed"
+	}
+}
+
+type mergeRequest struct {
+	ID              int64     `json:"id"`
+	IID             int       `json:"iid"`
+	Title           string    `json:"title"`
+	State           string    `json:"state"`
+	CreatedAt       time.Time `json:"created_at"`
+	SHA             string    `json:"sha"`
+	SourceBranch    string    `json:"source_branch"`
+	TargetBranch    string    `json:"target_branch"`
+	SourceProjectID int64     `json:"source_project_id"`
+	TargetProjectID int64     `json:"target_project_id"`
+	DiffRefs        struct {
+		BaseSHA string `json:"base_sha"`
+	} `json:"diff_refs"`
+	DetailedMergeStatus string   `json:"detailed_merge_status"`
+	Labels              []string `json:"labels"`
+}
+
+func (mr *mergeRequest) pullRequest() *vcs.PullRequest {
+	labels := make([]*vcs.Label, len(mr.Labels))
+	for i, label := range mr.Labels {
+		labels[i] = &vcs.Label{Name: label}
+	}
+	return &vcs.PullRequest{
+		ID:             mr.ID,
+		Number:         mr.IID,
+		Title:          mr.Title,
+		CreatedAt:      mr.CreatedAt,
+		HeadSHA:        mr.SHA,
+		HeadRef:        mr.SourceBranch,
+		BaseSHA:        mr.DiffRefs.BaseSHA,
+		BaseRef:        mr.TargetBranch,
+		Fork:           mr.SourceProjectID != mr.TargetProjectID,
+		MergeableState: mergeableState(mr.DetailedMergeStatus),
+		Labels:         labels,
+	}
+}
+
+// mergeableState converts the detailed merge status into the mergeable state of GitHub, which vcs.PullRequest has.
+// The statuses which mu itself resolves, such as the pipeline and the approvals, are regarded as mergeable.
+func mergeableState(status string) string {
+	switch status {
+	case "mergeable":
+		return "clean"
+	case "conflict", "broken_status":
+		return "dirty"
+	case "need_rebase":
+		return "behind"
+	case "checking", "unchecked", "preparing", "approvals_syncing":
+		return "unknown"
+	default:
+		return "unstable"
+	}
+}
+
+func (g *gitlab) GetPullRequest(ctx context.Context, number int) (*vcs.PullRequest, error) {
+	mr := &mergeRequest{}
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d", number), nil, nil, mr); err != nil {
+		return nil, err
+	}
+	return mr.pullRequest(), nil
+}
+
+func (g *gitlab) FindMergedPullRequest(ctx context.Context, sha string) (*vcs.PullRequest, error) {
+	var mrs []*mergeRequest
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/repository/commits/%s/merge_requests", sha), nil, nil, &mrs); err != nil {
+		return nil, err
+	}
+	for _, mr := range mrs {
+		if mr.State == "merged" {
+			return mr.pullRequest(), nil
+		}
+	}
+	return nil, vcs.ErrNotFound
+}
+
+type diff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	RenamedFile bool   `json:"renamed_file"`
+}
+
+func (g *gitlab) ListFiles(ctx context.Context, number int) ([]string, error) {
+	diffs, err := listAll[*diff](ctx, g, g.projectPath("/merge_requests/%d/diffs", number), nil)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		files = append(files, d.NewPath)
+		if d.RenamedFile {
+			files = append(files, d.OldPath)
+		}
+	}
+	return files, nil
+}
+
+func (g *gitlab) ListReviews(ctx context.Context, number int) (vcs.Reviews, error) {
+	var approvals struct {
+		ApprovedBy []struct {
+			User user `json:"user"`
+		} `json:"approved_by"`
+	}
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d/approvals", number), nil, nil, &approvals); err != nil {
+		return nil, err
+	}
+	reviews := make(vcs.Reviews, 0, len(approvals.ApprovedBy))
+	for _, approval := range approvals.ApprovedBy {
+		reviews = append(reviews, &vcs.Review{
+			UserLogin: approval.User.Username,
+			State:     "APPROVED",
+		})
+	}
+	return reviews, nil
+}
+
+// The access levels of the members.
+const (
+	accessGuest      = 10
+	accessDeveloper  = 30
+	accessMaintainer = 40
+	accessOwner      = 50
+)
+
+func permissionLevel(accessLevel int) string {
+	switch {
+	case accessLevel >= accessOwner:
+		return vcs.PermissionAdmin
+	case accessLevel >= accessMaintainer:
+		return vcs.PermissionMaintain
+	case accessLevel >= accessDeveloper:
+		return vcs.PermissionWrite
+	case accessLevel >= accessGuest:
+		return vcs.PermissionRead
+	default:
+		return vcs.PermissionNone
+	}
+}
+
+// findUserID returns the ID of the user, since the member API does not accept the username.
+func (g *gitlab) findUserID(ctx context.Context, username string) (int64, error) {
+	var users []struct {
+		ID int64 `json:"id"`
+	}
+	if _, err := g.do(ctx, http.MethodGet, "/users", url.Values{"username": {username}}, nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("%w: user %s", vcs.ErrNotFound, username)
+	}
+	return users[0].ID, nil
+}
+
+type member struct {
+	AccessLevel int    `json:"access_level"`
+	State       string `json:"state"`
+}
+
+// GetPermissionLevel returns the permission level of the member of the project, including the inherited membership.
+func (g *gitlab) GetPermissionLevel(ctx context.Context, user string) (string, error) {
+	id, err := g.findUserID(ctx, user)
+	if err != nil {
+		if errors.Is(err, vcs.ErrNotFound) {
+			return vcs.PermissionNone, nil
+		}
+		return "", err
+	}
+	var m member
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/members/all/%d", id), nil, nil, &m); err != nil {
+		if errors.Is(err, vcs.ErrNotFound) {
+			return vcs.PermissionNone, nil
+		}
+		return "", err
+	}
+	return permissionLevel(m.AccessLevel), nil
+}
+
+// IsTeamMember reports whether the user is a member of the group, whose full path is team, or of its ancestors.
+func (g *gitlab) IsTeamMember(ctx context.Context, team, user string) (bool, error) {
+	id, err := g.findUserID(ctx, user)
+	if err != nil {
+		if errors.Is(err, vcs.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	var m member
+	path := fmt.Sprintf("/groups/%s/members/all/%d", url.PathEscape(team), id)
+	if _, err := g.do(ctx, http.MethodGet, path, nil, nil, &m); err != nil {
+		if errors.Is(err, vcs.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return m.State == "" || m.State == "active", nil
+}
+
+func (g *gitlab) MergePullRequest(ctx context.Context, params *vcs.MergePullRequestParams) error {
+	in := map[string]any{
+		"sha":                         params.SHA,
+		"squash":                      params.Method == "squash",
+		"should_remove_source_branch": params.DeleteBranch,
+	}
+	_, err := g.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d/merge", params.Number), nil, in, nil)
+	return err
+}
+
+type label struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (g *gitlab) CreateLabel(ctx context.Context, name, description, color string) error {
+	if color == "" {
+		color = "428bca"
+	}
+	in := map[string]string{
+		"name":        name,
+		"description": description,
+		"color":       "#" + strings.TrimPrefix(color, "#"),
+	}
+	_, err := g.do(ctx, http.MethodPost, g.projectPath("/labels"), nil, in, nil)
+	return err
+}
+
+func (g *gitlab) DeleteLabel(ctx context.Context, name string) error {
+	_, err := g.do(ctx, http.MethodDelete, g.projectPath("/labels/%s", url.PathEscape(name)), nil, nil, nil)
+	return err
+}
+
+func (g *gitlab) GetLabel(ctx context.Context, name string) (*vcs.Label, error) {
+	l := &label{}
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/labels/%s", url.PathEscape(name)), nil, nil, l); err != nil {
+		return nil, err
+	}
+	return &vcs.Label{
+		Name:        l.Name,
+		Description: l.Description,
+	}, nil
+}
+
+func (g *gitlab) AddPullRequestLabels(ctx context.Context, number int, labels []string) error {
+	in := map[string]string{"add_labels": strings.Join(labels, ",")}
+	_, err := g.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d", number), nil, in, nil)
+	return err
+}
+
+func (g *gitlab) ListPullRequestsByLabel(ctx context.Context, label string, limit int) ([]*vcs.PullRequest, error) {
+	query := url.Values{
+		"state":    []string{"opened"},
+		"labels":   []string{label},
+		"order_by": []string{"created_at"},
+		"sort":     []string{"asc"},
+	}
+	mrs, err := listAll[*mergeRequest](ctx, g, g.projectPath("/merge_requests"), query)
+	if err != nil {
+		return nil, err
+	}
+	prs := make([]*vcs.PullRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		if limit > 0 && len(prs) >= limit {
+			break
+		}
+		prs = append(prs, mr.pullRequest())
+	}
+	return prs, nil
+}
+
+func (g *gitlab) FindPullRequestByLabel(ctx context.Context, label string) (*vcs.PullRequest, error) {
+	prs, err := g.ListPullRequestsByLabel(ctx, label, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, vcs.ErrNotFound
+	}
+	return prs[0], nil
+}
+
+type issue struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (i *issue) issue() *vcs.Issue {
+	return &vcs.Issue{
+		Number: i.IID,
+		Title:  i.Title,
+		Body:   i.Description,
+	}
+}
+
+func (g *gitlab) FindIssueByLabel(ctx context.Context, label string) (*vcs.Issue, error) {
+	query := url.Values{
+		"state":  []string{"opened"},
+		"labels": []string{label},
+	}
+	var issues []*issue
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/issues"), query, nil, &issues); err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, vcs.ErrNotFound
+	}
+	return issues[0].issue(), nil
+}
+
+func (g *gitlab) CreateIssue(ctx context.Context, title, body string, labels []string) (*vcs.Issue, error) {
+	in := map[string]string{
+		"title":       title,
+		"description": body,
+		"labels":      strings.Join(labels, ","),
+	}
+	ret := &issue{}
+	if _, err := g.do(ctx, http.MethodPost, g.projectPath("/issues"), nil, in, ret); err != nil {
+		return nil, err
+	}
+	return ret.issue(), nil
+}
+
+func (g *gitlab) UpdateIssueBody(ctx context.Context, number int, body string) error {
+	in := map[string]string{"description": body}
+	_, err := g.do(ctx, http.MethodPut, g.projectPath("/issues/%d", number), nil, in, nil)
+	return err
+}
+
+func (g *gitlab) CloseIssue(ctx context.Context, number int) error {
+	in := map[string]string{"state_event": "close"}
+	_, err := g.do(ctx, http.MethodPut, g.projectPath("/issues/%d", number), nil, in, nil)
+	return err
+}
+
+func (g *gitlab) GetFileContent(ctx context.Context, path, ref string) ([]byte, error) {
+	var file struct {
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	query := url.Values{"ref": []string{ref}}
+	if _, err := g.do(ctx, http.MethodGet, g.projectPath("/repository/files/%s", url.PathEscape(path)), query, nil, &file); err != nil {
+		return nil, err
+	}
+	if file.Encoding != "base64" {
+		return []byte(file.Content), nil
+	}
+	return base64.StdEncoding.DecodeString(file.Content)
+}
+
+func (g *gitlab) ListRepositoryFiles(ctx context.Context, dir, ref string) ([]string, error) {
+	query := url.Values{
+		"ref":       []string{ref},
+		"recursive": []string{"true"},
+	}
+	if dir = path.Clean(dir); dir != "." {
+		query.Set("path", dir)
+	}
+	type treeEntry struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	entries, err := listAll[*treeEntry](ctx, g, g.projectPath("/repository/tree"), query)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.Type == "blob" {
+			files = append(files, entry.Path)
+		}
+	}
+	return files, nil
+}
+
+func (g *gitlab) CommitFiles(ctx context.Context, params *vcs.CommitFilesParams) (string, error) {
+	// The commits API cannot check the head of the branch, so it is checked just before the commit.
+	if params.HeadSHA != "" {
+		var branch struct {
+			Commit struct {
+				ID string `json:"id"`
+			} `json:"commit"`
+		}
+		if _, err := g.do(ctx, http.MethodGet, g.projectPath("/repository/branches/%s", url.PathEscape(params.Branch)), nil, nil, &branch); err != nil {
+			return "", err
+		}
+		if branch.Commit.ID != params.HeadSHA {
+			return "", fmt.Errorf("%w: %s is at %s, not %s", vcs.ErrOutdated, params.Branch, branch.Commit.ID, params.HeadSHA)
+		}
+	}
+	paths := make([]string, 0, len(params.Files))
+	for path := range params.Files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	actions := make([]map[string]string, 0, len(paths))
+	for _, path := range paths {
+		action := "update"
+		query := url.Values{"ref": []string{params.Branch}}
+		if _, err := g.do(ctx, http.MethodHead, g.projectPath("/repository/files/%s", url.PathEscape(path)), query, nil, nil); err != nil {
+			if !errors.Is(err, vcs.ErrNotFound) {
+				return "", err
+			}
+			action = "create"
+		}
+		actions = append(actions, map[string]string{
+			"action":    action,
+			"file_path": path,
+			"content":   params.Files[path],
+		})
+	}
+	in := map[string]any{
+		"branch":         params.Branch,
+		"commit_message": params.Message,
+		"actions":        actions,
+	}
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if _, err := g.do(ctx, http.MethodPost, g.projectPath("/repository/commits"), nil, in, &commit); err != nil {
+		return "", err
+	}
+	return commit.ID, nil
+}
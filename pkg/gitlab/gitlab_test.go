@@ -0,0 +1,457 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+type request struct {
+	method string
+	uri    string
+	body   map[string]any
+}
+
+type response struct {
+	status   int
+	body     string
+	nextPage string
+}
+
+// fakeGitlab is a stand-in of the REST API v4, which responds to "<method> <request uri>".
+type fakeGitlab struct {
+	t         *testing.T
+	responses map[string]*response
+	requests  []*request
+}
+
+func newTestGitlab(t *testing.T, responses map[string]*response) (*gitlab, *fakeGitlab) {
+	t.Helper()
+	fake := &fakeGitlab{t: t, responses: responses}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	g := New(&Params{
+		APIURL:    srv.URL + "/api/v4/",
+		Token:     "token",
+		ProjectID: "group/project",
+	}).(*gitlab)
+	return g, fake
+}
+
+func (f *fakeGitlab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(f.t, "token", r.Header.Get("PRIVATE-TOKEN"))
+	req := &request{method: r.Method, uri: r.URL.RequestURI()}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		require.NoError(f.t, json.Unmarshal(data, &req.body))
+	}
+	f.requests = append(f.requests, req)
+	resp, ok := f.responses[r.Method+" "+r.URL.RequestURI()]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+		return
+	}
+	if resp.nextPage != "" {
+		w.Header().Set("X-Next-Page", resp.nextPage)
+	}
+	status := resp.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(resp.body))
+}
+
+func TestGitlab_CreateComment(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"POST /api/v4/projects/group%2Fproject/merge_requests/1/notes": {status: http.StatusCreated, body: `{"id":10}`},
+	})
+	require.NoError(t, g.CreateComment(ctx, 1, "mu plan"))
+	require.Len(t, fake.requests, 1)
+	assert.Equal(t, map[string]any{"body": "mu plan"}, fake.requests[0].body)
+}
+
+func TestGitlab_ListComments(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/merge_requests/1/notes?order_by=created_at&per_page=100&sort=asc": {
+			body:     `[{"id":1,"body":"mu plan","author":{"username":"alice"},"created_at":"2024-01-01T00:00:00Z"}]`,
+			nextPage: "2",
+		},
+		"GET /api/v4/projects/group%2Fproject/merge_requests/1/notes?order_by=created_at&page=2&per_page=100&sort=asc": {
+			body: `[{"id":2,"body":"added 1 commit","system":true},{"id":3,"body":"<!-- mu:plan -->","author":{"username":"mu-bot"},"created_at":"2024-01-01T00:01:00Z"}]`,
+		},
+	})
+	comments, err := g.ListComments(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "1", comments[0].ID)
+	assert.Equal(t, "alice", comments[0].Author.Login)
+	assert.Equal(t, "3", comments[1].ID)
+	assert.Equal(t, int64(3), comments[1].DatabaseID)
+	assert.Equal(t, "<!-- mu:plan -->", comments[1].Body)
+	assert.Equal(t, "mu-bot", comments[1].Author.Login)
+	assert.Equal(t, "2024-01-01T00:01:00Z", comments[1].CreatedAt)
+}
+
+func TestGitlab_CreateCommentReaction(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"POST /api/v4/projects/group%2Fproject/merge_requests/1/notes/10/award_emoji": {status: http.StatusCreated, body: `{}`},
+	})
+	g.getenv = func(string) string { return "" }
+	require.NoError(t, g.CreateCommentReaction(ctx, 10, "+1"))
+	assert.Empty(t, fake.requests)
+
+	g.getenv = func(key string) string {
+		if key == "CI_MERGE_REQUEST_IID" {
+			return "1"
+		}
+		return ""
+	}
+	require.NoError(t, g.CreateCommentReaction(ctx, 10, "+1"))
+	require.Len(t, fake.requests, 1)
+	assert.Equal(t, map[string]any{"name": "thumbsup"}, fake.requests[0].body)
+}
+
+func TestGitlab_CreateCommitStatus(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"POST /api/v4/projects/group%2Fproject/statuses/sha": {status: http.StatusCreated, body: `{}`},
+	})
+	require.NoError(t, g.CreateCommitStatus(ctx, &vcs.CommitStatus{
+		Sha:       "sha",
+		Status:    vcs.FailureStatus,
+		TargetURL: "https://gitlab.example.com/pipelines/1",
+		Desc:      "Plan failed",
+		Context:   "mu/plan: test",
+	}))
+	require.Len(t, fake.requests, 1)
+	expected := map[string]any{
+		"state":       "failed",
+		"name":        "mu/plan: test",
+		"description": "Plan failed",
+		"target_url":  "https://gitlab.example.com/pipelines/1",
+	}
+	assert.Equal(t, expected, fake.requests[0].body)
+}
+
+func TestGitlab_ListCommitStatuses(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/repository/commits/sha/statuses?per_page=100": {
+			body: `[{"status":"success","name":"mu/apply: test","description":"Apply succeeded."},
+{"status":"running","name":"mu/plan: test","target_url":"https://gitlab.example.com/pipelines/1"},
+{"status":"canceled","name":"mu/apply: other"}]`,
+		},
+	})
+	statuses, err := g.ListCommitStatuses(ctx, "sha")
+	require.NoError(t, err)
+	expected := []*vcs.CommitStatus{
+		{Sha: "sha", Status: vcs.SuccessStatus, Desc: "Apply succeeded.", Context: "mu/apply: test"},
+		{Sha: "sha", Status: vcs.PendingStatus, TargetURL: "https://gitlab.example.com/pipelines/1", Context: "mu/plan: test"},
+		{Sha: "sha", Status: vcs.ErrorStatus, Context: "mu/apply: other"},
+	}
+	assert.Equal(t, expected, statuses)
+}
+
+func TestGitlab_GetPullRequest(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/merge_requests/1": {
+			body: `{"id":100,"iid":1,"title":"test","state":"opened","created_at":"2024-01-01T00:00:00Z","sha":"head","source_branch":"feature","target_branch":"main",
+"source_project_id":2,"target_project_id":1,"diff_refs":{"base_sha":"base"},"detailed_merge_status":"not_approved","labels":["mu_lock_test"]}`,
+		},
+	})
+	pr, err := g.GetPullRequest(ctx, 1)
+	require.NoError(t, err)
+	expected := &vcs.PullRequest{
+		ID:             100,
+		Number:         1,
+		Title:          "test",
+		CreatedAt:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		HeadSHA:        "head",
+		HeadRef:        "feature",
+		BaseSHA:        "base",
+		BaseRef:        "main",
+		Fork:           true,
+		MergeableState: "unstable",
+		Labels:         []*vcs.Label{{Name: "mu_lock_test"}},
+	}
+	assert.Equal(t, expected, pr)
+	assert.True(t, pr.IsMergeable())
+
+	_, err = g.GetPullRequest(ctx, 2)
+	require.ErrorIs(t, err, vcs.ErrNotFound)
+}
+
+func TestGitlab_FindMergedPullRequest(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/repository/commits/sha1/merge_requests": {
+			body: `[{"iid":1,"state":"closed"},{"iid":2,"state":"merged","detailed_merge_status":"not_open"}]`,
+		},
+		"GET /api/v4/projects/group%2Fproject/repository/commits/sha2/merge_requests": {
+			body: `[]`,
+		},
+	})
+	pr, err := g.FindMergedPullRequest(ctx, "sha1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, pr.Number)
+
+	_, err = g.FindMergedPullRequest(ctx, "sha2")
+	require.ErrorIs(t, err, vcs.ErrNotFound)
+}
+
+func TestGitlab_ListFiles(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/merge_requests/1/diffs?per_page=100": {
+			body: `[{"old_path":"a/main.tf","new_path":"a/main.tf"},{"old_path":"b/old.tf","new_path":"b/new.tf","renamed_file":true}]`,
+		},
+	})
+	files, err := g.ListFiles(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/main.tf", "b/new.tf", "b/old.tf"}, files)
+}
+
+func TestGitlab_ListReviews(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/merge_requests/1/approvals": {
+			body: `{"approved_by":[{"user":{"username":"alice"}},{"user":{"username":"bob"}}]}`,
+		},
+	})
+	reviews, err := g.ListReviews(ctx, 1)
+	require.NoError(t, err)
+	expected := vcs.Reviews{
+		{UserLogin: "alice", State: "APPROVED"},
+		{UserLogin: "bob", State: "APPROVED"},
+	}
+	assert.Equal(t, expected, reviews)
+}
+
+func TestGitlab_GetPermissionLevel(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/users?username=alice":                   {body: `[{"id":1}]`},
+		"GET /api/v4/users?username=bob":                     {body: `[{"id":2}]`},
+		"GET /api/v4/users?username=carol":                   {body: `[]`},
+		"GET /api/v4/projects/group%2Fproject/members/all/1": {body: `{"access_level":40}`},
+		"GET /api/v4/groups/group%2Fsre/members/all/1":       {body: `{"access_level":30,"state":"active"}`},
+		"GET /api/v4/groups/group%2Fsre/members/all/2":       {body: `{"access_level":30,"state":"awaiting"}`},
+	})
+
+	tests := map[string]string{
+		"alice": vcs.PermissionMaintain,
+		"bob":   vcs.PermissionNone,
+		"carol": vcs.PermissionNone,
+	}
+	for user, expected := range tests {
+		level, err := g.GetPermissionLevel(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, expected, level, user)
+	}
+
+	ok, err := g.IsTeamMember(ctx, "group/sre", "alice")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = g.IsTeamMember(ctx, "group/sre", "bob")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = g.IsTeamMember(ctx, "group/sre", "carol")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPermissionLevel(t *testing.T) {
+	t.Parallel()
+	tests := map[int]string{
+		0:  vcs.PermissionNone,
+		5:  vcs.PermissionNone,
+		10: vcs.PermissionRead,
+		20: vcs.PermissionRead,
+		30: vcs.PermissionWrite,
+		40: vcs.PermissionMaintain,
+		50: vcs.PermissionAdmin,
+	}
+	for accessLevel, expected := range tests {
+		assert.Equal(t, expected, permissionLevel(accessLevel), accessLevel)
+	}
+}
+
+func TestGitlab_Labels(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"POST /api/v4/projects/group%2Fproject/labels":                {status: http.StatusConflict, body: `{"message":"Label already exists"}`},
+		"GET /api/v4/projects/group%2Fproject/labels/mu_lock_test":    {body: `{"name":"mu_lock_test","description":"PR: #1"}`},
+		"PUT /api/v4/projects/group%2Fproject/merge_requests/1":       {body: `{}`},
+		"DELETE /api/v4/projects/group%2Fproject/labels/mu_lock_test": {status: http.StatusNoContent},
+	})
+
+	err := g.CreateLabel(ctx, "mu_lock_test", "PR: #1", "ff0000")
+	require.ErrorIs(t, err, vcs.ErrAlreadyExists)
+	assert.Equal(t, map[string]any{"name": "mu_lock_test", "description": "PR: #1", "color": "#ff0000"}, fake.requests[0].body)
+
+	label, err := g.GetLabel(ctx, "mu_lock_test")
+	require.NoError(t, err)
+	assert.Equal(t, &vcs.Label{Name: "mu_lock_test", Description: "PR: #1"}, label)
+
+	require.NoError(t, g.AddPullRequestLabels(ctx, 1, []string{"mu_lock_test", "mu_plan"}))
+	assert.Equal(t, map[string]any{"add_labels": "mu_lock_test,mu_plan"}, fake.requests[2].body)
+
+	require.NoError(t, g.DeleteLabel(ctx, "mu_lock_test"))
+	require.ErrorIs(t, g.DeleteLabel(ctx, "mu_lock_other"), vcs.ErrNotFound)
+}
+
+func TestGitlab_FindPullRequestByLabel(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/merge_requests?labels=mu_lock_test&order_by=created_at&per_page=100&sort=asc&state=opened": {
+			body: `[{"iid":3,"labels":["mu_lock_test"]}]`,
+		},
+		"GET /api/v4/projects/group%2Fproject/merge_requests?labels=mu_lock_other&order_by=created_at&per_page=100&sort=asc&state=opened": {
+			body: `[]`,
+		},
+	})
+	pr, err := g.FindPullRequestByLabel(ctx, "mu_lock_test")
+	require.NoError(t, err)
+	assert.Equal(t, 3, pr.Number)
+	assert.True(t, pr.HasLabel("mu_lock_test"))
+
+	_, err = g.FindPullRequestByLabel(ctx, "mu_lock_other")
+	require.ErrorIs(t, err, vcs.ErrNotFound)
+}
+
+func TestGitlab_Issues(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/issues?labels=mu_drift&state=opened": {body: `[]`},
+		"POST /api/v4/projects/group%2Fproject/issues":                             {status: http.StatusCreated, body: `{"iid":5,"title":"drift","description":"body"}`},
+		"PUT /api/v4/projects/group%2Fproject/issues/5":                            {body: `{}`},
+	})
+	_, err := g.FindIssueByLabel(ctx, "mu_drift")
+	require.ErrorIs(t, err, vcs.ErrNotFound)
+
+	issue, err := g.CreateIssue(ctx, "drift", "body", []string{"mu_drift"})
+	require.NoError(t, err)
+	assert.Equal(t, &vcs.Issue{Number: 5, Title: "drift", Body: "body"}, issue)
+	assert.Equal(t, map[string]any{"title": "drift", "description": "body", "labels": "mu_drift"}, fake.requests[1].body)
+
+	require.NoError(t, g.UpdateIssueBody(ctx, 5, "updated"))
+	assert.Equal(t, map[string]any{"description": "updated"}, fake.requests[2].body)
+	require.NoError(t, g.CloseIssue(ctx, 5))
+	assert.Equal(t, map[string]any{"state_event": "close"}, fake.requests[3].body)
+}
+
+func TestGitlab_CommitFiles(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"HEAD /api/v4/projects/group%2Fproject/repository/files/a%2Fstate.json?ref=mu": {},
+		"POST /api/v4/projects/group%2Fproject/repository/commits":                     {status: http.StatusCreated, body: `{"id":"commit"}`},
+	})
+	sha, err := g.CommitFiles(ctx, &vcs.CommitFilesParams{
+		Branch:  "mu",
+		Message: "update",
+		Files: map[string]string{
+			"a/state.json": "a",
+			"b/state.json": "b",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "commit", sha)
+	expected := map[string]any{
+		"branch":         "mu",
+		"commit_message": "update",
+		"actions": []any{
+			map[string]any{"action": "update", "file_path": "a/state.json", "content": "a"},
+			map[string]any{"action": "create", "file_path": "b/state.json", "content": "b"},
+		},
+	}
+	assert.Equal(t, expected, fake.requests[2].body)
+}
+
+func TestGitlab_CommitFiles_HeadSHA(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/repository/branches/feature": {body: `{"commit":{"id":"head"}}`},
+		"POST /api/v4/projects/group%2Fproject/repository/commits":         {status: http.StatusCreated, body: `{"id":"commit"}`},
+	})
+	params := &vcs.CommitFilesParams{
+		Branch:  "feature",
+		HeadSHA: "head",
+		Message: "mu fmt",
+		Files:   map[string]string{"main.tf": "a"},
+	}
+	sha, err := g.CommitFiles(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, "commit", sha)
+
+	params.HeadSHA = "old"
+	fake.requests = nil
+	_, err = g.CommitFiles(ctx, params)
+	require.ErrorIs(t, err, vcs.ErrOutdated)
+	assert.Len(t, fake.requests, 1)
+}
+
+func TestGitlab_ListRepositoryFiles(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/repository/tree?path=test&per_page=100&recursive=true&ref=sha": {
+			body: `[{"path":"test/modules","type":"tree"},{"path":"test/main.tf","type":"blob"},{"path":"test/modules/main.tf","type":"blob"}]`,
+		},
+	})
+	files, err := g.ListRepositoryFiles(ctx, "./test", "sha")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test/main.tf", "test/modules/main.tf"}, files)
+}
+
+func TestGitlab_GetFileContent(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"GET /api/v4/projects/group%2Fproject/repository/files/.github%2Fmu.yaml?ref=main": {
+			body: `{"encoding":"base64","content":"dmVyc2lvbjogMQo="}`,
+		},
+	})
+	content, err := g.GetFileContent(ctx, ".github/mu.yaml", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "version: 1\n", string(content))
+
+	_, err = g.GetFileContent(ctx, ".github/mu.yaml", "feature")
+	require.ErrorIs(t, err, vcs.ErrNotFound)
+}
+
+func TestGitlab_MergePullRequest(t *testing.T) {
+	ctx := context.Background()
+	g, fake := newTestGitlab(t, map[string]*response{
+		"PUT /api/v4/projects/group%2Fproject/merge_requests/1/merge": {body: `{}`},
+	})
+	require.NoError(t, g.MergePullRequest(ctx, &vcs.MergePullRequestParams{
+		Number:       1,
+		SHA:          "sha",
+		Method:       "squash",
+		DeleteBranch: true,
+	}))
+	expected := map[string]any{"sha": "sha", "squash": true, "should_remove_source_branch": true}
+	assert.Equal(t, expected, fake.requests[0].body)
+}
+
+func TestGitlab_unexpectedStatus(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestGitlab(t, map[string]*response{
+		"POST /api/v4/projects/group%2Fproject/merge_requests/1/notes": {status: http.StatusForbidden, body: `{"message":"403 Forbidden"}`},
+	})
+	err := g.CreateComment(ctx, 1, "mu plan")
+	require.ErrorIs(t, err, errUnexpectedStatus)
+	assert.Contains(t, err.Error(), "403 Forbidden")
+}
@@ -0,0 +1,62 @@
+// Package hook runs the shell commands configured around the Terraform steps of a project.
+package hook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Shell is the shell which runs the hook commands.
+const Shell = "sh"
+
+type Params struct {
+	Command string
+	// Dir is the working directory of the command.
+	Dir string
+	// Env is added to the environment of the current process.
+	Env []string
+	// Stream receives the combined output while the command runs.
+	Stream io.Writer
+}
+
+type Output struct {
+	// Result is the combined stdout and stderr of the command.
+	Result   string
+	HasError bool
+	Error    error
+}
+
+// Run runs the command with `sh -c`.
+// A non-zero exit status is reported as Output.HasError, and the error is only returned if the command cannot be run.
+func Run(ctx context.Context, params *Params) (*Output, error) {
+	buf := new(strings.Builder)
+	cmd := exec.CommandContext(ctx, Shell, "-c", params.Command) // #nosec G204
+	cmd.Dir = params.Dir
+	cmd.Env = append(os.Environ(), params.Env...)
+	var w io.Writer = buf
+	if params.Stream != nil {
+		w = io.MultiWriter(buf, params.Stream)
+	}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+		out := &Output{
+			Result:   buf.String(),
+			HasError: true,
+			Error:    err,
+		}
+		return out, nil
+	}
+	return &Output{
+		Result: buf.String(),
+	}, nil
+}
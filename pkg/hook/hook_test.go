@@ -0,0 +1,61 @@
+package hook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		params *Params
+		want   *Output
+	}{
+		"success": {
+			params: &Params{
+				Command: `echo "$MU_PROJECT" && pwd`,
+				Dir:     "/",
+				Env:     []string{"MU_PROJECT=test"},
+			},
+			want: &Output{
+				Result: "test\n/\n",
+			},
+		},
+		"failure": {
+			params: &Params{
+				Command: "echo error >&2; exit 3",
+			},
+			want: &Output{
+				Result:   "error\n",
+				HasError: true,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			stream := new(strings.Builder)
+			tt.params.Stream = stream
+			got, err := Run(context.Background(), tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Result, got.Result)
+			assert.Equal(t, tt.want.HasError, got.HasError)
+			assert.Equal(t, tt.want.HasError, got.Error != nil)
+			assert.Equal(t, tt.want.Result, stream.String())
+		})
+	}
+}
+
+func TestRun_NotFoundDir(t *testing.T) {
+	t.Parallel()
+	_, err := Run(context.Background(), &Params{
+		Command: "true",
+		Dir:     "/not/found",
+	})
+	assert.Error(t, err)
+}
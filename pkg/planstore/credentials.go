@@ -0,0 +1,211 @@
+package planstore
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialsRefreshWindow is how long before the expiry the temporary credentials are refreshed,
+// so that a request is not signed with the credentials which expire on the way.
+const credentialsRefreshWindow = 5 * time.Minute
+
+var errNoCredentials = errors.New("no credentials")
+
+// credentialsProvider returns the credentials to sign the requests.
+type credentialsProvider interface {
+	retrieve(ctx context.Context) (*credentials, error)
+}
+
+type staticCredentials credentials
+
+func (c *staticCredentials) retrieve(context.Context) (*credentials, error) {
+	return (*credentials)(c), nil
+}
+
+// cachedCredentials keeps the temporary credentials from fetch, and fetches them again before they expire.
+type cachedCredentials struct {
+	fetch  func(ctx context.Context) (*credentials, error)
+	now    func() time.Time
+	locker sync.Mutex
+	creds  *credentials
+}
+
+func (c *cachedCredentials) retrieve(ctx context.Context) (*credentials, error) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	if c.creds != nil && c.now().Add(credentialsRefreshWindow).Before(c.creds.expiry) {
+		return c.creds, nil
+	}
+	creds, err := c.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.creds = creds
+	return creds, nil
+}
+
+// newCredentialsProvider returns the provider of the first available credentials: the static access key,
+// the web identity token such as IRSA on EKS, and the container credentials endpoint such as of ECS tasks.
+func newCredentialsProvider(params *S3Params, cli *http.Client, now func() time.Time) credentialsProvider {
+	switch {
+	case params.AccessKeyID != "":
+		return &staticCredentials{
+			accessKeyID:     params.AccessKeyID,
+			secretAccessKey: params.SecretAccessKey,
+			sessionToken:    params.SessionToken,
+		}
+	case params.WebIdentityTokenFile != "":
+		w := &webIdentityCredentials{
+			tokenFile:   params.WebIdentityTokenFile,
+			roleARN:     params.RoleARN,
+			sessionName: params.RoleSessionName,
+			endpoint:    params.STSEndpoint,
+			cli:         cli,
+			now:         now,
+		}
+		if w.sessionName == "" {
+			w.sessionName = fmt.Sprintf("mu-%d", now().UnixNano())
+		}
+		if w.endpoint == "" {
+			w.endpoint = fmt.Sprintf("https://sts.%s.amazonaws.com", params.Region)
+		}
+		return &cachedCredentials{fetch: w.fetch, now: now}
+	case params.ContainerCredentialsURL != "":
+		c := &containerCredentials{
+			url:       params.ContainerCredentialsURL,
+			token:     params.ContainerAuthorizationToken,
+			tokenFile: params.ContainerAuthorizationTokenFile,
+			cli:       cli,
+		}
+		return &cachedCredentials{fetch: c.fetch, now: now}
+	default:
+		return &staticCredentials{}
+	}
+}
+
+// webIdentityCredentials assumes the role with the OIDC token in the file, which is rotated by the platform,
+// so the file is read on every fetch.
+// See: https://docs.aws.amazon.com/STS/latest/APIReference/API_AssumeRoleWithWebIdentity.html
+type webIdentityCredentials struct {
+	tokenFile   string
+	roleARN     string
+	sessionName string
+	endpoint    string
+	cli         *http.Client
+	now         func() time.Time
+}
+
+type assumeRoleWithWebIdentityResponse struct {
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+}
+
+func (w *webIdentityCredentials) fetch(ctx context.Context) (*credentials, error) {
+	if w.roleARN == "" {
+		return nil, fmt.Errorf("%w: the role ARN is required with the web identity token", errNoCredentials)
+	}
+	token, err := os.ReadFile(w.tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"Action":           []string{"AssumeRoleWithWebIdentity"},
+		"Version":          []string{"2011-06-15"},
+		"RoleArn":          []string{w.roleARN},
+		"RoleSessionName":  []string{w.sessionName},
+		"WebIdentityToken": []string{strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := w.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to assume role with web identity: %w", unexpectedStatus(resp))
+	}
+	ret := &assumeRoleWithWebIdentityResponse{}
+	if err := xml.NewDecoder(resp.Body).Decode(ret); err != nil {
+		return nil, err
+	}
+	return &credentials{
+		accessKeyID:     ret.Credentials.AccessKeyID,
+		secretAccessKey: ret.Credentials.SecretAccessKey,
+		sessionToken:    ret.Credentials.SessionToken,
+		expiry:          ret.Credentials.Expiration,
+	}, nil
+}
+
+// containerCredentials gets the credentials from the endpoint of the container runtime.
+// The authorization token in the file is rotated, e.g. by EKS Pod Identity, so the file is read on every fetch.
+// See: https://docs.aws.amazon.com/sdkref/latest/guide/feature-container-credentials.html
+type containerCredentials struct {
+	url       string
+	token     string
+	tokenFile string
+	cli       *http.Client
+}
+
+type containerCredentialsResponse struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+func (c *containerCredentials) fetch(ctx context.Context) (*credentials, error) {
+	token := c.token
+	if c.tokenFile != "" {
+		data, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get container credentials: %w", unexpectedStatus(resp))
+	}
+	ret := &containerCredentialsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(ret); err != nil {
+		return nil, err
+	}
+	return &credentials{
+		accessKeyID:     ret.AccessKeyID,
+		secretAccessKey: ret.SecretAccessKey,
+		sessionToken:    ret.Token,
+		expiry:          ret.Expiration,
+	}, nil
+}
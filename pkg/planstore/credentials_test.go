@@ -0,0 +1,129 @@
+package planstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCredentialsProvider_WebIdentity(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var fetched atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/mu", r.PostForm.Get("RoleArn"))
+		assert.Equal(t, "mu-test", r.PostForm.Get("RoleSessionName"))
+		assert.Equal(t, "test-oidc-token", r.PostForm.Get("WebIdentityToken"))
+		n := fetched.Add(1)
+		_, _ = fmt.Fprintf(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>test-access-key-%d</AccessKeyId>
+      <SecretAccessKey>test-secret-key</SecretAccessKey>
+      <SessionToken>test-session-token</SessionToken>
+      <Expiration>2025-01-01T01:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`, n)
+	}))
+	defer srv.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("test-oidc-token\n"), 0600))
+
+	provider := newCredentialsProvider(&S3Params{
+		WebIdentityTokenFile: tokenFile,
+		RoleARN:              "arn:aws:iam::123456789012:role/mu",
+		RoleSessionName:      "mu-test",
+		STSEndpoint:          srv.URL,
+	}, srv.Client(), func() time.Time { return now })
+	ctx := context.Background()
+	creds, err := provider.retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &credentials{
+		accessKeyID:     "test-access-key-1",
+		secretAccessKey: "test-secret-key",
+		sessionToken:    "test-session-token",
+		expiry:          time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC),
+	}, creds)
+
+	// The credentials are cached until they are about to expire.
+	creds, err = provider.retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "test-access-key-1", creds.accessKeyID)
+	now = now.Add(56 * time.Minute)
+	creds, err = provider.retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "test-access-key-2", creds.accessKeyID)
+	assert.Equal(t, int32(2), fetched.Load())
+}
+
+func TestNewCredentialsProvider_Container(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "test-auth-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"AccessKeyId":"test-access-key","SecretAccessKey":"test-secret-key",
+"Token":"test-session-token","Expiration":"2025-01-01T01:00:00Z"}`))
+	}))
+	defer srv.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("test-auth-token"), 0600))
+	now := func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	provider := newCredentialsProvider(&S3Params{
+		ContainerCredentialsURL:         srv.URL + "/v2/credentials",
+		ContainerAuthorizationTokenFile: tokenFile,
+	}, srv.Client(), now)
+	creds, err := provider.retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &credentials{
+		accessKeyID:     "test-access-key",
+		secretAccessKey: "test-secret-key",
+		sessionToken:    "test-session-token",
+		expiry:          time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC),
+	}, creds)
+
+	provider = newCredentialsProvider(&S3Params{
+		ContainerCredentialsURL: srv.URL + "/v2/credentials",
+	}, srv.Client(), now)
+	_, err = provider.retrieve(ctx)
+	require.ErrorIs(t, err, errUnexpectedStatus)
+}
+
+func TestNewCredentialsProvider_Static(t *testing.T) {
+	t.Parallel()
+	// The static credentials take precedence over the others.
+	provider := newCredentialsProvider(&S3Params{
+		AccessKeyID:             "test-access-key",
+		SecretAccessKey:         "test-secret-key",
+		WebIdentityTokenFile:    "/nonexistent",
+		ContainerCredentialsURL: "http://169.254.170.2/v2/credentials",
+	}, http.DefaultClient, time.Now)
+	creds, err := provider.retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &credentials{accessKeyID: "test-access-key", secretAccessKey: "test-secret-key"}, creds)
+}
+
+func TestNewCredentialsProvider_WebIdentityWithoutRole(t *testing.T) {
+	t.Parallel()
+	provider := newCredentialsProvider(&S3Params{
+		Region:               "us-east-1",
+		WebIdentityTokenFile: "/nonexistent",
+	}, http.DefaultClient, time.Now)
+	_, err := provider.retrieve(context.Background())
+	require.ErrorIs(t, err, errNoCredentials)
+}
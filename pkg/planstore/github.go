@@ -0,0 +1,101 @@
+package planstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/archive"
+	"github.com/yu-icchi/mu/pkg/artifact"
+	"github.com/yu-icchi/mu/pkg/github"
+)
+
+type GithubParams struct {
+	Github   github.Github
+	Artifact artifact.Client
+	Archiver archive.Archive
+}
+
+// githubStore keeps the plan files in the GitHub Actions Artifacts.
+type githubStore struct {
+	github   github.Github
+	artifact artifact.Client
+	archiver archive.Archive
+}
+
+func NewGithub(params *GithubParams) PlanStore {
+	return &githubStore{
+		github:   params.Github,
+		artifact: params.Artifact,
+		archiver: params.Archiver,
+	}
+}
+
+func (g *githubStore) Put(ctx context.Context, name string, files []string) (string, error) {
+	dir, err := os.MkdirTemp("", "mu_plan")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	archivePath := filepath.Join(dir, name+archiveExt)
+	if err := g.archiver.Compress(archivePath, files); err != nil {
+		return "", err
+	}
+
+	// The plans of the same name are outdated, and artifact names must be unique within a workflow run.
+	if err := g.github.DeleteArtifactsByNames(ctx, []string{name}); err != nil {
+		return "", err
+	}
+	uploaded, err := g.artifact.Upload(ctx, name, archivePath)
+	if err != nil {
+		return "", err
+	}
+	runURL := action.RunURL()
+	if runURL == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("%s/artifacts/%d", runURL, uploaded.ID), nil
+}
+
+func (g *githubStore) Get(ctx context.Context, name, dir string) error {
+	artifacts, err := g.github.MultiGetArtifactsByNames(ctx, []string{name})
+	if err != nil {
+		return err
+	}
+	artifactFile := artifacts.Get(name)
+	if artifactFile == nil {
+		return ErrNotFound
+	}
+
+	path := filepath.Join(dir, name+archiveExt)
+	if err := g.download(ctx, path, artifactFile.ID); err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(path)
+	}()
+	return g.archiver.Decompress(dir, path)
+}
+
+func (g *githubStore) download(ctx context.Context, path string, artifactID int64) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return g.github.DownloadArtifact(ctx, artifactID, file)
+}
+
+func (g *githubStore) Delete(ctx context.Context, names []string) error {
+	return g.github.DeleteArtifactsByNames(ctx, names)
+}
+
+func (g *githubStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return g.github.ListArtifactNames(ctx, prefix)
+}
@@ -0,0 +1,152 @@
+package planstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/archive"
+	archiveMock "github.com/yu-icchi/mu/pkg/archive/mock"
+	"github.com/yu-icchi/mu/pkg/artifact"
+	artifactMock "github.com/yu-icchi/mu/pkg/artifact/mock"
+	"github.com/yu-icchi/mu/pkg/github"
+	githubMock "github.com/yu-icchi/mu/pkg/github/mock"
+)
+
+func TestGithubStore_Put(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "test/mu")
+	t.Setenv("GITHUB_RUN_ID", "test-run-id")
+	tests := []struct {
+		name      string
+		prepare   func(ctx context.Context, gh *githubMock.MockGithub, cli *artifactMock.MockClient)
+		expect    string
+		expectErr error
+	}{
+		{
+			name: "success",
+			prepare: func(ctx context.Context, gh *githubMock.MockGithub, cli *artifactMock.MockClient) {
+				gh.EXPECT().DeleteArtifactsByNames(ctx, []string{"mu_test_default_1"}).Return(nil)
+				cli.EXPECT().Upload(ctx, "mu_test_default_1", gomock.Any()).
+					DoAndReturn(func(_ context.Context, name, path string) (*artifact.Artifact, error) {
+						assert.Equal(t, "mu_test_default_1.zip", filepath.Base(path))
+						return &artifact.Artifact{ID: 123, Name: name, Size: 8}, nil
+					})
+			},
+			expect: "https://github.com/test/mu/actions/runs/test-run-id/artifacts/123",
+		},
+		{
+			name: "failed to upload",
+			prepare: func(ctx context.Context, gh *githubMock.MockGithub, cli *artifactMock.MockClient) {
+				gh.EXPECT().DeleteArtifactsByNames(ctx, []string{"mu_test_default_1"}).Return(nil)
+				cli.EXPECT().Upload(ctx, "mu_test_default_1", gomock.Any()).Return(nil, assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "failed to delete old plans",
+			prepare: func(ctx context.Context, gh *githubMock.MockGithub, cli *artifactMock.MockClient) {
+				gh.EXPECT().DeleteArtifactsByNames(ctx, []string{"mu_test_default_1"}).Return(assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			gh := githubMock.NewMockGithub(ctrl)
+			cli := artifactMock.NewMockClient(ctrl)
+			dir := t.TempDir()
+			planFile := filepath.Join(dir, "test_default_1.tfplan")
+			require.NoError(t, os.WriteFile(planFile, []byte("plan data"), 0600))
+			store := NewGithub(&GithubParams{
+				Github:   gh,
+				Artifact: cli,
+				Archiver: archive.NewZipArchiver(),
+			})
+			ctx := context.Background()
+			tt.prepare(ctx, gh, cli)
+
+			url, err := store.Put(ctx, "mu_test_default_1", []string{planFile})
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, url)
+		})
+	}
+}
+
+func TestGithubStore_Get(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		prepare   func(ctx context.Context, gh *githubMock.MockGithub, archiver *archiveMock.MockArchive, dir string)
+		expectErr error
+	}{
+		{
+			name: "success",
+			prepare: func(ctx context.Context, gh *githubMock.MockGithub, archiver *archiveMock.MockArchive, dir string) {
+				gh.EXPECT().MultiGetArtifactsByNames(ctx, []string{"mu_test_default_1"}).Return(github.Artifacts{
+					"mu_test_default_1": &github.Artifact{ID: 1, Name: "mu_test_default_1"},
+				}, nil)
+				gh.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, file io.Writer) error {
+						_, err := io.Copy(file, strings.NewReader("zip data"))
+						return err
+					})
+				archiver.EXPECT().Decompress(dir, filepath.Join(dir, "mu_test_default_1.zip")).Return(nil)
+			},
+		},
+		{
+			name: "not found",
+			prepare: func(ctx context.Context, gh *githubMock.MockGithub, archiver *archiveMock.MockArchive, dir string) {
+				gh.EXPECT().MultiGetArtifactsByNames(ctx, []string{"mu_test_default_1"}).Return(github.Artifacts{}, nil)
+			},
+			expectErr: ErrNotFound,
+		},
+		{
+			name: "failed to decompress",
+			prepare: func(ctx context.Context, gh *githubMock.MockGithub, archiver *archiveMock.MockArchive, dir string) {
+				gh.EXPECT().MultiGetArtifactsByNames(ctx, []string{"mu_test_default_1"}).Return(github.Artifacts{
+					"mu_test_default_1": &github.Artifact{ID: 1, Name: "mu_test_default_1"},
+				}, nil)
+				gh.EXPECT().DownloadArtifact(ctx, int64(1), gomock.Any()).Return(nil)
+				archiver.EXPECT().Decompress(dir, filepath.Join(dir, "mu_test_default_1.zip")).Return(assert.AnError)
+			},
+			expectErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			gh := githubMock.NewMockGithub(ctrl)
+			archiver := archiveMock.NewMockArchive(ctrl)
+			dir := t.TempDir()
+			ctx := context.Background()
+			tt.prepare(ctx, gh, archiver, dir)
+			store := NewGithub(&GithubParams{
+				Github:   gh,
+				Archiver: archiver,
+			})
+			err := store.Get(ctx, "mu_test_default_1", dir)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			_, err = os.Stat(filepath.Join(dir, "mu_test_default_1.zip"))
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
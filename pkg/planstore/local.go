@@ -0,0 +1,79 @@
+package planstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yu-icchi/mu/pkg/archive"
+)
+
+const archiveExt = ".zip"
+
+// localStore keeps the plan files as zip files in a local or shared (e.g. NFS) directory.
+type localStore struct {
+	dir      string
+	archiver archive.Archive
+}
+
+func NewLocal(dir string, archiver archive.Archive) PlanStore {
+	return &localStore{
+		dir:      dir,
+		archiver: archiver,
+	}
+}
+
+func (l *localStore) path(name string) string {
+	return filepath.Join(l.dir, name+archiveExt)
+}
+
+func (l *localStore) Put(_ context.Context, name string, files []string) (string, error) {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return "", err
+	}
+	return "", l.archiver.Compress(l.path(name), files)
+}
+
+func (l *localStore) Get(_ context.Context, name, dir string) error {
+	path := l.path(name)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return l.archiver.Decompress(dir, path)
+}
+
+func (l *localStore) Delete(_ context.Context, names []string) error {
+	for _, name := range names {
+		if err := os.Remove(l.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *localStore) List(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name, ok := strings.CutSuffix(entry.Name(), archiveExt)
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
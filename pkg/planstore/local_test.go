@@ -0,0 +1,48 @@
+package planstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yu-icchi/mu/pkg/archive"
+)
+
+func TestLocalStore(t *testing.T) {
+	t.Parallel()
+	store := NewLocal(filepath.Join(t.TempDir(), "plans"), archive.NewZipArchiver())
+	ctx := context.Background()
+
+	names, err := store.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	src := t.TempDir()
+	planFile := filepath.Join(src, "test_default_1.tfplan")
+	require.NoError(t, os.WriteFile(planFile, []byte("plan data"), 0644))
+	_, err = store.Put(ctx, "mu_test_default_1", []string{planFile})
+	require.NoError(t, err)
+
+	names, err = store.List(ctx, "mu_test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mu_test_default_1"}, names)
+	names, err = store.List(ctx, "mu_sample")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	dest := t.TempDir()
+	err = store.Get(ctx, "mu_test_default_1", dest)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dest, "test_default_1.tfplan"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan data", string(data))
+
+	err = store.Delete(ctx, []string{"mu_test_default_1", "mu_unknown"})
+	require.NoError(t, err)
+	err = store.Get(ctx, "mu_test_default_1", dest)
+	require.ErrorIs(t, err, ErrNotFound)
+}
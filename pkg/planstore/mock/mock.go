@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: planstore.go
+//
+// Generated by this command:
+//
+//	mockgen -source=planstore.go -package=mock -destination=mock/mock.go PlanStore
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPlanStore is a mock of PlanStore interface.
+type MockPlanStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlanStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockPlanStoreMockRecorder is the mock recorder for MockPlanStore.
+type MockPlanStoreMockRecorder struct {
+	mock *MockPlanStore
+}
+
+// NewMockPlanStore creates a new mock instance.
+func NewMockPlanStore(ctrl *gomock.Controller) *MockPlanStore {
+	mock := &MockPlanStore{ctrl: ctrl}
+	mock.recorder = &MockPlanStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlanStore) EXPECT() *MockPlanStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPlanStore) Delete(ctx context.Context, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPlanStoreMockRecorder) Delete(ctx, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPlanStore)(nil).Delete), ctx, names)
+}
+
+// Get mocks base method.
+func (m *MockPlanStore) Get(ctx context.Context, name, dir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name, dir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPlanStoreMockRecorder) Get(ctx, name, dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPlanStore)(nil).Get), ctx, name, dir)
+}
+
+// List mocks base method.
+func (m *MockPlanStore) List(ctx context.Context, prefix string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, prefix)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPlanStoreMockRecorder) List(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPlanStore)(nil).List), ctx, prefix)
+}
+
+// Put mocks base method.
+func (m *MockPlanStore) Put(ctx context.Context, name string, files []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, name, files)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockPlanStoreMockRecorder) Put(ctx, name, files any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockPlanStore)(nil).Put), ctx, name, files)
+}
@@ -0,0 +1,25 @@
+package planstore
+
+//go:generate mkdir -p mock
+//go:generate mockgen -source=planstore.go -package=mock -destination=mock/mock.go PlanStore
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("plan is not found")
+
+// PlanStore keeps the plan files between `mu plan` and `mu apply`.
+type PlanStore interface {
+	// Put stores the files as the plan named name.
+	// It returns the URL of the stored plan, or an empty string if the backend has no URL to show.
+	Put(ctx context.Context, name string, files []string) (string, error)
+	// Get restores the files of the plan named name into dir.
+	// It returns ErrNotFound if the plan does not exist.
+	Get(ctx context.Context, name, dir string) error
+	// Delete deletes the plans. Plans that do not exist are ignored.
+	Delete(ctx context.Context, names []string) error
+	// List returns the names of the stored plans which start with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
@@ -0,0 +1,291 @@
+package planstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yu-icchi/mu/pkg/archive"
+)
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+type S3Params struct {
+	Bucket string
+	// Prefix is prepended to the object keys.
+	Prefix string
+	Region string
+	// Endpoint is the URL of an S3-compatible storage such as MinIO.
+	// The bucket is addressed in the path style when the endpoint is set.
+	Endpoint string
+	// AccessKeyID, SecretAccessKey and SessionToken are the static credentials, which take precedence over the others.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// WebIdentityTokenFile is the file of the OIDC token to assume RoleARN, e.g. of IRSA on EKS.
+	// It is used when the static credentials are not set.
+	WebIdentityTokenFile string
+	RoleARN              string
+	// RoleSessionName is the name of the assumed role session. A unique name is generated if it is empty.
+	RoleSessionName string
+	// STSEndpoint is the URL of AWS STS. The regional endpoint of Region is used if it is empty.
+	STSEndpoint string
+	// ContainerCredentialsURL is the endpoint of the container credentials, e.g. of ECS tasks and EKS Pod Identity.
+	// It is used when neither the static credentials nor the web identity token are set.
+	// The authorization token is read from ContainerAuthorizationTokenFile if it is set.
+	ContainerCredentialsURL         string
+	ContainerAuthorizationToken     string
+	ContainerAuthorizationTokenFile string
+	Archiver                        archive.Archive
+	HTTPClient                      *http.Client
+}
+
+// s3Store keeps the plan files as zip objects in an S3-compatible object storage.
+type s3Store struct {
+	bucket   string
+	prefix   string
+	region   string
+	endpoint string
+	creds    credentialsProvider
+	archiver archive.Archive
+	cli      *http.Client
+	now      func() time.Time
+}
+
+func NewS3(params *S3Params) PlanStore {
+	cli := params.HTTPClient
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	return &s3Store{
+		bucket:   params.Bucket,
+		prefix:   strings.Trim(params.Prefix, "/"),
+		region:   params.Region,
+		endpoint: strings.TrimSuffix(params.Endpoint, "/"),
+		creds:    newCredentialsProvider(params, cli, time.Now),
+		archiver: params.Archiver,
+		cli:      cli,
+		now:      time.Now,
+	}
+}
+
+func (s *s3Store) key(name string) string {
+	return path.Join(s.prefix, name)
+}
+
+func (s *s3Store) url(key string, query url.Values) (*url.URL, error) {
+	var (
+		u   *url.URL
+		err error
+	)
+	if s.endpoint != "" {
+		u, err = url.Parse(s.endpoint)
+		if err != nil {
+			return nil, err
+		}
+		u.Path = path.Join("/", u.Path, s.bucket, key)
+	} else {
+		u = &url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region),
+			Path:   "/" + key,
+		}
+	}
+	if key == "" && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = query.Encode()
+	return u, nil
+}
+
+func (s *s3Store) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u, err := s.url(key, query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	creds, err := s.creds.retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	signV4(req, payloadHash, s.region, "s3", creds, s.now())
+	return s.cli.Do(req)
+}
+
+func (s *s3Store) Put(ctx context.Context, name string, files []string) (string, error) {
+	dir, err := os.MkdirTemp("", "mu_plan")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	archivePath := filepath.Join(dir, name+archiveExt)
+	if err := s.archiver.Compress(archivePath, files); err != nil {
+		return "", err
+	}
+	body, err := os.ReadFile(archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, s.key(name+archiveExt), nil, body)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", unexpectedStatus(resp)
+	}
+	return "", nil
+}
+
+func (s *s3Store) Get(ctx context.Context, name, dir string) error {
+	resp, err := s.do(ctx, http.MethodGet, s.key(name+archiveExt), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return unexpectedStatus(resp)
+	}
+
+	archivePath := filepath.Join(dir, name+archiveExt)
+	if err := s.save(archivePath, resp.Body); err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(archivePath)
+	}()
+	return s.archiver.Decompress(dir, archivePath)
+}
+
+func (s *s3Store) save(path string, r io.Reader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	_, err = io.Copy(file, r)
+	return err
+}
+
+func (s *s3Store) Delete(ctx context.Context, names []string) error {
+	for _, name := range names {
+		if err := s.delete(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *s3Store) delete(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.key(name+archiveExt), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return unexpectedStatus(resp)
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	keyPrefix := s.key(prefix)
+	if prefix == "" && s.prefix != "" {
+		keyPrefix += "/"
+	}
+	names := make([]string, 0)
+	var token string
+	for {
+		query := url.Values{
+			"list-type": []string{"2"},
+			"prefix":    []string{keyPrefix},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		ret, err := s.list(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range ret.Contents {
+			name := strings.TrimPrefix(content.Key, s.prefix)
+			name = strings.TrimPrefix(name, "/")
+			name, ok := strings.CutSuffix(name, archiveExt)
+			if !ok || strings.Contains(name, "/") {
+				continue
+			}
+			names = append(names, name)
+		}
+		if !ret.IsTruncated {
+			break
+		}
+		token = ret.NextContinuationToken
+	}
+	return names, nil
+}
+
+func (s *s3Store) list(ctx context.Context, query url.Values) (*listBucketResult, error) {
+	resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus(resp)
+	}
+	ret := &listBucketResult{}
+	if err := xml.NewDecoder(resp.Body).Decode(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func unexpectedStatus(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%w: %s %s", errUnexpectedStatus, resp.Status, strings.TrimSpace(string(body)))
+}
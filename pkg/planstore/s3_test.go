@@ -0,0 +1,177 @@
+package planstore
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yu-icchi/mu/pkg/archive"
+)
+
+// fakeS3 is an in-process stand-in of the S3 REST API with path-style addressing.
+type fakeS3 struct {
+	t       *testing.T
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(t *testing.T, bucket string) *httptest.Server {
+	f := &fakeS3{
+		t:       t,
+		bucket:  bucket,
+		objects: map[string][]byte{},
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-access-key/") ||
+		r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	bucketPrefix := "/" + f.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, bucketPrefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, bucketPrefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		require.NoError(f.t, err)
+		f.objects[key] = body
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key string `xml:"Key"`
+	}
+	ret := struct {
+		XMLName     xml.Name  `xml:"ListBucketResult"`
+		Contents    []content `xml:"Contents"`
+		IsTruncated bool      `xml:"IsTruncated"`
+	}{}
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		ret.Contents = append(ret.Contents, content{Key: key})
+	}
+	_ = xml.NewEncoder(w).Encode(ret)
+}
+
+func TestS3Store(t *testing.T) {
+	t.Parallel()
+	srv := newFakeS3(t, "test-bucket")
+	store := NewS3(&S3Params{
+		Bucket:          "test-bucket",
+		Prefix:          "plans",
+		Region:          "us-east-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		Archiver:        archive.NewZipArchiver(),
+		HTTPClient:      srv.Client(),
+	})
+	ctx := context.Background()
+
+	src := t.TempDir()
+	planFile := filepath.Join(src, "test_default_1.tfplan")
+	require.NoError(t, os.WriteFile(planFile, []byte("plan data"), 0644))
+	metaFile := filepath.Join(src, "test_default_1.tfplan.meta.json")
+	require.NoError(t, os.WriteFile(metaFile, []byte("{}"), 0644))
+
+	_, err := store.Put(ctx, "mu_test_default_1", []string{planFile, metaFile})
+	require.NoError(t, err)
+
+	names, err := store.List(ctx, "mu_")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mu_test_default_1"}, names)
+
+	dest := t.TempDir()
+	err = store.Get(ctx, "mu_test_default_1", dest)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dest, "test_default_1.tfplan"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan data", string(data))
+	_, err = os.Stat(filepath.Join(dest, "test_default_1.tfplan.meta.json"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dest, "mu_test_default_1.zip"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	err = store.Delete(ctx, []string{"mu_test_default_1", "mu_unknown"})
+	require.NoError(t, err)
+
+	err = store.Get(ctx, "mu_test_default_1", dest)
+	require.ErrorIs(t, err, ErrNotFound)
+	names, err = store.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestS3Store_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+	srv := newFakeS3(t, "test-bucket")
+	store := NewS3(&S3Params{
+		Bucket:          "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "invalid-access-key",
+		SecretAccessKey: "test-secret-key",
+		Archiver:        archive.NewZipArchiver(),
+		HTTPClient:      srv.Client(),
+	})
+	_, err := store.List(context.Background(), "")
+	require.ErrorIs(t, err, errUnexpectedStatus)
+}
+
+func TestS3Store_url(t *testing.T) {
+	t.Parallel()
+	store := NewS3(&S3Params{
+		Bucket: "test-bucket",
+		Prefix: "plans/",
+		Region: "ap-northeast-1",
+	}).(*s3Store)
+	u, err := store.url(store.key("mu_test_default_1.zip"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://test-bucket.s3.ap-northeast-1.amazonaws.com/plans/mu_test_default_1.zip", u.String())
+}
@@ -0,0 +1,58 @@
+package server
+
+import "sync"
+
+type Locker interface {
+	// Lock blocks until the lock of key is acquired, and returns the function to release it.
+	Lock(key string) (unlock func())
+}
+
+// keyedLocker is a set of mutexes keyed by string.
+// The mutex of a key is removed when nobody holds or waits for it.
+type keyedLocker struct {
+	mu    sync.Mutex
+	locks map[string]*keyedMutex
+}
+
+type keyedMutex struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func NewLocker() Locker {
+	return &keyedLocker{
+		locks: make(map[string]*keyedMutex),
+	}
+}
+
+func (l *keyedLocker) Lock(key string) func() {
+	l.mu.Lock()
+	m, ok := l.locks[key]
+	if !ok {
+		m = &keyedMutex{}
+		l.locks[key] = m
+	}
+	m.refs++
+	l.mu.Unlock()
+
+	m.mu.Lock()
+	return func() {
+		m.mu.Unlock()
+		l.mu.Lock()
+		m.refs--
+		if m.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// scopedLocker prefixes the keys so that the projects of different repositories do not share a lock.
+type scopedLocker struct {
+	locker Locker
+	scope  string
+}
+
+func (s *scopedLocker) Lock(key string) func() {
+	return s.locker.Lock(s.scope + "/" + key)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocker(t *testing.T) {
+	t.Parallel()
+	locker := NewLocker().(*keyedLocker)
+
+	var (
+		wg      sync.WaitGroup
+		running atomic.Int32
+		maxRun  atomic.Int32
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := locker.Lock("project")
+			defer unlock()
+			n := running.Add(1)
+			if n > maxRun.Load() {
+				maxRun.Store(n)
+			}
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxRun.Load())
+	assert.Empty(t, locker.locks)
+
+	// Different keys do not block each other.
+	unlockA := locker.Lock("a")
+	unlockB := locker.Lock("b")
+	unlockB()
+	unlockA()
+	assert.Empty(t, locker.locks)
+}
+
+func TestScopedLocker(t *testing.T) {
+	t.Parallel()
+	locker := NewLocker().(*keyedLocker)
+	scoped := &scopedLocker{locker: locker, scope: "project/octocat/infra"}
+	unlock := scoped.Lock("aws")
+	assert.Contains(t, locker.locks, "project/octocat/infra/aws")
+	unlock()
+	assert.Empty(t, locker.locks)
+}
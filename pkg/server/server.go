@@ -0,0 +1,284 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// maxPayloadSize is the maximum size of the webhook payload.
+// See: https://docs.github.com/en/webhooks/webhook-events-and-payloads#payload-cap
+const maxPayloadSize = 25 << 20
+
+// Request is an event to be handled in the checked out working tree of the pull request.
+type Request struct {
+	Owner  string
+	Repo   string
+	Number int
+	// Dir is the directory where the pull request is checked out.
+	Dir string
+	// ConfigDir is the directory where the default branch is checked out, which the config is read from,
+	// since the config of the pull request is not reviewed yet.
+	ConfigDir string
+	Event     github.Event
+	// Locker serializes the operations on the same project of the repository.
+	Locker Locker
+}
+
+// Dispatcher handles the request, e.g. by running App.ExecuteEvent.
+type Dispatcher func(ctx context.Context, req *Request) error
+
+// NewVCS returns the client of the repository, which checks the events before the pull request is checked out.
+type NewVCS func(ctx context.Context, owner, repo string) (vcs.VCS, error)
+
+type Params struct {
+	// Secret is the secret of the webhook to verify the signature of the payloads.
+	Secret     string
+	Workspace  Workspace
+	Dispatcher Dispatcher
+	NewVCS     NewVCS
+	// AllowForks allows the pull requests from the forks, which run their code with the credentials of the server.
+	AllowForks bool
+	Logger     log.Logger
+}
+
+// Server receives the webhook events of GitHub, and dispatches them in the background.
+// The events of the same pull request are handled one by one, since they share the working tree.
+type Server struct {
+	secret     []byte
+	workspace  Workspace
+	dispatcher Dispatcher
+	newVCS     NewVCS
+	allowForks bool
+	logger     log.Logger
+	locker     Locker
+	wg         sync.WaitGroup
+}
+
+func New(params *Params) *Server {
+	return &Server{
+		secret:     []byte(params.Secret),
+		workspace:  params.Workspace,
+		dispatcher: params.Dispatcher,
+		newVCS:     params.NewVCS,
+		allowForks: params.AllowForks,
+		logger:     params.Logger,
+		locker:     NewLocker(),
+	}
+}
+
+// target is the pull request which the event is for.
+type target struct {
+	owner  string
+	repo   string
+	number int
+	ref    string
+	// defaultBranch is the branch which the config is read from.
+	defaultBranch string
+	// commenter is the user who commented the command, who must have the write permission.
+	commenter string
+	// closed removes the working trees of the pull request after the event is handled.
+	closed bool
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := verifySignature(s.secret, payload, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	eventName := r.Header.Get("X-GitHub-Event")
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	if eventName == "ping" {
+		_, _ = io.WriteString(w, "pong")
+		return
+	}
+	event, err := github.DecodeEvent(eventName, bytes.NewReader(payload))
+	if err != nil {
+		if errors.Is(err, github.ErrUnsupportedEventType) {
+			s.ignore(w, delivery, fmt.Sprintf("unsupported event: %s", eventName))
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t, reason := s.target(event)
+	if t == nil {
+		s.ignore(w, delivery, reason)
+		return
+	}
+
+	s.wg.Add(1)
+	go s.dispatch(context.WithoutCancel(r.Context()), delivery, t, event)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Wait blocks until all the dispatched events are handled.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) ignore(w http.ResponseWriter, delivery, reason string) {
+	s.logger.Info("ignored the event", log.String("delivery", delivery), log.String("reason", reason))
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = io.WriteString(w, reason)
+}
+
+func (s *Server) target(event github.Event) (*target, string) {
+	switch e := event.(type) {
+	case *github.PullRequestEvent:
+		switch e.GetAction() {
+		case github.Opened, github.Synchronize, github.Reopened:
+			return &target{
+				owner:         e.GetRepo().GetOwner().GetLogin(),
+				repo:          e.GetRepo().GetName(),
+				number:        e.Number(),
+				ref:           fmt.Sprintf("refs/pull/%d/merge", e.Number()),
+				defaultBranch: e.GetRepo().GetDefaultBranch(),
+			}, ""
+		case github.Closed:
+			// The merge ref does not exist after the pull request is closed.
+			return &target{
+				owner:         e.GetRepo().GetOwner().GetLogin(),
+				repo:          e.GetRepo().GetName(),
+				number:        e.Number(),
+				ref:           fmt.Sprintf("refs/pull/%d/head", e.Number()),
+				defaultBranch: e.GetRepo().GetDefaultBranch(),
+				closed:        true,
+			}, ""
+		default:
+			return nil, fmt.Sprintf("unsupported action: %s", e.GetAction())
+		}
+	case *github.IssueCommentEvent:
+		if e.GetAction() != github.Created {
+			return nil, fmt.Sprintf("unsupported action: %s", e.GetAction())
+		}
+		if !e.GetIssue().IsPullRequest() {
+			return nil, "not a pull request"
+		}
+		if !strings.HasPrefix(strings.TrimSpace(e.GetComment().GetBody()), "mu ") {
+			return nil, "not a mu command"
+		}
+		return &target{
+			owner:         e.GetRepo().GetOwner().GetLogin(),
+			repo:          e.GetRepo().GetName(),
+			number:        e.Number(),
+			ref:           fmt.Sprintf("refs/pull/%d/merge", e.Number()),
+			defaultBranch: e.GetRepo().GetDefaultBranch(),
+			commenter:     e.GetComment().GetUser().GetLogin(),
+		}, ""
+	default:
+		return nil, "not a pull request event"
+	}
+}
+
+func (s *Server) dispatch(ctx context.Context, delivery string, t *target, event github.Event) {
+	defer s.wg.Done()
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.logger.Error("panic occurred", log.String("delivery", delivery), log.String("panic", fmt.Sprint(rec)))
+		}
+	}()
+	repository := t.owner + "/" + t.repo
+	logOpts := []log.Option{
+		log.String("delivery", delivery),
+		log.String("repository", repository),
+		log.Int("number", t.number),
+	}
+
+	reason, err := s.check(ctx, t)
+	if err != nil {
+		s.logger.Error("failed to check the event", append(logOpts, log.Error(err))...)
+		return
+	}
+	if reason != "" {
+		s.logger.Info("ignored the event", append(logOpts, log.String("reason", reason))...)
+		return
+	}
+
+	unlock := s.locker.Lock(fmt.Sprintf("workspace/%s/%d", repository, t.number))
+	defer unlock()
+	if t.closed {
+		// The working trees are not used after the pull request is closed, and .terraform of them takes up the disk.
+		defer func() {
+			if err := s.workspace.Remove(ctx, t.owner, t.repo, t.number); err != nil {
+				s.logger.Error("failed to remove the workspace", append(logOpts, log.Error(err))...)
+			}
+		}()
+	}
+
+	dir, err := s.workspace.Checkout(ctx, t.owner, t.repo, t.number, t.ref)
+	if err != nil {
+		s.logger.Error("failed to checkout", append(logOpts, log.Error(err))...)
+		return
+	}
+	configDir, err := s.workspace.CheckoutDefaultBranch(ctx, t.owner, t.repo, t.number, t.defaultBranch)
+	if err != nil {
+		s.logger.Error("failed to checkout the default branch", append(logOpts, log.Error(err))...)
+		return
+	}
+	err = s.dispatcher(ctx, &Request{
+		Owner:     t.owner,
+		Repo:      t.repo,
+		Number:    t.number,
+		Dir:       dir,
+		ConfigDir: configDir,
+		Event:     event,
+		Locker:    &scopedLocker{locker: s.locker, scope: "project/" + repository},
+	})
+	if err != nil {
+		s.logger.Error("failed to handle the event", append(logOpts, log.Error(err))...)
+		return
+	}
+	s.logger.Info("handled the event", logOpts...)
+}
+
+// check returns the reason to drop the event before the pull request is checked out: the commands from the users
+// without the write permission, and the pull requests from the forks unless they are allowed.
+func (s *Server) check(ctx context.Context, t *target) (string, error) {
+	if t.defaultBranch == "" {
+		return "no default branch", nil
+	}
+	client, err := s.newVCS(ctx, t.owner, t.repo)
+	if err != nil {
+		return "", err
+	}
+	if t.commenter != "" {
+		level, err := client.GetPermissionLevel(ctx, t.commenter)
+		if err != nil {
+			return "", err
+		}
+		if !vcs.HasPermission(level, vcs.PermissionWrite) {
+			return fmt.Sprintf("%s has no write permission", t.commenter), nil
+		}
+	}
+	if s.allowForks {
+		return "", nil
+	}
+	pr, err := client.GetPullRequest(ctx, t.number)
+	if err != nil {
+		return "", err
+	}
+	if pr.Fork {
+		return "pull request from a fork", nil
+	}
+	return "", nil
+}
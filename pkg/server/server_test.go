@@ -0,0 +1,353 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/vcs"
+	"github.com/yu-icchi/mu/pkg/vcs/mock"
+)
+
+const testSecret = "test-secret"
+
+func sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type checkout struct {
+	owner string
+	repo  string
+	prNum int
+	ref   string
+}
+
+type fakeWorkspace struct {
+	mu        sync.Mutex
+	checkouts []*checkout
+	branches  []string
+	removed   []int
+	err       error
+}
+
+func (f *fakeWorkspace) Checkout(_ context.Context, owner, repo string, prNum int, ref string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checkouts = append(f.checkouts, &checkout{owner: owner, repo: repo, prNum: prNum, ref: ref})
+	if f.err != nil {
+		return "", f.err
+	}
+	return "/workspace/" + owner + "/" + repo, nil
+}
+
+func (f *fakeWorkspace) CheckoutDefaultBranch(_ context.Context, owner, repo string, _ int, branch string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.branches = append(f.branches, branch)
+	return "/workspace/" + owner + "/" + repo + "/default", nil
+}
+
+func (f *fakeWorkspace) Remove(_ context.Context, _, _ string, prNum int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, prNum)
+	return nil
+}
+
+func newTestVCS(client vcs.VCS) NewVCS {
+	return func(context.Context, string, string) (vcs.VCS, error) {
+		return client, nil
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		method         string
+		event          string
+		payload        string
+		signature      string
+		allowForks     bool
+		prepare        func(m *mock.MockVCS)
+		expectStatus   int
+		expectCheckout *checkout
+		expectEvent    any
+		expectRemoved  []int
+	}{
+		{
+			name:    "pull_request opened",
+			event:   "pull_request",
+			payload: "testdata/pull_request_opened.json",
+			prepare: func(m *mock.MockVCS) {
+				m.EXPECT().GetPullRequest(gomock.Any(), 12).Return(&vcs.PullRequest{Number: 12}, nil)
+			},
+			expectStatus: http.StatusAccepted,
+			expectCheckout: &checkout{
+				owner: "octocat",
+				repo:  "infra",
+				prNum: 12,
+				ref:   "refs/pull/12/merge",
+			},
+			expectEvent: &github.PullRequestEvent{},
+		},
+		{
+			name:    "pull_request closed",
+			event:   "pull_request",
+			payload: "testdata/pull_request_closed.json",
+			prepare: func(m *mock.MockVCS) {
+				m.EXPECT().GetPullRequest(gomock.Any(), 12).Return(&vcs.PullRequest{Number: 12}, nil)
+			},
+			expectStatus: http.StatusAccepted,
+			expectCheckout: &checkout{
+				owner: "octocat",
+				repo:  "infra",
+				prNum: 12,
+				ref:   "refs/pull/12/head",
+			},
+			expectEvent:   &github.PullRequestEvent{},
+			expectRemoved: []int{12},
+		},
+		{
+			name:    "issue_comment on pull request",
+			event:   "issue_comment",
+			payload: "testdata/issue_comment_created.json",
+			prepare: func(m *mock.MockVCS) {
+				m.EXPECT().GetPermissionLevel(gomock.Any(), "octocat").Return(vcs.PermissionWrite, nil)
+				m.EXPECT().GetPullRequest(gomock.Any(), 12).Return(&vcs.PullRequest{Number: 12}, nil)
+			},
+			expectStatus: http.StatusAccepted,
+			expectCheckout: &checkout{
+				owner: "octocat",
+				repo:  "infra",
+				prNum: 12,
+				ref:   "refs/pull/12/merge",
+			},
+			expectEvent: &github.IssueCommentEvent{},
+		},
+		{
+			name:    "issue_comment by user without write permission",
+			event:   "issue_comment",
+			payload: "testdata/issue_comment_created.json",
+			prepare: func(m *mock.MockVCS) {
+				m.EXPECT().GetPermissionLevel(gomock.Any(), "octocat").Return(vcs.PermissionTriage, nil)
+			},
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name:    "pull_request from fork",
+			event:   "pull_request",
+			payload: "testdata/pull_request_opened.json",
+			prepare: func(m *mock.MockVCS) {
+				m.EXPECT().GetPullRequest(gomock.Any(), 12).Return(&vcs.PullRequest{Number: 12, Fork: true}, nil)
+			},
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name:         "pull_request from allowed fork",
+			event:        "pull_request",
+			payload:      "testdata/pull_request_opened.json",
+			allowForks:   true,
+			expectStatus: http.StatusAccepted,
+			expectCheckout: &checkout{
+				owner: "octocat",
+				repo:  "infra",
+				prNum: 12,
+				ref:   "refs/pull/12/merge",
+			},
+			expectEvent: &github.PullRequestEvent{},
+		},
+		{
+			name:         "issue_comment on issue",
+			event:        "issue_comment",
+			payload:      "testdata/issue_comment_issue.json",
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name:         "ping",
+			event:        "ping",
+			payload:      "testdata/ping.json",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "unsupported event",
+			event:        "star",
+			payload:      "testdata/ping.json",
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name:         "invalid signature",
+			event:        "pull_request",
+			payload:      "testdata/pull_request_opened.json",
+			signature:    "sha256=0000",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "missing signature",
+			event:        "pull_request",
+			payload:      "testdata/pull_request_opened.json",
+			signature:    "-",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			event:        "pull_request",
+			payload:      "testdata/pull_request_opened.json",
+			expectStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			client := mock.NewMockVCS(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(client)
+			}
+			workspace := &fakeWorkspace{}
+			var (
+				mu       sync.Mutex
+				requests []*Request
+			)
+			srv := New(&Params{
+				Secret:     testSecret,
+				Workspace:  workspace,
+				NewVCS:     newTestVCS(client),
+				AllowForks: tt.allowForks,
+				Dispatcher: func(_ context.Context, req *Request) error {
+					mu.Lock()
+					defer mu.Unlock()
+					requests = append(requests, req)
+					return nil
+				},
+				Logger: log.New(io.Discard),
+			})
+
+			payload, err := os.ReadFile(tt.payload)
+			require.NoError(t, err)
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/", strings.NewReader(string(payload)))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-GitHub-Delivery", "test-delivery")
+			switch tt.signature {
+			case "":
+				req.Header.Set("X-Hub-Signature-256", sign(payload))
+			case "-":
+			default:
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			srv.Wait()
+
+			assert.Equal(t, tt.expectStatus, rec.Code)
+			if tt.expectCheckout == nil {
+				assert.Empty(t, workspace.checkouts)
+				assert.Empty(t, requests)
+				return
+			}
+			require.Len(t, workspace.checkouts, 1)
+			assert.Equal(t, tt.expectCheckout, workspace.checkouts[0])
+			require.Len(t, requests, 1)
+			assert.Equal(t, "octocat", requests[0].Owner)
+			assert.Equal(t, "infra", requests[0].Repo)
+			assert.Equal(t, 12, requests[0].Number)
+			assert.Equal(t, "/workspace/octocat/infra", requests[0].Dir)
+			assert.Equal(t, "/workspace/octocat/infra/default", requests[0].ConfigDir)
+			assert.Equal(t, []string{"main"}, workspace.branches)
+			assert.IsType(t, tt.expectEvent, requests[0].Event)
+			assert.NotNil(t, requests[0].Locker)
+			assert.Equal(t, tt.expectRemoved, workspace.removed)
+		})
+	}
+}
+
+func TestServer_ServeHTTP_CheckoutFailed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockVCS(ctrl)
+	client.EXPECT().GetPullRequest(gomock.Any(), 12).Return(&vcs.PullRequest{Number: 12}, nil)
+	workspace := &fakeWorkspace{err: assert.AnError}
+	srv := New(&Params{
+		Secret:    testSecret,
+		Workspace: workspace,
+		NewVCS:    newTestVCS(client),
+		Dispatcher: func(_ context.Context, req *Request) error {
+			t.Error("must not be dispatched")
+			return nil
+		},
+		Logger: log.New(io.Discard),
+	})
+	payload, err := os.ReadFile("testdata/pull_request_opened.json")
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(payload)))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", sign(payload))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	srv.Wait()
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, workspace.checkouts, 1)
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+	payload := []byte(`{"zen":"Keep it logically awesome."}`)
+	tests := []struct {
+		name      string
+		signature string
+		expectErr error
+	}{
+		{
+			name:      "valid",
+			signature: sign(payload),
+		},
+		{
+			name:      "wrong signature",
+			signature: "sha256=" + strings.Repeat("0", 64),
+			expectErr: errInvalidSignature,
+		},
+		{
+			name:      "sha1",
+			signature: "sha1=0000",
+			expectErr: errInvalidSignature,
+		},
+		{
+			name:      "not hex",
+			signature: "sha256=zz",
+			expectErr: errInvalidSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := verifySignature([]byte(testSecret), payload, tt.signature)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// verifySignature verifies the X-Hub-Signature-256 header of the webhook.
+// See: https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+func verifySignature(secret, payload []byte, signature string) error {
+	const prefix = "sha256="
+	hexSig, ok := strings.CutPrefix(signature, prefix)
+	if !ok {
+		return errInvalidSignature
+	}
+	sig, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return errInvalidSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return errInvalidSignature
+	}
+	return nil
+}
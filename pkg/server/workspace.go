@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var errGitFailed = errors.New("git failed")
+
+// Workspace checks out the pull requests into the local directories.
+type Workspace interface {
+	// Checkout checks out ref of the repository for the pull request, and returns the directory.
+	Checkout(ctx context.Context, owner, repo string, prNum int, ref string) (string, error)
+	// CheckoutDefaultBranch checks out the default branch for the pull request into another directory, and returns it.
+	CheckoutDefaultBranch(ctx context.Context, owner, repo string, prNum int, branch string) (string, error)
+	// Remove removes the directories checked out for the pull request.
+	Remove(ctx context.Context, owner, repo string, prNum int) error
+}
+
+type GitWorkspaceParams struct {
+	// Root is the directory where the repositories are cached.
+	Root string
+	// BaseURL is the URL of the git server (default https://github.com).
+	BaseURL string
+	Token   string
+}
+
+// gitWorkspace caches a working tree per pull request under Root/<owner>/<repo>/<number>.
+// The working tree is reused across the events of the pull request, so `.terraform` directories survive between runs.
+// The default branch is checked out per pull request under Root/<owner>/<repo>/default/<number>, so that the events of
+// the other pull requests do not update it while it is read.
+type gitWorkspace struct {
+	root    string
+	baseURL string
+	token   string
+}
+
+func NewGitWorkspace(params *GitWorkspaceParams) Workspace {
+	baseURL := params.BaseURL
+	if baseURL == "" {
+		baseURL = "https://github.com"
+	}
+	return &gitWorkspace{
+		root:    params.Root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   params.Token,
+	}
+}
+
+func (w *gitWorkspace) Checkout(ctx context.Context, owner, repo string, prNum int, ref string) (string, error) {
+	dir := filepath.Join(w.root, owner, repo, strconv.Itoa(prNum))
+	if err := w.checkout(ctx, dir, owner, repo, ref); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func (w *gitWorkspace) CheckoutDefaultBranch(ctx context.Context, owner, repo string, prNum int, branch string) (string, error) {
+	dir := filepath.Join(w.root, owner, repo, "default", strconv.Itoa(prNum))
+	if err := w.checkout(ctx, dir, owner, repo, "refs/heads/"+branch); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func (w *gitWorkspace) Remove(_ context.Context, owner, repo string, prNum int) error {
+	return errors.Join(
+		os.RemoveAll(filepath.Join(w.root, owner, repo, strconv.Itoa(prNum))),
+		os.RemoveAll(filepath.Join(w.root, owner, repo, "default", strconv.Itoa(prNum))),
+	)
+}
+
+func (w *gitWorkspace) checkout(ctx context.Context, dir, owner, repo, ref string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := w.git(ctx, dir, "init", "--quiet"); err != nil {
+			return err
+		}
+		remote := fmt.Sprintf("%s/%s/%s.git", w.baseURL, owner, repo)
+		if err := w.git(ctx, dir, "remote", "add", "origin", remote); err != nil {
+			return err
+		}
+	}
+	if err := w.git(ctx, dir, "fetch", "--quiet", "--depth=1", "--force", "origin", ref); err != nil {
+		return err
+	}
+	if err := w.git(ctx, dir, "checkout", "--quiet", "--force", "--detach", "FETCH_HEAD"); err != nil {
+		return err
+	}
+	// The ignored files such as .terraform are kept to skip downloading the providers again.
+	if err := w.git(ctx, dir, "clean", "--quiet", "-ffd"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *gitWorkspace) git(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if w.token != "" {
+		// The token is passed in the environment, since the command line is visible to the other users in the process list.
+		auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + w.token))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		)
+	}
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: git %s: %w: %s", errGitFailed, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
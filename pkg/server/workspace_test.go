@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=mu", "-c", "user.email=mu@example.com", "-c", "init.defaultBranch=main"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// newRemote creates the repository served at <root>/octocat/infra.git with refs/pull/1/head.
+func newRemote(t *testing.T, root string) string {
+	t.Helper()
+	remote := filepath.Join(root, "octocat", "infra.git")
+	require.NoError(t, os.MkdirAll(remote, 0755))
+	runGit(t, remote, "init", "--quiet")
+	return remote
+}
+
+func commitPull(t *testing.T, remote, file, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(remote, file), []byte(content), 0600))
+	runGit(t, remote, "add", file)
+	runGit(t, remote, "commit", "--quiet", "-m", file)
+	runGit(t, remote, "update-ref", "refs/pull/1/head", "HEAD")
+}
+
+func TestGitWorkspace_Checkout(t *testing.T) {
+	t.Parallel()
+	remoteRoot := t.TempDir()
+	remote := newRemote(t, remoteRoot)
+	commitPull(t, remote, "main.tf", "# v1")
+
+	root := t.TempDir()
+	ws := NewGitWorkspace(&GitWorkspaceParams{
+		Root:    root,
+		BaseURL: "file://" + remoteRoot,
+	})
+	ctx := context.Background()
+	dir, err := ws.Checkout(ctx, "octocat", "infra", 1, "refs/pull/1/head")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "octocat", "infra", "1"), dir)
+	content, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "# v1", string(content))
+
+	// The cached working tree is updated, and the ignored files are kept.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(".terraform\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".terraform"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked.tf"), []byte(""), 0600))
+	commitPull(t, remote, ".gitignore", ".terraform\n")
+	commitPull(t, remote, "main.tf", "# v2")
+
+	dir, err = ws.Checkout(ctx, "octocat", "infra", 1, "refs/pull/1/head")
+	require.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "# v2", string(content))
+	assert.DirExists(t, filepath.Join(dir, ".terraform"))
+	assert.NoFileExists(t, filepath.Join(dir, "untracked.tf"))
+}
+
+func TestGitWorkspace_Checkout_NotFound(t *testing.T) {
+	t.Parallel()
+	remoteRoot := t.TempDir()
+	remote := newRemote(t, remoteRoot)
+	commitPull(t, remote, "main.tf", "# v1")
+
+	ws := NewGitWorkspace(&GitWorkspaceParams{
+		Root:    t.TempDir(),
+		BaseURL: "file://" + remoteRoot,
+		Token:   "test-token",
+	})
+	_, err := ws.Checkout(context.Background(), "octocat", "infra", 2, "refs/pull/2/head")
+	require.ErrorIs(t, err, errGitFailed)
+	assert.NotContains(t, err.Error(), "test-token")
+}
+
+func TestGitWorkspace_Remove(t *testing.T) {
+	t.Parallel()
+	remoteRoot := t.TempDir()
+	remote := newRemote(t, remoteRoot)
+	commitPull(t, remote, "main.tf", "# v1")
+
+	ws := NewGitWorkspace(&GitWorkspaceParams{
+		Root:    t.TempDir(),
+		BaseURL: "file://" + remoteRoot,
+	})
+	ctx := context.Background()
+	dir, err := ws.Checkout(ctx, "octocat", "infra", 1, "refs/pull/1/head")
+	require.NoError(t, err)
+	defaultDir, err := ws.CheckoutDefaultBranch(ctx, "octocat", "infra", 1, "main")
+	require.NoError(t, err)
+	otherDir, err := ws.Checkout(ctx, "octocat", "infra", 2, "refs/pull/1/head")
+	require.NoError(t, err)
+
+	require.NoError(t, ws.Remove(ctx, "octocat", "infra", 1))
+	assert.NoDirExists(t, dir)
+	assert.NoDirExists(t, defaultDir)
+	assert.DirExists(t, otherDir)
+	// It is not an error to remove the removed workspace.
+	require.NoError(t, ws.Remove(ctx, "octocat", "infra", 1))
+}
+
+func TestGitWorkspace_Checkout_Token(t *testing.T) {
+	t.Parallel()
+	auth := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case auth <- r.Header.Get("Authorization"):
+		default:
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ws := NewGitWorkspace(&GitWorkspaceParams{
+		Root:    t.TempDir(),
+		BaseURL: srv.URL,
+		Token:   "test-token",
+	})
+	_, err := ws.Checkout(context.Background(), "octocat", "infra", 1, "refs/pull/1/head")
+	require.ErrorIs(t, err, errGitFailed)
+	// x-access-token:test-token
+	assert.Equal(t, "Basic eC1hY2Nlc3MtdG9rZW46dGVzdC10b2tlbg==", <-auth)
+}
+
+func TestGitWorkspace_CheckoutDefaultBranch(t *testing.T) {
+	t.Parallel()
+	remoteRoot := t.TempDir()
+	remote := newRemote(t, remoteRoot)
+	commitPull(t, remote, "mu.yaml", "# main")
+
+	root := t.TempDir()
+	ws := NewGitWorkspace(&GitWorkspaceParams{
+		Root:    root,
+		BaseURL: "file://" + remoteRoot,
+	})
+	ctx := context.Background()
+	dir, err := ws.CheckoutDefaultBranch(ctx, "octocat", "infra", 1, "main")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "octocat", "infra", "default", "1"), dir)
+	content, err := os.ReadFile(filepath.Join(dir, "mu.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "# main", string(content))
+
+	// The pull request is checked out into another directory.
+	prDir, err := ws.Checkout(ctx, "octocat", "infra", 1, "refs/pull/1/head")
+	require.NoError(t, err)
+	assert.NotEqual(t, dir, prDir)
+}
@@ -2,4 +2,8 @@ package terraform
 
 import "errors"
 
-var errTerraformMissmatchVersion = errors.New("terraform version mismatch")
+var (
+	errTerraformMissmatchVersion = errors.New("terraform version mismatch")
+	errInvalidConfiguration      = errors.New("invalid configuration")
+	errUnformatted               = errors.New("unformatted configuration")
+)
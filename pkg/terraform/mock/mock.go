@@ -87,6 +87,26 @@ func (mr *MockTerraformMockRecorder) CompareVersion(ctx, version any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareVersion", reflect.TypeOf((*MockTerraform)(nil).CompareVersion), ctx, version)
 }
 
+// Fmt mocks base method.
+func (m *MockTerraform) Fmt(ctx context.Context, params *terraform.FmtParams, opts ...terraform.Option) (*terraform.FmtOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Fmt", varargs...)
+	ret0, _ := ret[0].(*terraform.FmtOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fmt indicates an expected call of Fmt.
+func (mr *MockTerraformMockRecorder) Fmt(ctx, params any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fmt", reflect.TypeOf((*MockTerraform)(nil).Fmt), varargs...)
+}
+
 // ForceUnlock mocks base method.
 func (m *MockTerraform) ForceUnlock(ctx context.Context, lockID string, opts ...terraform.Option) (*terraform.ForceUnlockOutput, error) {
 	m.ctrl.T.Helper()
@@ -147,6 +167,26 @@ func (mr *MockTerraformMockRecorder) Init(ctx, params any, opts ...any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockTerraform)(nil).Init), varargs...)
 }
 
+// Output mocks base method.
+func (m *MockTerraform) Output(ctx context.Context, opts ...terraform.Option) (*terraform.OutputsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Output", varargs...)
+	ret0, _ := ret[0].(*terraform.OutputsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Output indicates an expected call of Output.
+func (mr *MockTerraformMockRecorder) Output(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Output", reflect.TypeOf((*MockTerraform)(nil).Output), varargs...)
+}
+
 // Plan mocks base method.
 func (m *MockTerraform) Plan(ctx context.Context, params *terraform.PlanParams, opts ...terraform.Option) (*terraform.Output, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +255,26 @@ func (mr *MockTerraformMockRecorder) SwitchWorkspace(ctx, workspace any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchWorkspace", reflect.TypeOf((*MockTerraform)(nil).SwitchWorkspace), ctx, workspace)
 }
 
+// Validate mocks base method.
+func (m *MockTerraform) Validate(ctx context.Context, opts ...terraform.Option) (*terraform.ValidateOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Validate", varargs...)
+	ret0, _ := ret[0].(*terraform.ValidateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockTerraformMockRecorder) Validate(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTerraform)(nil).Validate), varargs...)
+}
+
 // Version mocks base method.
 func (m *MockTerraform) Version(ctx context.Context) (string, map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -3,8 +3,14 @@ package terraform
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-version"
@@ -28,6 +34,9 @@ type Terraform interface {
 	ForceUnlock(ctx context.Context, lockID string, opts ...Option) (*ForceUnlockOutput, error)
 	Import(ctx context.Context, params *ImportParams, opts ...Option) (*ImportOutput, error)
 	StateRm(ctx context.Context, params *StateRmParams, opts ...Option) (*StateRmOutput, error)
+	Output(ctx context.Context, opts ...Option) (*OutputsOutput, error)
+	Validate(ctx context.Context, opts ...Option) (*ValidateOutput, error)
+	Fmt(ctx context.Context, params *FmtParams, opts ...Option) (*FmtOutput, error)
 	Cleanup(ctx context.Context)
 }
 
@@ -43,6 +52,8 @@ type Output struct {
 	HasParseError      bool
 	Error              error
 	RawLog             string
+	// HasChanges is the result of `terraform plan -detailed-exitcode`.
+	HasChanges bool
 }
 
 type ForceUnlockOutput struct {
@@ -63,22 +74,57 @@ type StateRmOutput struct {
 	Error    error
 }
 
+type OutputsOutput struct {
+	Values   []*OutputValue
+	Result   string
+	HasError bool
+	Error    error
+}
+
+type OutputValue struct {
+	Name      string
+	Type      string
+	Value     string
+	Sensitive bool
+}
+
+type ValidateOutput struct {
+	Result   string
+	HasError bool
+	Error    error
+}
+
+type FmtOutput struct {
+	Result   string
+	Files    []string
+	HasDiff  bool
+	HasError bool
+	Error    error
+}
+
 const LatestVersion = "latest"
 
 type InitParams struct {
 	BackendConfig     map[string]string
 	BackendConfigPath string
+	// ExtraArgs are appended to the arguments of terraform init.
+	ExtraArgs []string
 }
 
 type PlanParams struct {
-	Vars     []string
-	VarFiles []string
-	Destroy  bool
-	Out      string
+	Vars        []string
+	VarFiles    []string
+	Destroy     bool
+	RefreshOnly bool
+	Out         string
+	// ExtraArgs are appended to the arguments of terraform plan.
+	ExtraArgs []string
 }
 
 type ApplyParams struct {
 	PlanFilePath string
+	// ExtraArgs are appended to the arguments of terraform apply, before the plan file.
+	ExtraArgs []string
 }
 
 type ImportParams struct {
@@ -93,6 +139,10 @@ type StateRmParams struct {
 	DryRun  bool
 }
 
+type FmtParams struct {
+	Write bool
+}
+
 type options struct {
 	stream io.Writer
 }
@@ -111,24 +161,36 @@ type installer interface {
 }
 
 type terraform struct {
-	version   string
-	workDir   string
-	execPath  string
-	installer installer
-	tf        *tfexec.Terraform
+	version         string
+	workDir         string
+	execPath        string
+	wrapper         string
+	wrapperExecPath string
+	launcherDir     string
+	installer       installer
+	tf              *tfexec.Terraform
 }
 
+// WrapperTerragrunt runs the commands through Terragrunt, which runs the installed Terraform.
+const WrapperTerragrunt = "terragrunt"
+
 type Params struct {
 	Version  string
 	WorkDir  string
 	ExecPath string
+	// Wrapper is the command which the Terraform commands are run through, e.g. WrapperTerragrunt.
+	Wrapper string
+	// WrapperExecPath is the path of the wrapper, which is looked up in PATH if it is empty.
+	WrapperExecPath string
 }
 
 func New(params *Params) Terraform {
 	return &terraform{
-		version:  strings.ToLower(params.Version),
-		workDir:  params.WorkDir,
-		execPath: params.ExecPath,
+		version:         strings.ToLower(params.Version),
+		workDir:         params.WorkDir,
+		execPath:        params.ExecPath,
+		wrapper:         params.Wrapper,
+		wrapperExecPath: params.WrapperExecPath,
 	}
 }
 
@@ -154,11 +216,58 @@ func (t *terraform) Setup(ctx context.Context) error {
 			return err
 		}
 	}
-	t.tf, err = tfexec.NewTerraform(t.workDir, t.execPath)
+	if t.wrapper == "" {
+		t.tf, err = tfexec.NewTerraform(t.workDir, t.execPath)
+		return err
+	}
+
+	if t.wrapperExecPath == "" {
+		t.wrapperExecPath, err = exec.LookPath(t.wrapper)
+		if err != nil {
+			return err
+		}
+	}
+	launcher, err := t.writeLauncher()
 	if err != nil {
 		return err
 	}
-	return nil
+	t.tf, err = tfexec.NewTerraform(t.workDir, launcher)
+	return err
+}
+
+// writeLauncher writes a script which runs the wrapper with the installed Terraform.
+// The variables are set in the script instead of tfexec.SetEnv, since tfexec drops TF_VAR_ variables from the environment it is given.
+// Terragrunt reads the variables of both the TG_ prefix and the older TERRAGRUNT_ prefix.
+// The output of Terraform is forwarded as it is, so that it is parsed in the same way as Terraform.
+func (t *terraform) writeLauncher() (string, error) {
+	dir, err := os.MkdirTemp("", "mu-"+t.wrapper)
+	if err != nil {
+		return "", err
+	}
+	t.launcherDir = dir
+	env := [][2]string{
+		{"TG_TF_PATH", t.execPath},
+		{"TERRAGRUNT_TFPATH", t.execPath},
+		{"TG_NON_INTERACTIVE", "true"},
+		{"TERRAGRUNT_NON_INTERACTIVE", "true"},
+		{"TG_TF_FORWARD_STDOUT", "true"},
+		{"TERRAGRUNT_FORWARD_TF_STDOUT", "true"},
+	}
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	for _, kv := range env {
+		fmt.Fprintf(&script, "export %s=%s\n", kv[0], shellQuote(kv[1]))
+	}
+	fmt.Fprintf(&script, "exec %s \"$@\"\n", shellQuote(t.wrapperExecPath))
+	path := filepath.Join(dir, t.wrapper)
+	if err := os.WriteFile(path, []byte(script.String()), 0o700); err != nil { // #nosec G306
+		return "", err
+	}
+	return path, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func (t *terraform) Version(ctx context.Context) (string, map[string]string, error) {
@@ -213,7 +322,13 @@ func (t *terraform) Init(ctx context.Context, params *InitParams, opts ...Option
 		initOpts = append(initOpts, tfexec.Reconfigure(true))
 	}
 	parser := tfcmt.NewPlanParser()
-	if err := t.tf.Init(ctx, initOpts...); err != nil {
+	var err error
+	if len(params.ExtraArgs) > 0 {
+		err = t.exec(ctx, initArgs(params), opt, outBuf, errBuf)
+	} else {
+		err = t.tf.Init(ctx, initOpts...)
+	}
+	if err != nil {
 		if errBuf.Len() == 0 {
 			return nil, err
 		}
@@ -264,9 +379,20 @@ func (t *terraform) Plan(ctx context.Context, params *PlanParams, opts ...Option
 		planOpts = append(planOpts, tfexec.Out(params.Out))
 	}
 	planOpts = append(planOpts, tfexec.Destroy(params.Destroy))
+	if params.RefreshOnly {
+		planOpts = append(planOpts, tfexec.RefreshOnly(true))
+	}
 
 	parser := tfcmt.NewPlanParser()
-	_, err := t.tf.Plan(ctx, planOpts...)
+	var (
+		hasChanges bool
+		err        error
+	)
+	if len(params.ExtraArgs) > 0 {
+		hasChanges, err = t.execPlan(ctx, params, opt, outBuf, errBuf)
+	} else {
+		hasChanges, err = t.tf.Plan(ctx, planOpts...)
+	}
 	if err != nil {
 		if errBuf.Len() == 0 {
 			return nil, err
@@ -278,7 +404,9 @@ func (t *terraform) Plan(ctx context.Context, params *PlanParams, opts ...Option
 		return t.toOutput(ret, errBuf.String()), nil
 	}
 	ret := parser.Parse(outBuf.String())
-	return t.toOutput(ret, outBuf.String()), nil
+	out := t.toOutput(ret, outBuf.String())
+	out.HasChanges = hasChanges
+	return out, nil
 }
 
 func (t *terraform) Apply(ctx context.Context, params *ApplyParams, opts ...Option) (*Output, error) {
@@ -302,7 +430,12 @@ func (t *terraform) Apply(ctx context.Context, params *ApplyParams, opts ...Opti
 	}
 
 	parser := tfcmt.NewApplyParser()
-	err := t.tf.Apply(ctx, applyOpts...)
+	var err error
+	if len(params.ExtraArgs) > 0 {
+		err = t.exec(ctx, applyArgs(params), opt, outBuf, errBuf)
+	} else {
+		err = t.tf.Apply(ctx, applyOpts...)
+	}
 	if err != nil {
 		if errBuf.Len() == 0 {
 			return nil, err
@@ -426,10 +559,258 @@ func (t *terraform) StateRm(ctx context.Context, params *StateRmParams, opts ...
 	return out, nil
 }
 
+func (t *terraform) Output(ctx context.Context, opts ...Option) (*OutputsOutput, error) {
+	opt := &options{}
+	for i := range opts {
+		opts[i](opt)
+	}
+
+	// Sensitive values are included in stdout of `terraform output -json`, so only stderr is streamed.
+	errBuf := new(strings.Builder)
+	t.tf.SetStdout(io.Discard)
+	if opt.stream != nil {
+		t.tf.SetStderr(io.MultiWriter(errBuf, opt.stream))
+	} else {
+		t.tf.SetStderr(errBuf)
+	}
+
+	outputs, err := t.tf.Output(ctx)
+	if err != nil {
+		if errBuf.Len() == 0 {
+			return nil, err
+		}
+		out := &OutputsOutput{
+			Result:   errBuf.String(),
+			HasError: true,
+			Error:    err,
+		}
+		return out, nil
+	}
+	values := make([]*OutputValue, 0, len(outputs))
+	for name, meta := range outputs {
+		values = append(values, &OutputValue{
+			Name:      name,
+			Type:      formatOutputJSON(meta.Type),
+			Value:     formatOutputJSON(meta.Value),
+			Sensitive: meta.Sensitive,
+		})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+	out := &OutputsOutput{
+		Values: values,
+	}
+	return out, nil
+}
+
+func (t *terraform) Validate(ctx context.Context, opts ...Option) (*ValidateOutput, error) {
+	opt := &options{}
+	for i := range opts {
+		opts[i](opt)
+	}
+
+	ret, err := t.tf.Validate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := new(strings.Builder)
+	for _, diag := range ret.Diagnostics {
+		result.WriteString(fmt.Sprintf("%s: %s\n", capitalize(string(diag.Severity)), diag.Summary))
+		if diag.Range != nil {
+			result.WriteString(fmt.Sprintf("\n  on %s line %d:\n", diag.Range.Filename, diag.Range.Start.Line))
+		}
+		if diag.Detail != "" {
+			result.WriteString("\n")
+			result.WriteString(diag.Detail)
+			result.WriteString("\n")
+		}
+		result.WriteString("\n")
+	}
+	if ret.Valid && result.Len() == 0 {
+		result.WriteString("Success! The configuration is valid.\n")
+	}
+	if opt.stream != nil {
+		_, _ = io.WriteString(opt.stream, result.String())
+	}
+	out := &ValidateOutput{
+		Result:   strings.TrimSpace(result.String()),
+		HasError: !ret.Valid,
+	}
+	if out.HasError {
+		out.Error = errInvalidConfiguration
+	}
+	return out, nil
+}
+
+func capitalize(severity string) string {
+	if severity == "" {
+		return severity
+	}
+	return strings.ToUpper(severity[:1]) + severity[1:]
+}
+
+// Fmt runs `terraform fmt` recursively in the working directory.
+// tfexec does not support the -diff option, so the command is executed directly.
+func (t *terraform) Fmt(ctx context.Context, params *FmtParams, opts ...Option) (*FmtOutput, error) {
+	opt := &options{}
+	for i := range opts {
+		opts[i](opt)
+	}
+
+	args := []string{"fmt", "-no-color", "-recursive"}
+	if params.Write {
+		args = append(args, "-list=true", "-write=true", "-diff=false")
+	} else {
+		args = append(args, "-list=false", "-write=false", "-diff=true", "-check=true")
+	}
+	outBuf := new(strings.Builder)
+	errBuf := new(strings.Builder)
+	cmd := exec.CommandContext(ctx, t.execPath, args...) // #nosec G204
+	cmd.Dir = t.workDir
+	if opt.stream != nil {
+		cmd.Stdout = io.MultiWriter(outBuf, opt.stream)
+		cmd.Stderr = io.MultiWriter(errBuf, opt.stream)
+	} else {
+		cmd.Stdout = outBuf
+		cmd.Stderr = errBuf
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+		// exit code 3 means that some files are not formatted.
+		const unformatted = 3
+		if exitErr.ExitCode() == unformatted && !params.Write {
+			out := &FmtOutput{
+				Result:   outBuf.String(),
+				HasDiff:  true,
+				HasError: true,
+				Error:    errUnformatted,
+			}
+			return out, nil
+		}
+		if errBuf.Len() == 0 {
+			return nil, err
+		}
+		out := &FmtOutput{
+			Result:   errBuf.String(),
+			HasError: true,
+			Error:    err,
+		}
+		return out, nil
+	}
+	out := &FmtOutput{
+		Result: outBuf.String(),
+	}
+	if params.Write {
+		for _, line := range strings.Split(outBuf.String(), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			out.Files = append(out.Files, filepath.ToSlash(line))
+		}
+	}
+	return out, nil
+}
+
+func formatOutputJSON(raw json.RawMessage) string {
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
 func (t *terraform) Cleanup(ctx context.Context) {
 	if t.installer != nil {
 		_ = t.installer.Remove(ctx)
 	}
+	if t.launcherDir != "" {
+		_ = os.RemoveAll(t.launcherDir)
+	}
+}
+
+// exec runs terraform, or the wrapper, with the args directly, since tfexec does not accept arbitrary arguments.
+func (t *terraform) exec(ctx context.Context, args []string, opt *options, outBuf, errBuf io.Writer) error {
+	cmd := exec.CommandContext(ctx, t.tf.ExecPath(), args...) // #nosec G204
+	cmd.Dir = t.workDir
+	cmd.Env = append(os.Environ(), "TF_IN_AUTOMATION=1")
+	if opt.stream != nil {
+		cmd.Stdout = io.MultiWriter(outBuf, opt.stream)
+		cmd.Stderr = io.MultiWriter(errBuf, opt.stream)
+	} else {
+		cmd.Stdout = outBuf
+		cmd.Stderr = errBuf
+	}
+	return cmd.Run()
+}
+
+// execPlan runs terraform plan with -detailed-exitcode, and reports the exit code 2 as the changes.
+func (t *terraform) execPlan(
+	ctx context.Context, params *PlanParams, opt *options, outBuf, errBuf io.Writer,
+) (bool, error) {
+	err := t.exec(ctx, planArgs(params), opt, outBuf, errBuf)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return true, nil
+	}
+	return false, err
+}
+
+func initArgs(params *InitParams) []string {
+	args := []string{"init", "-no-color", "-input=false"}
+	if params.BackendConfigPath != "" {
+		args = append(args, "-backend-config="+params.BackendConfigPath)
+	}
+	keys := make([]string, 0, len(params.BackendConfig))
+	for key := range params.BackendConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("-backend-config=%s=%s", key, params.BackendConfig[key]))
+	}
+	if params.BackendConfigPath != "" || len(params.BackendConfig) > 0 {
+		args = append(args, "-reconfigure")
+	}
+	return append(args, params.ExtraArgs...)
+}
+
+func planArgs(params *PlanParams) []string {
+	args := []string{"plan", "-no-color", "-input=false", "-detailed-exitcode"}
+	for _, v := range params.Vars {
+		args = append(args, "-var="+v)
+	}
+	for _, v := range params.VarFiles {
+		args = append(args, "-var-file="+v)
+	}
+	if params.Out != "" {
+		args = append(args, "-out="+params.Out)
+	}
+	if params.Destroy {
+		args = append(args, "-destroy")
+	}
+	if params.RefreshOnly {
+		args = append(args, "-refresh-only")
+	}
+	return append(args, params.ExtraArgs...)
+}
+
+func applyArgs(params *ApplyParams) []string {
+	args := []string{"apply", "-no-color", "-input=false", "-auto-approve"}
+	args = append(args, params.ExtraArgs...)
+	if params.PlanFilePath != "" {
+		args = append(args, params.PlanFilePath)
+	}
+	return args
 }
 
 func (t *terraform) toOutput(ret tfcmt.ParseResult, rawLog string) *Output {
@@ -125,3 +125,49 @@ func TestTerraform_Plan_Apply(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.", apply.Result)
 }
+
+func TestArgs(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		args   []string
+		expect []string
+	}{
+		"init": {
+			args: initArgs(&InitParams{
+				BackendConfig:     map[string]string{"key": "state", "bucket": "tfstate"},
+				BackendConfigPath: "backend.hcl",
+				ExtraArgs:         []string{"-upgrade"},
+			}),
+			expect: []string{
+				"init", "-no-color", "-input=false", "-backend-config=backend.hcl",
+				"-backend-config=bucket=tfstate", "-backend-config=key=state", "-reconfigure", "-upgrade",
+			},
+		},
+		"plan": {
+			args: planArgs(&PlanParams{
+				Vars:      []string{"name=test"},
+				VarFiles:  []string{"prod.tfvars"},
+				Out:       "test.tfplan",
+				Destroy:   true,
+				ExtraArgs: []string{"-parallelism=5"},
+			}),
+			expect: []string{
+				"plan", "-no-color", "-input=false", "-detailed-exitcode", "-var=name=test",
+				"-var-file=prod.tfvars", "-out=test.tfplan", "-destroy", "-parallelism=5",
+			},
+		},
+		"apply": {
+			args: applyArgs(&ApplyParams{
+				PlanFilePath: "test.tfplan",
+				ExtraArgs:    []string{"-parallelism=5"},
+			}),
+			expect: []string{"apply", "-no-color", "-input=false", "-auto-approve", "-parallelism=5", "test.tfplan"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expect, tt.args)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Package terragrunt finds the Terragrunt units in a repository and the dependencies between them.
+package terragrunt
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ConfigFile is the configuration file of a Terragrunt unit.
+const ConfigFile = "terragrunt.hcl"
+
+// The directories which are never units, e.g. the caches of Terragrunt and Terraform.
+var skipDirs = []string{".git", ".terraform", ".terragrunt-cache"}
+
+// Discover returns the directories under root which have ConfigFile, relative to root in slash-separated paths.
+// root itself is not a unit, since its ConfigFile is the parent configuration included by the units.
+func Discover(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if slices.Contains(skipDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != ConfigFile {
+			return nil
+		}
+		dir, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if dir != "." {
+			dirs = append(dirs, filepath.ToSlash(dir))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(dirs)
+	return dirs, nil
+}
+
+var (
+	commentRegex      = regexp.MustCompile(`(?m)(^|\s)(#|//).*$|/\*(?s:.*?)\*/`)
+	configPathRegex   = regexp.MustCompile(`(?m)^\s*config_path\s*=\s*"([^"$]+)"`)
+	dependenciesRegex = regexp.MustCompile(`(?s)dependencies\s*\{\s*paths\s*=\s*\[(.*?)\]`)
+	stringRegex       = regexp.MustCompile(`"([^"$]+)"`)
+)
+
+// Dependencies returns the directories of the units which the unit in dir depends on, i.e. config_path of
+// the dependency blocks and paths of the dependencies block, which are joined to dir and cleaned.
+// Only the string literals are read, so the paths built by functions or interpolations are ignored.
+func Dependencies(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ConfigFile))
+	if err != nil {
+		return nil, err
+	}
+	src := commentRegex.ReplaceAllString(string(data), "$1")
+
+	var paths []string
+	for _, match := range configPathRegex.FindAllStringSubmatch(src, -1) {
+		paths = append(paths, match[1])
+	}
+	for _, match := range dependenciesRegex.FindAllStringSubmatch(src, -1) {
+		for _, str := range stringRegex.FindAllStringSubmatch(match[1], -1) {
+			paths = append(paths, str[1])
+		}
+	}
+
+	deps := make([]string, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.ToSlash(filepath.Clean(path))
+		if !slices.Contains(deps, path) {
+			deps = append(deps, path)
+		}
+	}
+	return deps, nil
+}
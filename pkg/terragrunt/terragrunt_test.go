@@ -0,0 +1,44 @@
+package terragrunt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+	dirs, err := Discover("testdata/live")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod/app", "prod/db", "prod/vpc"}, dirs)
+}
+
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		dir    string
+		expect []string
+	}{
+		"dependency blocks and dependencies": {
+			dir:    "testdata/live/prod/app",
+			expect: []string{"testdata/live/prod/vpc", "testdata/live/prod/db"},
+		},
+		"dependency block": {
+			dir:    "testdata/live/prod/db",
+			expect: []string{"testdata/live/prod/vpc"},
+		},
+		"no dependencies": {
+			dir:    "testdata/live/prod/vpc",
+			expect: []string{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			deps, err := Dependencies(tt.dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, deps)
+		})
+	}
+}
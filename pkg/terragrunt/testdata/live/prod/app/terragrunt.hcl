@@ -0,0 +1,26 @@
+include "root" {
+  path = find_in_parent_folders()
+}
+
+# dependency "old" {
+#   config_path = "../old"
+# }
+
+dependency "vpc" {
+  config_path = "../vpc"
+
+  mock_outputs = {
+    vpc_id = "vpc-00000000"
+  }
+}
+
+dependency "shared" {
+  config_path = "${get_terragrunt_dir()}/../shared"
+}
+
+dependencies {
+  paths = [
+    "../db", // the database must be migrated first
+    "../vpc",
+  ]
+}
@@ -0,0 +1,106 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// dryRun reads from the wrapped VCS as usual,
+// but prints every comment, label, status and other change to w instead of calling the GitHub API.
+type dryRun struct {
+	VCS
+	w      io.Writer
+	locker sync.Mutex
+}
+
+// NewDryRun wraps v so that mu can be run against a real pull request without changing it.
+func NewDryRun(v VCS, w io.Writer) VCS {
+	return &dryRun{
+		VCS: v,
+		w:   w,
+	}
+}
+
+func (d *dryRun) print(format string, args ...any) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	_, _ = fmt.Fprintf(d.w, "[dry-run] "+format+"\n", args...)
+}
+
+func (d *dryRun) printBody(header, body string) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	_, _ = fmt.Fprintf(d.w, "[dry-run] %s\n", header)
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		_, _ = fmt.Fprintf(d.w, "  | %s\n", line)
+	}
+}
+
+func (d *dryRun) CreateComment(_ context.Context, number int, body string) error {
+	d.printBody(fmt.Sprintf("create comment on #%d", number), body)
+	return nil
+}
+
+func (d *dryRun) HideComment(_ context.Context, id string) error {
+	d.print("hide comment %s", id)
+	return nil
+}
+
+func (d *dryRun) CreateCommentReaction(_ context.Context, commentID int64, content string) error {
+	d.print("add reaction %q to comment %d", content, commentID)
+	return nil
+}
+
+func (d *dryRun) CreateLabel(_ context.Context, name, description, color string) error {
+	d.print("create label %q (description=%q, color=%s)", name, description, color)
+	return nil
+}
+
+func (d *dryRun) DeleteLabel(_ context.Context, label string) error {
+	d.print("delete label %q", label)
+	return nil
+}
+
+func (d *dryRun) AddPullRequestLabels(_ context.Context, number int, labels []string) error {
+	d.print("add labels %q to #%d", labels, number)
+	return nil
+}
+
+func (d *dryRun) CreateCommitStatus(_ context.Context, commitStatus *CommitStatus) error {
+	d.print("set status %q to %s on %s (description=%q, url=%s)",
+		commitStatus.Context, commitStatus.Status, commitStatus.Sha, commitStatus.Desc, commitStatus.TargetURL)
+	return nil
+}
+
+func (d *dryRun) CommitFiles(_ context.Context, params *CommitFilesParams) (string, error) {
+	for path, content := range params.Files {
+		d.printBody(fmt.Sprintf("commit %s to %s (message=%q)", path, params.Branch, params.Message), content)
+	}
+	return "", nil
+}
+
+func (d *dryRun) CreateIssue(_ context.Context, title, body string, labels []string) (*Issue, error) {
+	d.printBody(fmt.Sprintf("create issue %q with labels %q", title, labels), body)
+	return &Issue{
+		Title: title,
+		Body:  body,
+	}, nil
+}
+
+func (d *dryRun) UpdateIssueBody(_ context.Context, number int, body string) error {
+	d.printBody(fmt.Sprintf("update body of #%d", number), body)
+	return nil
+}
+
+func (d *dryRun) CloseIssue(_ context.Context, number int) error {
+	d.print("close #%d", number)
+	return nil
+}
+
+func (d *dryRun) MergePullRequest(_ context.Context, params *MergePullRequestParams) error {
+	d.print("merge #%d at %s (method=%s, delete_branch=%t)", params.Number, params.SHA, params.Method, params.DeleteBranch)
+	return nil
+}
@@ -0,0 +1,52 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type readOnlyVCS struct {
+	VCS
+	pr *PullRequest
+}
+
+func (r *readOnlyVCS) GetPullRequest(_ context.Context, _ int) (*PullRequest, error) {
+	return r.pr, nil
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	pr := &PullRequest{Number: 1, HeadSHA: "sha"}
+	buf := &bytes.Buffer{}
+	// The embedded VCS is nil except GetPullRequest, so any write that reaches it panics.
+	v := NewDryRun(&readOnlyVCS{pr: pr}, buf)
+
+	actual, err := v.GetPullRequest(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, pr, actual)
+
+	require.NoError(t, v.CreateComment(ctx, 1, "### plan\nok"))
+	require.NoError(t, v.CreateLabel(ctx, "mu_lock_test", "desc", "ffffff"))
+	require.NoError(t, v.AddPullRequestLabels(ctx, 1, []string{"mu_lock_test"}))
+	require.NoError(t, v.CreateCommitStatus(ctx, &CommitStatus{
+		Sha:     "sha",
+		Status:  SuccessStatus,
+		Desc:    "Plan succeeded",
+		Context: "mu/plan: test",
+	}))
+	require.NoError(t, v.DeleteLabel(ctx, "mu_lock_test"))
+
+	expected := `[dry-run] create comment on #1
+  | ### plan
+  | ok
+[dry-run] create label "mu_lock_test" (description="desc", color=ffffff)
+[dry-run] add labels ["mu_lock_test"] to #1
+[dry-run] set status "mu/plan: test" to success on sha (description="Plan succeeded", url=)
+[dry-run] delete label "mu_lock_test"
+`
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,12 @@
+package vcs
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when the pull request, label or issue does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when the label to create already exists.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrOutdated is returned when the branch to commit has moved from the expected head.
+	ErrOutdated = errors.New("outdated")
+)
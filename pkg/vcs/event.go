@@ -0,0 +1,81 @@
+package vcs
+
+const (
+	Opened      = "opened"
+	Synchronize = "synchronize"
+	Reopened    = "reopened"
+	Closed      = "closed"
+	Created     = "created"
+	Submitted   = "submitted"
+)
+
+// ReviewApproved is the state of the review which approves the pull request.
+const ReviewApproved = "approved"
+
+type Event interface {
+	Number() int
+}
+
+// PullRequestEvent is triggered when a pull request is opened, updated, reopened or closed.
+type PullRequestEvent struct {
+	Action            string
+	PullRequestNumber int
+	// User is the login of the user who triggered the event.
+	User string
+}
+
+func (e *PullRequestEvent) Number() int {
+	return e.PullRequestNumber
+}
+
+// CommentEvent is triggered when a comment is created on a pull request.
+type CommentEvent struct {
+	Action            string
+	PullRequestNumber int
+	// CommentID is 0 if the command is not from a real comment, e.g. `mu exec`.
+	CommentID int64
+	Body      string
+	// User is the login of the user who posted the comment.
+	User string
+}
+
+func (e *CommentEvent) Number() int {
+	return e.PullRequestNumber
+}
+
+// ReviewEvent is triggered when a review is submitted to a pull request.
+type ReviewEvent struct {
+	Action            string
+	PullRequestNumber int
+	// State is the lowercase state of the review, e.g. "approved".
+	State string
+	// User is the login of the reviewer.
+	User string
+}
+
+func (e *ReviewEvent) Number() int {
+	return e.PullRequestNumber
+}
+
+// DriftEvent is triggered by a scheduled or manually run pipeline to detect the drift.
+// It is not related to any pull request, so Number always returns 0.
+type DriftEvent struct{}
+
+func (e *DriftEvent) Number() int {
+	return 0
+}
+
+// PushEvent is triggered when commits are pushed to a branch.
+// It is not related to any pull request, so Number always returns 0.
+type PushEvent struct {
+	// SHA is the commit after the push.
+	SHA string
+	// DefaultBranch reports whether the push targets the default branch of the repository.
+	DefaultBranch bool
+	// User is the login of the user who pushed the commits.
+	User string
+}
+
+func (e *PushEvent) Number() int {
+	return 0
+}
@@ -0,0 +1,219 @@
+// Package vcs defines the interface of the version control systems which host the pull requests, such as GitHub and GitLab.
+// A pull request means a merge request on GitLab, and a comment means a note.
+package vcs
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxCommentLen is the maximum length of a comment which every VCS accepts.
+const MaxCommentLen = 65536
+
+//go:generate mkdir -p mock
+//go:generate mockgen -source=vcs.go -package=mock -destination=mock/mock.go VCS
+
+type VCS interface {
+	// Comments
+	CreateComment(ctx context.Context, number int, body string) error
+	// HideComment collapses the outdated comment. It does nothing if the VCS cannot hide comments.
+	HideComment(ctx context.Context, id string) error
+	CreateCommentReaction(ctx context.Context, commentID int64, content string) error
+	ListComments(ctx context.Context, number int) ([]*Comment, error)
+
+	// Commit statuses
+	CreateCommitStatus(ctx context.Context, commitStatus *CommitStatus) error
+	// ListCommitStatuses returns the latest status of each context on the commit.
+	ListCommitStatuses(ctx context.Context, sha string) ([]*CommitStatus, error)
+
+	// Pull requests, changed files and approvals
+	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+	FindMergedPullRequest(ctx context.Context, sha string) (*PullRequest, error)
+	ListFiles(ctx context.Context, number int) ([]string, error)
+	ListReviews(ctx context.Context, number int) (Reviews, error)
+	MergePullRequest(ctx context.Context, params *MergePullRequestParams) error
+
+	// Locks, which are labels attached to the pull requests
+	CreateLabel(ctx context.Context, name, description, color string) error
+	DeleteLabel(ctx context.Context, label string) error
+	GetLabel(ctx context.Context, label string) (*Label, error)
+	AddPullRequestLabels(ctx context.Context, number int, labels []string) error
+	ListPullRequestsByLabel(ctx context.Context, label string, limit int) ([]*PullRequest, error)
+	FindPullRequestByLabel(ctx context.Context, label string) (*PullRequest, error)
+
+	// Issues, which report the drift
+	FindIssueByLabel(ctx context.Context, label string) (*Issue, error)
+	CreateIssue(ctx context.Context, title, body string, labels []string) (*Issue, error)
+	UpdateIssueBody(ctx context.Context, number int, body string) error
+	CloseIssue(ctx context.Context, number int) error
+
+	// Permissions of the users who run the commands
+	// GetPermissionLevel returns the permission level of the user on the repository, one of the Permission constants.
+	GetPermissionLevel(ctx context.Context, user string) (string, error)
+	// IsTeamMember reports whether the user is a member of the team,
+	// which is "org/team" or "team" of the owner of the repository (a group and its subgroups on GitLab).
+	IsTeamMember(ctx context.Context, team, user string) (bool, error)
+
+	// CommitFiles commits the files to the branch, and returns the SHA of the commit.
+	CommitFiles(ctx context.Context, params *CommitFilesParams) (string, error)
+	// GetFileContent returns the content of the file at the ref, which is a branch, a tag or a SHA.
+	// The path is relative to the repository root. It returns ErrNotFound if the file does not exist.
+	GetFileContent(ctx context.Context, path, ref string) ([]byte, error)
+	// ListRepositoryFiles returns the paths of the files under dir at the ref, which is a branch, a tag or a SHA.
+	// The dir and the paths are relative to the repository root, and "." means the root.
+	ListRepositoryFiles(ctx context.Context, dir, ref string) ([]string, error)
+
+	// Event returns the event which triggered the current run.
+	Event() (Event, error)
+}
+
+type PullRequest struct {
+	ID             int64
+	Number         int
+	Title          string
+	CreatedAt      time.Time
+	HeadSHA        string
+	HeadRef        string
+	BaseSHA        string
+	BaseRef        string
+	Fork           bool
+	MergeableState string
+	Labels         []*Label
+}
+
+// IsMergeable
+// See: https://github.com/octokit/octokit.net/issues/1763
+func (p *PullRequest) IsMergeable() bool {
+	switch p.MergeableState {
+	case "clean", "unstable", "has_hooks":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *PullRequest) HasLabel(labelName string) bool {
+	for _, label := range p.Labels {
+		if label.Name == labelName {
+			return true
+		}
+	}
+	return false
+}
+
+type Label struct {
+	Name        string
+	Description string
+}
+
+type Issue struct {
+	Number int
+	Title  string
+	Body   string
+}
+
+type Comment struct {
+	ID         string
+	DatabaseID int64
+	Body       string
+	Author     struct {
+		Login string
+	}
+	CreatedAt         string
+	IsMinimized       bool
+	ViewerCanMinimize bool
+}
+
+type Review struct {
+	UserLogin string
+	State     string
+}
+
+type Reviews []*Review
+
+func (rs Reviews) Approves() int {
+	var num int
+	for _, r := range rs {
+		switch strings.ToLower(r.State) {
+		case "approve", ReviewApproved:
+			num++
+		}
+	}
+	return num
+}
+
+// The permission levels of the repository collaborators, from the lowest.
+// GitLab roles are mapped to them: Guest and Reporter to read, Developer to write, Maintainer to maintain and Owner to admin.
+const (
+	PermissionNone     = "none"
+	PermissionRead     = "read"
+	PermissionTriage   = "triage"
+	PermissionWrite    = "write"
+	PermissionMaintain = "maintain"
+	PermissionAdmin    = "admin"
+)
+
+var permissionLevels = []string{
+	PermissionNone, PermissionRead, PermissionTriage, PermissionWrite, PermissionMaintain, PermissionAdmin,
+}
+
+// HasPermission reports whether the permission level is equal to or higher than required.
+// Unknown levels are treated as none.
+func HasPermission(level, required string) bool {
+	return slices.Index(permissionLevels, level) >= slices.Index(permissionLevels, required)
+}
+
+type CommitStatus struct {
+	Sha       string
+	Status    Status
+	TargetURL string
+	Desc      string
+	Context   string
+}
+
+type CommitFilesParams struct {
+	Branch string
+	// HeadSHA must match the head of the branch to commit, otherwise ErrOutdated is returned.
+	// The head is not checked if it is empty.
+	HeadSHA string
+	Message string
+	// Files maps a path relative to the repository root to its content.
+	Files map[string]string
+}
+
+type MergePullRequestParams struct {
+	Number int
+	// SHA must match the head of the pull request to allow merge.
+	SHA string
+	// Method is one of "merge", "squash" and "rebase".
+	Method string
+	// DeleteBranch deletes the head branch after merging.
+	DeleteBranch bool
+	Branch       string
+}
+
+type Status int
+
+const (
+	ErrorStatus = iota
+	FailureStatus
+	PendingStatus
+	SuccessStatus
+)
+
+func (s Status) String() string {
+	switch s {
+	case ErrorStatus:
+		return "error"
+	case FailureStatus:
+		return "failure"
+	case PendingStatus:
+		return "pending"
+	case SuccessStatus:
+		return "success"
+	default:
+		return ""
+	}
+}
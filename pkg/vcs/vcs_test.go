@@ -0,0 +1,18 @@
+package vcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviews_Approves(t *testing.T) {
+	t.Parallel()
+	reviews := Reviews{
+		{UserLogin: "alice", State: "APPROVED"},
+		{UserLogin: "bob", State: "approve"},
+		{UserLogin: "carol", State: "CHANGES_REQUESTED"},
+		{UserLogin: "dave", State: "COMMENTED"},
+	}
+	assert.Equal(t, 2, reviews.Approves())
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yu-icchi/mu/pkg/action"
+	"github.com/yu-icchi/mu/pkg/app"
+	"github.com/yu-icchi/mu/pkg/github"
+	"github.com/yu-icchi/mu/pkg/log"
+	"github.com/yu-icchi/mu/pkg/server"
+	"github.com/yu-icchi/mu/pkg/vcs"
+)
+
+// runServer runs `mu server`, which handles the webhook events of GitHub without GitHub Actions.
+// The secrets are read from the environment variables, MU_WEBHOOK_SECRET and MU_GITHUB_TOKEN.
+func runServer(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	path := flags.String("path", "/events", "path to receive the webhook events")
+	workspaceDir := flags.String("workspace-dir", filepath.Join(os.TempDir(), "mu"), "directory to cache the checked out pull requests")
+	configPath := flags.String("config-path", ".github/mu.yaml", "file path of YAML manifest for mu in the repository, which is read from the default branch")
+	allowCommands := flags.String("allow-commands", "plan,apply,unlock", "comma-separated list of allowed commands")
+	defaultTerraformVersion := flags.String("default-terraform-version", "latest", "terraform version to default")
+	emojiReaction := flags.String("emoji-reaction", "+1", "emoji reaction")
+	botName := flags.String("bot-name", "", botNameUsage)
+	allowForks := flags.Bool("allow-forks", false, "handle the pull requests from the forks, which run their code with the credentials of the server")
+	storeOpts := &planStoreOptions{}
+	registerPlanStoreFlags(flags, storeOpts, "", "directory to keep the plan files when plan-store is local (default <workspace-dir>/plans)")
+	auditOpts := &auditOptions{}
+	registerAuditFlags(flags, auditOpts)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	secret := os.Getenv("MU_WEBHOOK_SECRET")
+	if secret == "" {
+		return errors.New("MU_WEBHOOK_SECRET is required")
+	}
+	token := os.Getenv("MU_GITHUB_TOKEN")
+	if token == "" {
+		return errors.New("MU_GITHUB_TOKEN is required")
+	}
+	switch storeOpts.backend {
+	case "github":
+		return errors.New("plan-store github is not available in server mode")
+	case "local":
+		if storeOpts.dir == "" {
+			storeOpts.dir = filepath.Join(*workspaceDir, "plans")
+		}
+	}
+	login, err := resolveBotName(ctx, *botName, token)
+	if err != nil {
+		return err
+	}
+	encrypter := newEncrypter("")
+	signingKey := planSigningKey("", "")
+	logger := log.New(os.Stdout)
+
+	newClient := func(ctx context.Context, owner, repo string) (github.Github, error) {
+		gh, err := github.New(ctx, token, owner, repo, github.WithURLs(github.URLsFromEnv()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup github client: %w", err)
+		}
+		return gh, nil
+	}
+	dispatcher := func(ctx context.Context, req *server.Request) error {
+		gh, err := newClient(ctx, req.Owner, req.Repo)
+		if err != nil {
+			return err
+		}
+		planStore, err := newPlanStore(gh, storeOpts)
+		if err != nil {
+			return err
+		}
+		auditSinks, err := newAuditSinks(auditOpts, gh)
+		if err != nil {
+			return err
+		}
+		mu := app.New(&app.Params{
+			VCS:                     gh,
+			PlanStore:               planStore,
+			Encrypter:               encrypter,
+			PlanSigningKey:          signingKey,
+			ConfigPath:              filepath.Join(req.ConfigDir, *configPath),
+			DefaultTerraformVersion: *defaultTerraformVersion,
+			AllowCommands:           strings.Split(strings.ToLower(*allowCommands), ","),
+			DisableSummaryLog:       true,
+			EmojiReaction:           *emojiReaction,
+			WorkDir:                 req.Dir,
+			ConfigDir:               req.ConfigDir,
+			ProjectLocker:           req.Locker,
+			BotName:                 login,
+			AuditSinks:              auditSinks,
+			Release: &app.Release{
+				Version: version,
+				Commit:  commit,
+				Date:    date,
+			},
+		})
+		return mu.ExecuteEvent(ctx, github.ConvertEvent(req.Event))
+	}
+	muServer := server.New(&server.Params{
+		Secret: secret,
+		Workspace: server.NewGitWorkspace(&server.GitWorkspaceParams{
+			Root:    *workspaceDir,
+			BaseURL: action.ServerURL(),
+			Token:   token,
+		}),
+		Dispatcher: dispatcher,
+		NewVCS: func(ctx context.Context, owner, repo string) (vcs.VCS, error) {
+			return newClient(ctx, owner, repo)
+		},
+		AllowForks: *allowForks,
+		Logger:     logger,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(*path, muServer)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("mu server is listening", log.String("addr", *addr), log.String("path", *path))
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	// The running events are not interrupted, since an interrupted terraform apply may leave the state locked.
+	logger.Info("waiting for the running events")
+	muServer.Wait()
+	return nil
+}